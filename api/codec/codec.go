@@ -0,0 +1,47 @@
+// Package codec reads and writes the protometry messages, or any other protobuf message, in the canonical
+// protobuf JSON and text formats, for configs and logs
+package codec
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// MarshalJSON returns the canonical protobuf JSON of m on one line, fields named in lowerCamelCase and
+// the ones of default value omitted
+func MarshalJSON(m proto.Message) ([]byte, error) {
+	return MarshalJSONIndent(m, "")
+}
+
+// MarshalJSONIndent returns the canonical protobuf JSON of m, each field on its own line indented with
+// indent, or on one line when indent is empty
+func MarshalJSONIndent(m proto.Message, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{Indent: indent}).Marshal(&buf, m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads protobuf JSON into m, accepting both the lowerCamelCase and the original field
+// names and failing on unknown ones
+func UnmarshalJSON(data []byte, m proto.Message) error {
+	return jsonpb.Unmarshal(bytes.NewReader(data), m)
+}
+
+// MarshalText returns the protobuf text format of m, each field on its own line
+func MarshalText(m proto.Message) []byte {
+	return []byte(proto.MarshalTextString(m))
+}
+
+// MarshalCompactText returns the protobuf text format of m on one line
+func MarshalCompactText(m proto.Message) []byte {
+	return []byte(proto.CompactTextString(m))
+}
+
+// UnmarshalText reads the protobuf text format into m
+func UnmarshalText(data []byte, m proto.Message) error {
+	return proto.UnmarshalText(string(data), m)
+}
@@ -0,0 +1,58 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	data, err := MarshalJSON(vector3.NewVector3(1, 0, -2.5))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, `{"x":1,"z":-2.5}`, string(data))
+
+	data, err = MarshalJSON(volume.NewBoxMinMax(0, 0, 0, 1, 1, 1))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, `{"min":{},"max":{"x":1,"y":1,"z":1}}`, string(data))
+
+	a, _ := volume.NewAnyVolume(&volume.Sphere{Center: vector3.NewVector3(0, 1, 0), Radius: 2})
+	data, err = MarshalJSONIndent(a, "  ")
+	utils.Equals(t, nil, err)
+	utils.Equals(t, "{\n  \"sphere\": {\n    \"center\": {\n      \"y\": 1\n    },\n    \"radius\": 2\n  }\n}", string(data))
+
+	var got volume.AnyVolume
+	utils.Equals(t, nil, UnmarshalJSON(data, &got))
+	utils.Equals(t, true, proto.Equal(a, &got))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var q quaternion.Quaternion
+	utils.Equals(t, nil, UnmarshalJSON([]byte(`{"x": 0.5, "w": "NaN"}`), &q))
+	utils.Equals(t, 0.5, q.X)
+	utils.Equals(t, true, q.W != q.W)
+
+	var m volume.Mesh
+	utils.Equals(t, nil, UnmarshalJSON([]byte(`{"vertices": [{"x": 1}, {}], "tris": [0, 1, 1]}`), &m))
+	utils.Equals(t, 2, len(m.Vertices))
+	utils.Equals(t, []int32{0, 1, 1}, m.Tris)
+
+	utils.Equals(t, true, UnmarshalJSON([]byte(`{"w": 1}`), &volume.Box{}) != nil)
+	utils.Equals(t, true, UnmarshalJSON([]byte(`{"x": `), &vector3.Vector3{}) != nil)
+}
+
+func TestMarshalText(t *testing.T) {
+	b := volume.NewBoxMinMax(0, 0, 0, 1, 2, 3)
+	utils.Equals(t, "min: <\n>\nmax: <\n  x: 1\n  y: 2\n  z: 3\n>\n", string(MarshalText(b)))
+	utils.Equals(t, "min:<> max:<x:1 y:2 z:3 > ", string(MarshalCompactText(b)))
+
+	for _, data := range [][]byte{MarshalText(b), MarshalCompactText(b)} {
+		var got volume.Box
+		utils.Equals(t, nil, UnmarshalText(data, &got))
+		utils.Equals(t, true, proto.Equal(b, &got))
+	}
+	utils.Equals(t, true, UnmarshalText([]byte("min: <"), &volume.Box{}) != nil)
+}
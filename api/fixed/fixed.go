@@ -0,0 +1,329 @@
+package fixed
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// Fixed is a Q32.32 fixed point number, the integer value of the number times 2^32
+// Its operations only use integer arithmetic so they give the same bits on every platform. Addition and
+// subtraction are the ones of int64, the other operations round to the nearest and saturate on overflow
+type Fixed int64
+
+const (
+	// FracBits is the number of bits of the fraction
+	FracBits = 32
+	// One is 1
+	One Fixed = 1 << FracBits
+	// Half is 0.5
+	Half Fixed = One / 2
+	// Pi is π
+	Pi Fixed = 13493037705
+	// TwoPi is 2π
+	TwoPi Fixed = 26986075409
+	// HalfPi is π/2
+	HalfPi Fixed = 6746518852
+	// MaxValue is the largest number, a bit under 2^31
+	MaxValue Fixed = math.MaxInt64
+	// MinValue is the smallest number, -2^31
+	MinValue Fixed = math.MinInt64
+)
+
+// FromInt returns i as a fixed point number, it overflows outside of [-2^31, 2^31)
+func FromInt(i int64) Fixed {
+	return Fixed(i << FracBits)
+}
+
+// FromFloat returns the fixed point number closest to f, saturated, 0 for NaN
+// The conversion is exact for the numbers that fit, so it is deterministic too
+func FromFloat(f float64) Fixed {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= 1<<31:
+		return MaxValue
+	case f <= -1<<31:
+		return MinValue
+	}
+	return Fixed(math.Round(f * (1 << FracBits)))
+}
+
+// Float returns f as a float64, exact as long as f has no more than 53 significant bits
+func (f Fixed) Float() float64 {
+	return float64(f) / (1 << FracBits)
+}
+
+// Int returns the integer part of f, rounded down
+func (f Fixed) Int() int64 {
+	return int64(f) >> FracBits
+}
+
+// String writes f as its float64 value
+func (f Fixed) String() string {
+	return utils.FormatFloat(f.Float())
+}
+
+// Abs returns the absolute value of f, MaxValue for MinValue
+func (f Fixed) Abs() Fixed {
+	if f < 0 {
+		if f == MinValue {
+			return MaxValue
+		}
+		return -f
+	}
+	return f
+}
+
+// abs returns the absolute value of f as an unsigned integer and whether f is negative
+func (f Fixed) abs() (uint64, bool) {
+	if f < 0 {
+		return uint64(-f), true
+	}
+	return uint64(f), false
+}
+
+// saturate returns the magnitude m with a sign, MaxValue or MinValue when it doesn't fit
+func saturate(m uint64, negative bool) Fixed {
+	if negative {
+		if m >= 1<<63 {
+			return MinValue
+		}
+		return -Fixed(m)
+	}
+	if m > math.MaxInt64 {
+		return MaxValue
+	}
+	return Fixed(m)
+}
+
+// Mul returns f * g
+func (f Fixed) Mul(g Fixed) Fixed {
+	a, na := f.abs()
+	b, nb := g.abs()
+	hi, lo := bits.Mul64(a, b)
+	if hi >= 1<<(63-FracBits) {
+		return saturate(math.MaxUint64, na != nb)
+	}
+	m := hi<<(64-FracBits) | lo>>FracBits
+	// Rounding half away from zero, symmetric for the negatives
+	m += lo >> (FracBits - 1) & 1
+	return saturate(m, na != nb)
+}
+
+// Div returns f / g, saturated towards the sign of f when g is 0, and 0 for 0 / 0
+func (f Fixed) Div(g Fixed) Fixed {
+	a, na := f.abs()
+	b, nb := g.abs()
+	if b == 0 {
+		if a == 0 {
+			return 0
+		}
+		return saturate(math.MaxUint64, na)
+	}
+	hi, lo := a>>(64-FracBits), a<<FracBits
+	if hi >= b {
+		return saturate(math.MaxUint64, na != nb)
+	}
+	q, r := bits.Div64(hi, lo, b)
+	// r >= b - r, without overflowing 2r
+	if r >= b-r {
+		q++
+	}
+	return saturate(q, na != nb)
+}
+
+// sqrt128 returns the square root of the 128 bits integer hi:lo rounded to the nearest
+func sqrt128(hi, lo uint64) uint64 {
+	if hi == 0 && lo == 0 {
+		return 0
+	}
+	n := 128 - bits.LeadingZeros64(hi)
+	if hi == 0 {
+		n = 64 - bits.LeadingZeros64(lo)
+	}
+	if n > 126 {
+		// Too large for the estimate below to fit, 2 bits less precise
+		r := sqrt128(hi>>2, lo>>2|hi<<62)
+		return r << 1
+	}
+	// Newton's iterations decrease from an estimate above the root to its floor
+	x := uint64(1) << uint((n+1)/2)
+	for {
+		q, _ := bits.Div64(hi, lo, x)
+		y := (x + q) / 2
+		if y >= x {
+			break
+		}
+		x = y
+	}
+	// Round up when hi:lo - x² > x, (x + 1/2)² being x² + x + 1/4
+	sh, sl := bits.Mul64(x, x)
+	dl, borrow := bits.Sub64(lo, sl, 0)
+	dh, _ := bits.Sub64(hi, sh, borrow)
+	if dh > 0 || dl > x {
+		x++
+	}
+	return x
+}
+
+// Sqrt returns the square root of f, 0 for the negatives
+func (f Fixed) Sqrt() Fixed {
+	if f <= 0 {
+		return 0
+	}
+	return Fixed(sqrt128(uint64(f)>>(64-FracBits), uint64(f)<<FracBits))
+}
+
+const (
+	// cordicBits is the number of bits of the fraction of the vectors and angles of CORDIC, more precise
+	// than Fixed so that the rounding of the iterations doesn't show
+	cordicBits = 60
+	// cordicGain is the inverse of the growth of the vectors rotated by CORDIC
+	cordicGain = 700114967507363239
+	// cordicHalfPi is π/2
+	cordicHalfPi = 1811004864519280711
+)
+
+// cordicAtan holds atan(2^-i) for the iterations of CORDIC, with cordicBits of fraction
+var cordicAtan = [32]int64{
+	905502432259640355, 534549298976576474, 282441168888798124, 143371547418228444,
+	71963988336308046, 36017075762092179, 18012932708689205, 9007016009513623,
+	4503576721087964, 2251796950380271, 1125899548928887, 562949908682076,
+	281474971118251, 140737487656277, 70368744090283, 35184372077909,
+	17592186043051, 8796093022037, 4398046511083, 2199023255549,
+	1099511627776, 549755813888, 274877906944, 137438953472,
+	68719476736, 34359738368, 17179869184, 8589934592,
+	4294967296, 2147483648, 1073741824, 536870912,
+}
+
+// fromCordic rounds a number with cordicBits of fraction to a Fixed
+func fromCordic(v int64) Fixed {
+	return Fixed((v + 1<<(cordicBits-FracBits-1)) >> (cordicBits - FracBits))
+}
+
+// cordicRotate returns the cosine and sine of an angle between -π/2 and π/2, with cordicBits of fraction
+func cordicRotate(angle Fixed) (int64, int64) {
+	x, y, z := int64(cordicGain), int64(0), int64(angle)<<(cordicBits-FracBits)
+	for i, a := range cordicAtan {
+		dx, dy := y>>uint(i), x>>uint(i)
+		if z >= 0 {
+			x, y, z = x-dx, y+dy, z-a
+		} else {
+			x, y, z = x+dx, y-dy, z+a
+		}
+	}
+	// The angle left is below 2^-31, rotating by it to first order is exact enough. The vector is shifted
+	// so that the products fit
+	return x - (y>>31)*z>>(cordicBits-31), y + (x>>31)*z>>(cordicBits-31)
+}
+
+// SinCos returns the sine and cosine of the angle in radians
+func (f Fixed) SinCos() (sin, cos Fixed) {
+	a := f % TwoPi
+	if a > Pi {
+		a -= TwoPi
+	} else if a < -Pi {
+		a += TwoPi
+	}
+	flip := false
+	if a > HalfPi {
+		a, flip = Pi-a, true
+	} else if a < -HalfPi {
+		a, flip = -Pi-a, true
+	}
+	c, s := cordicRotate(a)
+	sin, cos = fromCordic(s), fromCordic(c)
+	if flip {
+		cos = -cos
+	}
+	return sin, cos
+}
+
+// Sin returns the sine of the angle in radians
+func (f Fixed) Sin() Fixed {
+	s, _ := f.SinCos()
+	return s
+}
+
+// Cos returns the cosine of the angle in radians
+func (f Fixed) Cos() Fixed {
+	_, c := f.SinCos()
+	return c
+}
+
+// Tan returns the tangent of the angle in radians, saturated around the poles
+func (f Fixed) Tan() Fixed {
+	s, c := f.SinCos()
+	return s.Div(c)
+}
+
+// Atan2 returns the angle of the point (x, y) from the x axis, between -π and π, like math.Atan2
+func Atan2(y, x Fixed) Fixed {
+	if x == 0 && y == 0 {
+		return 0
+	}
+	// Scaled up or down so that the largest has cordicBits of magnitude, the ratio is all that matters
+	ax, _ := x.abs()
+	ay, _ := y.abs()
+	shift := cordicBits - (64 - bits.LeadingZeros64(ax|ay))
+	vx, vy := int64(x), int64(y)
+	if shift >= 0 {
+		vx, vy = vx<<uint(shift), vy<<uint(shift)
+	} else {
+		vx, vy = vx>>uint(-shift), vy>>uint(-shift)
+	}
+	// Turned by a quarter into the right half plane
+	var z int64
+	if vx < 0 {
+		if vy >= 0 {
+			vx, vy, z = vy, -vx, cordicHalfPi
+		} else {
+			vx, vy, z = -vy, vx, -cordicHalfPi
+		}
+	}
+	for i, a := range cordicAtan {
+		dx, dy := vy>>uint(i), vx>>uint(i)
+		if vy > 0 {
+			vx, vy, z = vx+dx, vy-dy, z+a
+		} else {
+			vx, vy, z = vx-dx, vy+dy, z-a
+		}
+	}
+	// The angle left is y / x to first order, below 2^-31 so that y fits shifted
+	return fromCordic(z + (vy<<(cordicBits/2-1))/(vx>>(cordicBits/2+1)))
+}
+
+// Atan returns the arctangent of f, between -π/2 and π/2
+func (f Fixed) Atan() Fixed {
+	return Atan2(f, One)
+}
+
+// Asin returns the arcsine of f, between -π/2 and π/2, f being clamped to [-1, 1]
+func (f Fixed) Asin() Fixed {
+	f = clamp(f, -One, One)
+	return Atan2(f, cosOf(f))
+}
+
+// Acos returns the arccosine of f, between 0 and π, f being clamped to [-1, 1]
+func (f Fixed) Acos() Fixed {
+	f = clamp(f, -One, One)
+	return Atan2(cosOf(f), f)
+}
+
+// cosOf returns √(1 - f²) for f in [-1, 1], the product (1 - f)(1 + f) being exact on 128 bits
+func cosOf(f Fixed) Fixed {
+	hi, lo := bits.Mul64(uint64(One-f), uint64(One+f))
+	return Fixed(sqrt128(hi, lo))
+}
+
+func clamp(f, min, max Fixed) Fixed {
+	if f < min {
+		return min
+	}
+	if f > max {
+		return max
+	}
+	return f
+}
@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: fixed.proto
+
+package fixed
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Vector3 is a vector of Q32.32 fixed point numbers, 32 bits of integer part and 32 bits of fraction,
+// for simulations that must give the same bits on every platform
+type Vector3 struct {
+	X                    int64    `protobuf:"zigzag64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    int64    `protobuf:"zigzag64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Z                    int64    `protobuf:"zigzag64,3,opt,name=z,proto3" json:"z,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Vector3) Reset()         { *m = Vector3{} }
+func (m *Vector3) String() string { return proto.CompactTextString(m) }
+func (*Vector3) ProtoMessage()    {}
+func (*Vector3) Descriptor() ([]byte, []int) {
+	return fileDescriptor_25e628d52bdac375, []int{0}
+}
+
+func (m *Vector3) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Vector3.Unmarshal(m, b)
+}
+func (m *Vector3) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Vector3.Marshal(b, m, deterministic)
+}
+func (m *Vector3) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Vector3.Merge(m, src)
+}
+func (m *Vector3) XXX_Size() int {
+	return xxx_messageInfo_Vector3.Size(m)
+}
+func (m *Vector3) XXX_DiscardUnknown() {
+	xxx_messageInfo_Vector3.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Vector3 proto.InternalMessageInfo
+
+func (m *Vector3) GetX() int64 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *Vector3) GetY() int64 {
+	if m != nil {
+		return m.Y
+	}
+	return 0
+}
+
+func (m *Vector3) GetZ() int64 {
+	if m != nil {
+		return m.Z
+	}
+	return 0
+}
+
+// Quaternion is a quaternion of Q32.32 fixed point numbers
+type Quaternion struct {
+	X                    int64    `protobuf:"zigzag64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    int64    `protobuf:"zigzag64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Z                    int64    `protobuf:"zigzag64,3,opt,name=z,proto3" json:"z,omitempty"`
+	W                    int64    `protobuf:"zigzag64,4,opt,name=w,proto3" json:"w,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Quaternion) Reset()         { *m = Quaternion{} }
+func (m *Quaternion) String() string { return proto.CompactTextString(m) }
+func (*Quaternion) ProtoMessage()    {}
+func (*Quaternion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_25e628d52bdac375, []int{1}
+}
+
+func (m *Quaternion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Quaternion.Unmarshal(m, b)
+}
+func (m *Quaternion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Quaternion.Marshal(b, m, deterministic)
+}
+func (m *Quaternion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quaternion.Merge(m, src)
+}
+func (m *Quaternion) XXX_Size() int {
+	return xxx_messageInfo_Quaternion.Size(m)
+}
+func (m *Quaternion) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quaternion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quaternion proto.InternalMessageInfo
+
+func (m *Quaternion) GetX() int64 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *Quaternion) GetY() int64 {
+	if m != nil {
+		return m.Y
+	}
+	return 0
+}
+
+func (m *Quaternion) GetZ() int64 {
+	if m != nil {
+		return m.Z
+	}
+	return 0
+}
+
+func (m *Quaternion) GetW() int64 {
+	if m != nil {
+		return m.W
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Vector3)(nil), "protometry.fixed.Vector3")
+	proto.RegisterType((*Quaternion)(nil), "protometry.fixed.Quaternion")
+}
+
+func init() {
+	proto.RegisterFile("fixed.proto", fileDescriptor_25e628d52bdac375)
+}
+
+var fileDescriptor_25e628d52bdac375 = []byte{
+	// 143 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4e, 0xcb, 0xac, 0x48,
+	0x4d, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0x00, 0x53, 0xb9, 0xa9, 0x25, 0x45, 0x95,
+	0x7a, 0x60, 0x71, 0x25, 0x63, 0x2e, 0xf6, 0xb0, 0xd4, 0xe4, 0x92, 0xfc, 0x22, 0x63, 0x21, 0x1e,
+	0x2e, 0xc6, 0x0a, 0x09, 0x46, 0x05, 0x46, 0x0d, 0xa1, 0x20, 0xc6, 0x0a, 0x10, 0xaf, 0x52, 0x82,
+	0x09, 0xc2, 0xab, 0x04, 0xf1, 0xaa, 0x24, 0x98, 0x21, 0xbc, 0x2a, 0x25, 0x17, 0x2e, 0xae, 0xc0,
+	0xd2, 0xc4, 0x92, 0xd4, 0xa2, 0xbc, 0xcc, 0xfc, 0x3c, 0xe2, 0xf5, 0x81, 0x78, 0xe5, 0x12, 0x2c,
+	0x10, 0x5e, 0xb9, 0x93, 0x25, 0x97, 0x48, 0x72, 0x7e, 0xae, 0x1e, 0xba, 0x93, 0x9c, 0x58, 0xdd,
+	0x40, 0x54, 0x00, 0x63, 0x14, 0x2b, 0x98, 0xbf, 0x8a, 0x49, 0x20, 0x00, 0xa1, 0x04, 0x2c, 0x97,
+	0xc4, 0x06, 0xd6, 0x64, 0x0c, 0x18, 0x00, 0x05, 0x45, 0x72, 0xdd, 0xdd, 0x00, 0x00, 0x00,
+}
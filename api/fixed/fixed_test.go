@@ -0,0 +1,110 @@
+package fixed
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// ulp is the smallest step of Fixed
+const ulp = 1.0 / (1 << FracBits)
+
+func near(a Fixed, b float64, ulps float64) bool {
+	return math.Abs(a.Float()-b) <= ulps*ulp
+}
+
+func TestFixed_Conversions(t *testing.T) {
+	utils.Equals(t, One, FromFloat(1))
+	utils.Equals(t, Fixed(-3)<<FracBits, FromInt(-3))
+	utils.Equals(t, Half, FromFloat(0.5))
+	utils.Equals(t, -1.25, FromFloat(-1.25).Float())
+	utils.Equals(t, int64(-2), FromFloat(-1.25).Int())
+	utils.Equals(t, int64(1), FromFloat(1.75).Int())
+	utils.Equals(t, Fixed(1), FromFloat(ulp*0.6))
+	utils.Equals(t, MaxValue, FromFloat(1e10))
+	utils.Equals(t, MinValue, FromFloat(math.Inf(-1)))
+	utils.Equals(t, Fixed(0), FromFloat(math.NaN()))
+	utils.Equals(t, "-1.25", FromFloat(-1.25).String())
+	utils.Equals(t, true, near(Pi, math.Pi, 0.5) && near(TwoPi, 2*math.Pi, 0.5) && near(HalfPi, math.Pi/2, 0.5))
+}
+
+func TestFixed_MulDiv(t *testing.T) {
+	utils.Equals(t, FromFloat(-3.75), FromFloat(1.5).Mul(FromFloat(-2.5)))
+	utils.Equals(t, FromFloat(0.6), FromFloat(1.5).Div(FromFloat(2.5)))
+	utils.Equals(t, FromFloat(-0.6), FromFloat(-1.5).Div(FromFloat(2.5)))
+	// Rounded to the nearest, symmetrically
+	utils.Equals(t, Fixed(1), Half.Mul(Fixed(1)))
+	utils.Equals(t, Fixed(-1), Half.Mul(Fixed(-1)))
+	utils.Equals(t, Fixed(1431655765), One.Div(FromInt(3)))
+	utils.Equals(t, Fixed(2863311531), FromInt(2).Div(FromInt(3)))
+	// Saturated
+	utils.Equals(t, MaxValue, FromInt(1<<20).Mul(FromInt(1<<20)))
+	utils.Equals(t, MinValue, FromInt(-1<<20).Mul(FromInt(1<<20)))
+	utils.Equals(t, MaxValue, FromInt(1<<20).Div(Fixed(1)))
+	utils.Equals(t, MinValue, FromInt(-1).Div(0))
+	utils.Equals(t, Fixed(0), Fixed(0).Div(0))
+	utils.Equals(t, MaxValue, MinValue.Abs())
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, b := FromFloat(r.Float64()*2000-1000), FromFloat(r.Float64()*2000-1000)
+		utils.Equals(t, true, near(a.Mul(b), a.Float()*b.Float(), 0.5))
+		utils.Equals(t, true, near(a.Div(b), a.Float()/b.Float(), 0.5))
+	}
+}
+
+func TestFixed_Sqrt(t *testing.T) {
+	utils.Equals(t, FromInt(3), FromInt(9).Sqrt())
+	utils.Equals(t, Half, FromFloat(0.25).Sqrt())
+	utils.Equals(t, Fixed(0), FromInt(-4).Sqrt())
+	utils.Equals(t, Fixed(65536), Fixed(1).Sqrt())
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		f := Fixed(r.Int63())
+		utils.Equals(t, true, near(f.Sqrt(), math.Sqrt(f.Float()), 0.5))
+	}
+	utils.Equals(t, true, near(MaxValue.Sqrt(), math.Sqrt(MaxValue.Float()), 0.5))
+}
+
+func TestFixed_Trigonometry(t *testing.T) {
+	utils.Equals(t, Fixed(0), Fixed(0).Sin())
+	utils.Equals(t, One, Fixed(0).Cos())
+	utils.Equals(t, One, HalfPi.Sin())
+	utils.Equals(t, Pi, Atan2(0, -One))
+	utils.Equals(t, -HalfPi, Atan2(-One, 0))
+	utils.Equals(t, Fixed(0), Atan2(0, 5))
+	utils.Equals(t, Fixed(0), Atan2(0, 0))
+	utils.Equals(t, Fixed(0), One.Acos())
+	utils.Equals(t, Pi, FromInt(-2).Acos())
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 2000; i++ {
+		// Angles past π/2 lose a bit to the rounding of π
+		a := FromFloat(r.Float64()*40 - 20)
+		s, c := a.SinCos()
+		utils.Equals(t, true, near(s, math.Sin(a.Float()), 1.5))
+		utils.Equals(t, true, near(c, math.Cos(a.Float()), 1.5))
+		a = FromFloat(r.Float64()*3 - 1.5)
+		utils.Equals(t, true, near(a.Sin(), math.Sin(a.Float()), 0.5))
+		utils.Equals(t, true, near(a.Cos(), math.Cos(a.Float()), 0.5))
+
+		x, y := FromFloat(r.Float64()*200-100), FromFloat(r.Float64()*200-100)
+		utils.Equals(t, true, near(Atan2(y, x), math.Atan2(y.Float(), x.Float()), 0.5))
+		u := FromFloat(r.Float64()*2 - 1)
+		utils.Equals(t, true, near(u.Atan(), math.Atan(u.Float()), 0.5))
+		utils.Equals(t, true, near(u.Asin(), math.Asin(u.Float()), 1))
+		utils.Equals(t, true, near(u.Acos(), math.Acos(u.Float()), 1))
+	}
+	utils.Equals(t, true, near(Atan2(MaxValue, MinValue), 3*math.Pi/4, 0.5))
+	utils.Equals(t, true, near(Atan2(1, FromInt(1<<30)), 0, 0.5))
+	utils.Equals(t, true, near(FromFloat(0.5).Tan(), math.Tan(0.5), 1))
+}
+
+// The results are bit for bit the same everywhere, these are the ones of every platform
+func TestFixed_Deterministic(t *testing.T) {
+	s, c := FromFloat(1).SinCos()
+	utils.Equals(t, [2]Fixed{3614090360, 2320580734}, [2]Fixed{s, c})
+	utils.Equals(t, Fixed(10729221487), Atan2(FromInt(3), FromInt(-4)))
+	utils.Equals(t, Fixed(6074001000), FromInt(2).Sqrt())
+}
@@ -0,0 +1,110 @@
+package fixed
+
+import (
+	"fmt"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// NewQuaternion constructs a Quaternion
+func NewQuaternion(x, y, z, w Fixed) *Quaternion {
+	return &Quaternion{X: int64(x), Y: int64(y), Z: int64(z), W: int64(w)}
+}
+
+// NewQuaternionIdentity returns the quaternion of no rotation
+func NewQuaternionIdentity() *Quaternion {
+	return NewQuaternion(0, 0, 0, One)
+}
+
+// NewQuaternionAxisAngle returns the rotation of angle radians around the unit axis
+func NewQuaternionAxisAngle(axis Vector3, angle Fixed) *Quaternion {
+	s, c := angle.Div(2 * One).SinCos()
+	a := axis.Times(s)
+	return NewQuaternion(Fixed(a.X), Fixed(a.Y), Fixed(a.Z), c)
+}
+
+// FromQuaternion returns the fixed point quaternion closest to q
+func FromQuaternion(q quaternion.Quaternion) *Quaternion {
+	return NewQuaternion(FromFloat(q.X), FromFloat(q.Y), FromFloat(q.Z), FromFloat(q.W))
+}
+
+// Float returns the quaternion as a float quaternion
+func (q Quaternion) Float() quaternion.Quaternion {
+	x, y, z, w := q.Components()
+	return *quaternion.NewQuaternion(x.Float(), y.Float(), z.Float(), w.Float())
+}
+
+// Components returns the components of the quaternion as Fixed
+func (q Quaternion) Components() (x, y, z, w Fixed) {
+	return Fixed(q.X), Fixed(q.Y), Fixed(q.Z), Fixed(q.W)
+}
+
+// Equal reports whether q and o are the same, bit for bit
+func (q Quaternion) Equal(o Quaternion) bool {
+	return q.X == o.X && q.Y == o.Y && q.Z == o.Z && q.W == o.W
+}
+
+// Dot returns the dot product of two quaternions
+func (q Quaternion) Dot(o Quaternion) Fixed {
+	x, y, z, w := q.Components()
+	x2, y2, z2, w2 := o.Components()
+	return x.Mul(x2) + y.Mul(y2) + z.Mul(z2) + w.Mul(w2)
+}
+
+// Normalize returns the quaternion scaled to a norm of 1, the identity if it is zero
+func (q Quaternion) Normalize() Quaternion {
+	n := q.Dot(q).Sqrt()
+	if n == 0 {
+		return *NewQuaternionIdentity()
+	}
+	x, y, z, w := q.Components()
+	return *NewQuaternion(x.Div(n), y.Div(n), z.Div(n), w.Div(n))
+}
+
+// Conjugate returns the inverse rotation of the unit quaternion q
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{X: -q.X, Y: -q.Y, Z: -q.Z, W: q.W}
+}
+
+// Mul returns the rotation of o followed by the one of q
+func (q Quaternion) Mul(o Quaternion) Quaternion {
+	x, y, z, w := q.Components()
+	x2, y2, z2, w2 := o.Components()
+	return *NewQuaternion(
+		w.Mul(x2)+x.Mul(w2)+y.Mul(z2)-z.Mul(y2),
+		w.Mul(y2)-x.Mul(z2)+y.Mul(w2)+z.Mul(x2),
+		w.Mul(z2)+x.Mul(y2)-y.Mul(x2)+z.Mul(w2),
+		w.Mul(w2)-x.Mul(x2)-y.Mul(y2)-z.Mul(z2),
+	)
+}
+
+// Slerp returns the spherical linear interpolation of unit quaternions from q to o by t, along the shortest
+// arc, like quaternion.Quaternion.Slerp
+func (q Quaternion) Slerp(o Quaternion, t Fixed) Quaternion {
+	d := q.Dot(o)
+	if d < 0 {
+		o = Quaternion{X: -o.X, Y: -o.Y, Z: -o.Z, W: -o.W}
+		d = -d
+	}
+	a, b := One-t, t
+	// Close quaternions are lerped, the sine below vanishing
+	if d < One-One/2048 {
+		theta := d.Acos()
+		s := theta.Sin()
+		a, b = (One - t).Mul(theta).Sin().Div(s), t.Mul(theta).Sin().Div(s)
+	}
+	x, y, z, w := q.Components()
+	x2, y2, z2, w2 := o.Components()
+	return NewQuaternion(a.Mul(x)+b.Mul(x2), a.Mul(y)+b.Mul(y2), a.Mul(z)+b.Mul(z2), a.Mul(w)+b.Mul(w2)).Normalize()
+}
+
+// Format writes the quaternion as "(x, y, z, w)" for the fmt package, like quaternion.Quaternion.Format
+func (q Quaternion) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "fixed.Quaternion{X:%#v, Y:%#v, Z:%#v, W:%#v}", q.X, q.Y, q.Z, q.W)
+		return
+	}
+	x, y, z, w := q.Components()
+	utils.WriteTuple(f, verb, x.Float(), y.Float(), z.Float(), w.Float())
+}
@@ -0,0 +1,58 @@
+package fixed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func quaternionDistance(a, b quaternion.Quaternion) float64 {
+	return math.Max(math.Max(math.Abs(a.X-b.X), math.Abs(a.Y-b.Y)), math.Max(math.Abs(a.Z-b.Z), math.Abs(a.W-b.W)))
+}
+
+func TestQuaternion_Conversions(t *testing.T) {
+	q := *quaternion.NewQuaternion(0, 0.5, -0.25, 1)
+	f := FromQuaternion(q)
+	utils.Equals(t, *NewQuaternion(0, Half, -One/4, One), *f)
+	utils.Equals(t, q, f.Float())
+	utils.Equals(t, "(0, 0.5, -0.25, 1)", fmt.Sprint(f))
+	utils.Equals(t, true, f.Equal(*f))
+	utils.Equals(t, false, f.Equal(*NewQuaternionIdentity()))
+}
+
+func TestQuaternion_Arithmetic(t *testing.T) {
+	axis := *NewVector3(0, 0, One)
+	quarter := NewQuaternionAxisAngle(axis, HalfPi)
+	utils.Equals(t, true, quaternionDistance(quarter.Float(), *quaternion.NewQuaternion(0, 0, math.Sqrt2/2, math.Sqrt2/2)) < 1e-9)
+	// Two quarter turns around z make a half turn
+	half := quarter.Mul(*quarter)
+	utils.Equals(t, true, quaternionDistance(half.Float(), *quaternion.NewQuaternion(0, 0, 1, 0)) < 1e-9)
+	v := NewVector3(One, 0, 0).Rotate(half)
+	utils.Equals(t, true, v.Float().Distance(*vector3.NewVector3(-1, 0, 0)) < 1e-8)
+	back := half.Mul(half.Conjugate())
+	utils.Equals(t, true, quaternionDistance(back.Float(), *quaternion.NewQuaternion(0, 0, 0, 1)) < 1e-9)
+
+	utils.Equals(t, *NewQuaternionIdentity(), Quaternion{}.Normalize())
+	n := NewQuaternion(0, FromInt(3), 0, FromInt(4)).Normalize()
+	utils.Equals(t, *NewQuaternion(0, FromFloat(0.6), 0, FromFloat(0.8)), n)
+	utils.Equals(t, true, near(n.Dot(n), 1, 1))
+}
+
+func TestQuaternion_Slerp(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 200; i++ {
+		a := quaternion.ToQuaternion(r.Float64()*6, r.Float64()*6, r.Float64()*6)
+		b := quaternion.ToQuaternion(r.Float64()*6, r.Float64()*6, r.Float64()*6)
+		s := r.Float64()
+		got := FromQuaternion(*a).Slerp(*FromQuaternion(*b), FromFloat(s)).Float()
+		exp := a.Slerp(*b, s)
+		utils.Equals(t, true, quaternionDistance(got, exp) < 1e-7)
+	}
+	q := FromQuaternion(*quaternion.ToQuaternion(1, 2, 3))
+	utils.Equals(t, true, quaternionDistance(q.Slerp(*q, Half).Float(), q.Float()) < 1e-9)
+}
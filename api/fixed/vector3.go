@@ -0,0 +1,133 @@
+package fixed
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// NewVector3 constructs a Vector3
+func NewVector3(x, y, z Fixed) *Vector3 {
+	return &Vector3{X: int64(x), Y: int64(y), Z: int64(z)}
+}
+
+// FromVector3 returns the fixed point vector closest to v
+func FromVector3(v vector3.Vector3) *Vector3 {
+	return NewVector3(FromFloat(v.X), FromFloat(v.Y), FromFloat(v.Z))
+}
+
+// Float returns the vector as a float vector
+func (v Vector3) Float() vector3.Vector3 {
+	return *vector3.NewVector3(Fixed(v.X).Float(), Fixed(v.Y).Float(), Fixed(v.Z).Float())
+}
+
+// Components returns the components of the vector as Fixed
+func (v Vector3) Components() (x, y, z Fixed) {
+	return Fixed(v.X), Fixed(v.Y), Fixed(v.Z)
+}
+
+// Equal reports whether v and v2 are the same, bit for bit
+func (v Vector3) Equal(v2 Vector3) bool {
+	return v.X == v2.X && v.Y == v2.Y && v.Z == v2.Z
+}
+
+// Plus returns the sum of v and v2
+// Not in-place
+func (v Vector3) Plus(v2 Vector3) Vector3 {
+	return Vector3{X: v.X + v2.X, Y: v.Y + v2.Y, Z: v.Z + v2.Z}
+}
+
+// Minus returns the difference of v and v2
+// Not in-place
+func (v Vector3) Minus(v2 Vector3) Vector3 {
+	return Vector3{X: v.X - v2.X, Y: v.Y - v2.Y, Z: v.Z - v2.Z}
+}
+
+// Times returns v scaled by m
+// Not in-place
+func (v Vector3) Times(m Fixed) Vector3 {
+	x, y, z := v.Components()
+	return *NewVector3(x.Mul(m), y.Mul(m), z.Mul(m))
+}
+
+// Divide returns v divided by m
+// Not in-place
+func (v Vector3) Divide(m Fixed) Vector3 {
+	x, y, z := v.Components()
+	return *NewVector3(x.Div(m), y.Div(m), z.Div(m))
+}
+
+// Dot returns the dot product of v and v2
+func (v Vector3) Dot(v2 Vector3) Fixed {
+	x, y, z := v.Components()
+	x2, y2, z2 := v2.Components()
+	return x.Mul(x2) + y.Mul(y2) + z.Mul(z2)
+}
+
+// Cross returns the cross product of v and v2
+// Not in-place
+func (v Vector3) Cross(v2 Vector3) Vector3 {
+	x, y, z := v.Components()
+	x2, y2, z2 := v2.Components()
+	return *NewVector3(y.Mul(z2)-z.Mul(y2), z.Mul(x2)-x.Mul(z2), x.Mul(y2)-y.Mul(x2))
+}
+
+// Norm returns the square of the length of v, like vector3.Vector3.Norm
+func (v Vector3) Norm() Fixed {
+	return v.Dot(v)
+}
+
+// Norm2 returns the length of v, like vector3.Vector3.Norm2, without overflowing for long vectors
+func (v Vector3) Norm2() Fixed {
+	// The squares are summed on 128 bits, their root has the fraction of Fixed
+	var hi, lo uint64
+	for _, c := range [3]int64{v.X, v.Y, v.Z} {
+		a, _ := Fixed(c).abs()
+		h, l := bits.Mul64(a, a)
+		var carry uint64
+		lo, carry = bits.Add64(lo, l, 0)
+		hi, _ = bits.Add64(hi, h, carry)
+	}
+	return saturate(sqrt128(hi, lo), false)
+}
+
+// Normalize returns the unit vector in the direction of v, the zero vector if v is zero
+// Not in-place
+func (v Vector3) Normalize() Vector3 {
+	n := v.Norm2()
+	if n == 0 {
+		return Vector3{}
+	}
+	return v.Divide(n)
+}
+
+// Distance returns the distance between v and v2
+func (v Vector3) Distance(v2 Vector3) Fixed {
+	return v.Minus(v2).Norm2()
+}
+
+// Lerp returns the linear interpolation from v to v2 by t
+// Not in-place
+func (v Vector3) Lerp(v2 Vector3, t Fixed) Vector3 {
+	return v.Plus(v2.Minus(v).Times(t))
+}
+
+// Rotate returns the vector rotated by the unit quaternion q
+// Not in-place
+func (v Vector3) Rotate(q Quaternion) Vector3 {
+	u := Vector3{X: q.X, Y: q.Y, Z: q.Z}
+	t := u.Cross(v).Times(2 * One)
+	return v.Plus(t.Times(Fixed(q.W))).Plus(u.Cross(t))
+}
+
+// Format writes the vector as "(x, y, z)" for the fmt package, like vector3.Vector3.Format
+func (v Vector3) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "fixed.Vector3{X:%#v, Y:%#v, Z:%#v}", v.X, v.Y, v.Z)
+		return
+	}
+	x, y, z := v.Components()
+	utils.WriteTuple(f, verb, x.Float(), y.Float(), z.Float())
+}
@@ -0,0 +1,63 @@
+package fixed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestVector3_Conversions(t *testing.T) {
+	v := *vector3.NewVector3(1.5, -2, 0.25)
+	f := FromVector3(v)
+	utils.Equals(t, *NewVector3(FromFloat(1.5), FromInt(-2), One/4), *f)
+	utils.Equals(t, v, f.Float())
+	utils.Equals(t, "(1.5, -2, 0.25)", fmt.Sprint(f))
+	utils.Equals(t, "fixed.Vector3{X:6442450944, Y:-8589934592, Z:1073741824}", fmt.Sprintf("%#v", f))
+}
+
+func TestVector3_Arithmetic(t *testing.T) {
+	a, b := *NewVector3(FromInt(1), FromInt(2), FromInt(3)), *NewVector3(FromInt(4), FromInt(-5), FromInt(6))
+	utils.Equals(t, *NewVector3(FromInt(5), FromInt(-3), FromInt(9)), a.Plus(b))
+	utils.Equals(t, *NewVector3(FromInt(-3), FromInt(7), FromInt(-3)), a.Minus(b))
+	utils.Equals(t, *NewVector3(Half, One, FromFloat(1.5)), a.Times(Half))
+	utils.Equals(t, a.Times(Half), a.Divide(FromInt(2)))
+	utils.Equals(t, FromInt(12), a.Dot(b))
+	utils.Equals(t, *NewVector3(FromInt(27), FromInt(6), FromInt(-13)), a.Cross(b))
+	utils.Equals(t, FromInt(14), a.Norm())
+	utils.Equals(t, FromInt(5), NewVector3(FromInt(3), 0, FromInt(-4)).Norm2())
+	utils.Equals(t, FromInt(5), NewVector3(FromInt(3), 0, 0).Distance(*NewVector3(0, FromInt(4), 0)))
+	utils.Equals(t, *NewVector3(FromFloat(0.6), 0, FromFloat(-0.8)), NewVector3(FromInt(3), 0, FromInt(-4)).Normalize())
+	utils.Equals(t, Vector3{}, Vector3{}.Normalize())
+	utils.Equals(t, *NewVector3(FromFloat(2.5), FromFloat(-1.5), FromFloat(4.5)), a.Lerp(b, Half))
+	utils.Equals(t, true, a.Equal(*NewVector3(FromInt(1), FromInt(2), FromInt(3))))
+	utils.Equals(t, false, a.Equal(b))
+
+	// Long vectors don't overflow
+	big := NewVector3(FromInt(1<<30), FromInt(1<<30), FromInt(1<<30))
+	utils.Equals(t, true, near(big.Norm2(), math.Sqrt(3)*(1<<30), 1))
+	utils.Equals(t, MaxValue, NewVector3(MaxValue, MaxValue, MaxValue).Norm2())
+
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 200; i++ {
+		v := *vector3.NewVector3(r.Float64()*200-100, r.Float64()*200-100, r.Float64()*200-100)
+		f := FromVector3(v)
+		utils.Equals(t, true, near(f.Norm2(), f.Float().Norm2(), 0.6))
+		n := f.Normalize().Float()
+		utils.Equals(t, true, n.Distance(v.Normalize()) < 1e-9)
+	}
+}
+
+func TestVector3_Rotate(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 200; i++ {
+		v := *vector3.NewVector3(r.Float64()*20-10, r.Float64()*20-10, r.Float64()*20-10)
+		q := quaternion.ToQuaternion(r.Float64()*6, r.Float64()*6, r.Float64()*6)
+		got := FromVector3(v).Rotate(*FromQuaternion(*q)).Float()
+		utils.Equals(t, true, got.Distance(v.Rotate(*q)) < 1e-7)
+	}
+}
@@ -0,0 +1,112 @@
+package interest
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+)
+
+// cell is the index of a cube of the grid
+type cell [3]int64
+
+// grid is a spatial hash of ids into cubic cells of the same size
+type grid struct {
+	size  float64
+	cells map[cell]map[uint64]struct{}
+}
+
+func newGrid(size float64) *grid {
+	return &grid{size: size, cells: make(map[cell]map[uint64]struct{})}
+}
+
+// maxCell bounds the cell coordinates, so that infinite or huge positions still convert to integers
+const maxCell = 1 << 52
+
+// coordinate returns the cell coordinate of x along an axis
+func (g *grid) coordinate(x float64) int64 {
+	c := math.Floor(x / g.size)
+	if math.IsNaN(c) {
+		return 0
+	}
+	return int64(math.Max(-maxCell, math.Min(c, maxCell)))
+}
+
+func (g *grid) cellOf(p vector3.Vector3) cell {
+	return cell{g.coordinate(p.X), g.coordinate(p.Y), g.coordinate(p.Z)}
+}
+
+// span returns the cells at the corners of the box, and the number of cells it overlaps
+func (g *grid) span(b volume.Box) (lo, hi cell, count float64) {
+	lo, hi = g.cellOf(*b.Min), g.cellOf(*b.Max)
+	count = 1
+	for i := range lo {
+		count *= float64(hi[i] - lo[i] + 1)
+	}
+	return lo, hi, count
+}
+
+func (g *grid) insert(c cell, id uint64) {
+	ids, ok := g.cells[c]
+	if !ok {
+		ids = make(map[uint64]struct{})
+		g.cells[c] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (g *grid) remove(c cell, id uint64) {
+	ids := g.cells[c]
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(g.cells, c)
+	}
+}
+
+// each calls f for the ids of the cells overlapped by the box, the ids overlapping several cells being
+// visited once per cell
+func (g *grid) each(b volume.Box, f func(id uint64)) {
+	lo, hi, count := g.span(b)
+	// A box larger than the occupied cells is cheaper to check against all of them
+	if count > float64(len(g.cells)) {
+		for c, ids := range g.cells {
+			if c[0] < lo[0] || c[0] > hi[0] || c[1] < lo[1] || c[1] > hi[1] || c[2] < lo[2] || c[2] > hi[2] {
+				continue
+			}
+			for id := range ids {
+				f(id)
+			}
+		}
+		return
+	}
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for z := lo[2]; z <= hi[2]; z++ {
+				for id := range g.cells[cell{x, y, z}] {
+					f(id)
+				}
+			}
+		}
+	}
+}
+
+// insertBox adds the id to every cell the box overlaps, to be used for boxes of a few cells only
+func (g *grid) insertBox(b volume.Box, id uint64) {
+	g.eachCell(b, func(c cell) { g.insert(c, id) })
+}
+
+// removeBox removes the id from every cell the box overlaps
+func (g *grid) removeBox(b volume.Box, id uint64) {
+	g.eachCell(b, func(c cell) { g.remove(c, id) })
+}
+
+func (g *grid) eachCell(b volume.Box, f func(c cell)) {
+	lo, hi, _ := g.span(b)
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for z := lo[2]; z <= hi[2]; z++ {
+				f(cell{x, y, z})
+			}
+		}
+	}
+}
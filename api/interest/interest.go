@@ -0,0 +1,251 @@
+package interest
+
+import (
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+)
+
+// EventKind tells how an entity changed for a subscriber
+type EventKind int
+
+const (
+	// Enter is sent when an entity comes into the view of a subscriber
+	Enter EventKind = iota
+	// Update is sent when an entity moves within the view of a subscriber
+	Update
+	// Leave is sent when an entity goes out of the view of a subscriber or is removed
+	Leave
+)
+
+// Event is a change of an entity to send to a subscriber
+type Event struct {
+	Subscriber uint64
+	Entity     uint64
+	Kind       EventKind
+	Position   vector3.Vector3
+}
+
+type entity struct {
+	position vector3.Vector3
+	removed  bool
+	// previous is the position at the last Flush, and whether the entity existed then
+	previous vector3.Vector3
+	existed  bool
+}
+
+type subscriber struct {
+	view    volume.Box
+	visible map[uint64]struct{}
+}
+
+// maxViewCells is the most cells a view is indexed in, larger views are checked against every change
+const maxViewCells = 1024
+
+// Manager tracks the entities and the views of the subscribers, both indexed in spatial hash grids, to tell
+// each subscriber which entities entered, moved within or left its view
+// Changes are accumulated and turned into events by Flush, usually once per tick
+// Cell size is best around the size of the views, views over maxViewCells cells are kept out of the grid
+type Manager struct {
+	entities     map[uint64]*entity
+	subscribers  map[uint64]*subscriber
+	entityGrid   *grid
+	viewGrid     *grid
+	largeViews   map[uint64]struct{}
+	dirty        map[uint64]struct{}
+	changedViews map[uint64]struct{}
+}
+
+// NewManager returns a manager indexing entities and views in cells of cellSize
+func NewManager(cellSize float64) *Manager {
+	return &Manager{
+		entities:     make(map[uint64]*entity),
+		subscribers:  make(map[uint64]*subscriber),
+		entityGrid:   newGrid(cellSize),
+		viewGrid:     newGrid(cellSize),
+		largeViews:   make(map[uint64]struct{}),
+		dirty:        make(map[uint64]struct{}),
+		changedViews: make(map[uint64]struct{}),
+	}
+}
+
+// SetEntity adds the entity or moves it to position
+func (m *Manager) SetEntity(id uint64, position vector3.Vector3) {
+	e, ok := m.entities[id]
+	if !ok {
+		e = &entity{}
+		m.entities[id] = e
+	} else if !e.removed {
+		m.entityGrid.remove(m.entityGrid.cellOf(e.position), id)
+	}
+	e.position, e.removed = position, false
+	m.entityGrid.insert(m.entityGrid.cellOf(position), id)
+	m.dirty[id] = struct{}{}
+}
+
+// RemoveEntity removes the entity, it leaves the views it was in at the next Flush
+func (m *Manager) RemoveEntity(id uint64) {
+	e, ok := m.entities[id]
+	if !ok || e.removed {
+		return
+	}
+	m.entityGrid.remove(m.entityGrid.cellOf(e.position), id)
+	e.removed = true
+	m.dirty[id] = struct{}{}
+}
+
+// Subscribe adds the subscriber or changes its view
+func (m *Manager) Subscribe(id uint64, view volume.Box) {
+	s, ok := m.subscribers[id]
+	if !ok {
+		s = &subscriber{visible: make(map[uint64]struct{})}
+		m.subscribers[id] = s
+	} else {
+		m.unindexView(id, s.view)
+	}
+	s.view = view
+	m.indexView(id, view)
+	m.changedViews[id] = struct{}{}
+}
+
+// indexView adds the view of the subscriber to the view grid, or to the large views if it covers too many cells
+func (m *Manager) indexView(id uint64, view volume.Box) {
+	if _, _, count := m.viewGrid.span(view); count > maxViewCells {
+		m.largeViews[id] = struct{}{}
+		return
+	}
+	m.viewGrid.insertBox(view, id)
+}
+
+// unindexView removes the view of the subscriber added by indexView
+func (m *Manager) unindexView(id uint64, view volume.Box) {
+	if _, ok := m.largeViews[id]; ok {
+		delete(m.largeViews, id)
+		return
+	}
+	m.viewGrid.removeBox(view, id)
+}
+
+// Unsubscribe removes the subscriber, without events
+func (m *Manager) Unsubscribe(id uint64) {
+	s, ok := m.subscribers[id]
+	if !ok {
+		return
+	}
+	m.unindexView(id, s.view)
+	delete(m.subscribers, id)
+	delete(m.changedViews, id)
+}
+
+// Query returns the entities inside the box, in increasing id
+func (m *Manager) Query(b volume.Box) []uint64 {
+	var ids []uint64
+	m.entityGrid.each(b, func(id uint64) {
+		if b.Contains(m.entities[id].position) {
+			ids = append(ids, id)
+		}
+	})
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Visible returns the entities in the view of the subscriber as of the last Flush, in increasing id
+func (m *Manager) Visible(subscriber uint64) []uint64 {
+	s, ok := m.subscribers[subscriber]
+	if !ok {
+		return nil
+	}
+	ids := make([]uint64, 0, len(s.visible))
+	for id := range s.visible {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// event records the change of visibility of the entity for the subscriber, returning whether there is one
+func (m *Manager) event(s *subscriber, subscriberID, entityID uint64) (Event, bool) {
+	e := m.entities[entityID]
+	_, was := s.visible[entityID]
+	is := !e.removed && s.view.Contains(e.position)
+	ev := Event{Subscriber: subscriberID, Entity: entityID, Position: e.position}
+	switch {
+	case is && !was:
+		s.visible[entityID] = struct{}{}
+		ev.Kind = Enter
+	case !is && was:
+		delete(s.visible, entityID)
+		ev.Kind = Leave
+	case is && was && (!e.existed || !e.position.Equal(e.previous)):
+		ev.Kind = Update
+	default:
+		return ev, false
+	}
+	return ev, true
+}
+
+// Flush returns the events of the changes since the previous Flush, by subscriber then entity
+// Subscribers whose view changed are compared against all the entities in it, the others only against the
+// entities that moved, were added or removed near them
+func (m *Manager) Flush() []Event {
+	var events []Event
+	for id := range m.changedViews {
+		s := m.subscribers[id]
+		// Entities in the new view, and the ones of the old view that may leave
+		candidates := make(map[uint64]struct{}, len(s.visible))
+		for e := range s.visible {
+			candidates[e] = struct{}{}
+		}
+		m.entityGrid.each(s.view, func(e uint64) { candidates[e] = struct{}{} })
+		for e := range candidates {
+			if ev, ok := m.event(s, id, e); ok {
+				events = append(events, ev)
+			}
+		}
+	}
+	for id := range m.dirty {
+		e := m.entities[id]
+		// Subscribers around the old and new positions
+		candidates := make(map[uint64]struct{})
+		positions := []vector3.Vector3{e.position}
+		if e.existed {
+			positions = append(positions, e.previous)
+		}
+		for _, p := range positions {
+			for s := range m.viewGrid.cells[m.viewGrid.cellOf(p)] {
+				candidates[s] = struct{}{}
+			}
+			for s := range m.largeViews {
+				if m.subscribers[s].view.Contains(p) {
+					candidates[s] = struct{}{}
+				}
+			}
+		}
+		for s := range candidates {
+			if _, done := m.changedViews[s]; done {
+				continue
+			}
+			if ev, ok := m.event(m.subscribers[s], s, id); ok {
+				events = append(events, ev)
+			}
+		}
+	}
+	for id := range m.dirty {
+		e := m.entities[id]
+		if e.removed {
+			delete(m.entities, id)
+			continue
+		}
+		e.previous, e.existed = e.position, true
+	}
+	m.dirty = make(map[uint64]struct{})
+	m.changedViews = make(map[uint64]struct{})
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Subscriber != events[j].Subscriber {
+			return events[i].Subscriber < events[j].Subscriber
+		}
+		return events[i].Entity < events[j].Entity
+	})
+	return events
+}
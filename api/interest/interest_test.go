@@ -0,0 +1,179 @@
+package interest
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestManager_Events(t *testing.T) {
+	m := NewManager(10)
+	m.Subscribe(1, *volume.NewBoxMinMax(0, 0, 0, 10, 10, 10))
+	m.Subscribe(2, *volume.NewBoxMinMax(5, 0, 0, 25, 10, 10))
+	m.SetEntity(100, *vector3.NewVector3(1, 1, 1))
+	m.SetEntity(101, *vector3.NewVector3(7, 1, 1))
+	m.SetEntity(102, *vector3.NewVector3(50, 1, 1))
+	utils.Equals(t, []Event{
+		{Subscriber: 1, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(1, 1, 1)},
+		{Subscriber: 1, Entity: 101, Kind: Enter, Position: *vector3.NewVector3(7, 1, 1)},
+		{Subscriber: 2, Entity: 101, Kind: Enter, Position: *vector3.NewVector3(7, 1, 1)},
+	}, m.Flush())
+	utils.Equals(t, []uint64{100, 101}, m.Visible(1))
+
+	// Nothing changed
+	utils.Equals(t, 0, len(m.Flush()))
+
+	// Moving within a view, across views, out of all of them
+	m.SetEntity(100, *vector3.NewVector3(2, 1, 1))
+	m.SetEntity(101, *vector3.NewVector3(20, 1, 1))
+	m.SetEntity(102, *vector3.NewVector3(60, 1, 1))
+	utils.Equals(t, []Event{
+		{Subscriber: 1, Entity: 100, Kind: Update, Position: *vector3.NewVector3(2, 1, 1)},
+		{Subscriber: 1, Entity: 101, Kind: Leave, Position: *vector3.NewVector3(20, 1, 1)},
+		{Subscriber: 2, Entity: 101, Kind: Update, Position: *vector3.NewVector3(20, 1, 1)},
+	}, m.Flush())
+
+	// Removed entities leave, changed views are compared again
+	m.RemoveEntity(101)
+	m.Subscribe(1, *volume.NewBoxMinMax(40, 0, 0, 70, 10, 10))
+	utils.Equals(t, []Event{
+		{Subscriber: 1, Entity: 100, Kind: Leave, Position: *vector3.NewVector3(2, 1, 1)},
+		{Subscriber: 1, Entity: 102, Kind: Enter, Position: *vector3.NewVector3(60, 1, 1)},
+		{Subscriber: 2, Entity: 101, Kind: Leave, Position: *vector3.NewVector3(20, 1, 1)},
+	}, m.Flush())
+	utils.Equals(t, []uint64{102}, m.Visible(1))
+	utils.Equals(t, []uint64(nil), m.Visible(3))
+
+	m.Unsubscribe(1)
+	m.SetEntity(102, *vector3.NewVector3(61, 1, 1))
+	utils.Equals(t, 0, len(m.Flush()))
+	utils.Equals(t, []uint64{100, 102}, m.Query(*volume.NewBoxMinMax(0, 0, 0, 100, 10, 10)))
+}
+
+func TestManager_Random(t *testing.T) {
+	// Views maintained from the events match brute force visibility
+	r := rand.New(rand.NewSource(1))
+	m := NewManager(20)
+	random := func() vector3.Vector3 {
+		return *vector3.NewVector3(r.Float64()*200, r.Float64()*200, r.Float64()*20)
+	}
+	views := make(map[uint64]volume.Box)
+	for s := uint64(0); s < 20; s++ {
+		p := random()
+		views[s] = *volume.NewBoxMinMax(p.X, p.Y, 0, p.X+40, p.Y+40, 20)
+		m.Subscribe(s, views[s])
+	}
+	positions := make(map[uint64]vector3.Vector3)
+	seen := make(map[uint64]map[uint64]bool)
+	for s := range views {
+		seen[s] = make(map[uint64]bool)
+	}
+	for tick := 0; tick < 50; tick++ {
+		for i := 0; i < 100; i++ {
+			id := uint64(r.Intn(500))
+			if r.Intn(10) == 0 {
+				m.RemoveEntity(id)
+				delete(positions, id)
+				continue
+			}
+			positions[id] = random()
+			m.SetEntity(id, positions[id])
+		}
+		if tick%10 == 0 {
+			p := random()
+			views[3] = *volume.NewBoxMinMax(p.X, p.Y, 0, p.X+40, p.Y+40, 20)
+			m.Subscribe(3, views[3])
+		}
+		for _, e := range m.Flush() {
+			switch e.Kind {
+			case Enter:
+				utils.Equals(t, false, seen[e.Subscriber][e.Entity])
+				seen[e.Subscriber][e.Entity] = true
+			case Leave:
+				utils.Equals(t, true, seen[e.Subscriber][e.Entity])
+				delete(seen[e.Subscriber], e.Entity)
+			case Update:
+				utils.Equals(t, true, seen[e.Subscriber][e.Entity])
+			}
+		}
+		for s, view := range views {
+			var want []uint64
+			for id, p := range positions {
+				if view.Contains(p) {
+					want = append(want, id)
+				}
+			}
+			sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+			utils.Equals(t, want, m.Query(view))
+			got := m.Visible(s)
+			if len(want) == 0 {
+				want = []uint64{}
+			}
+			utils.Equals(t, want, got)
+			utils.Equals(t, len(want), len(seen[s]))
+		}
+	}
+}
+
+func TestManager_LargeViews(t *testing.T) {
+	// Views over too many cells are not indexed in the grid, unbounded ones neither hang nor overflow
+	m := NewManager(1)
+	inf := math.Inf(1)
+	m.Subscribe(1, *volume.NewBoxMinMax(-1e9, -1e9, -1e9, 1e9, 1e9, 1e9))
+	m.Subscribe(2, *volume.NewBoxMinMax(-inf, -inf, -inf, inf, inf, inf))
+	m.Subscribe(3, *volume.NewBoxMinMax(0, 0, 0, 2, 2, 2))
+	m.SetEntity(100, *vector3.NewVector3(1, 1, 1))
+	utils.Equals(t, []Event{
+		{Subscriber: 1, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(1, 1, 1)},
+		{Subscriber: 2, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(1, 1, 1)},
+		{Subscriber: 3, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(1, 1, 1)},
+	}, m.Flush())
+	utils.Equals(t, []uint64{100}, m.Query(*volume.NewBoxMinMax(-inf, -inf, -inf, inf, inf, inf)))
+
+	m.SetEntity(100, *vector3.NewVector3(2e9, 1, 1))
+	utils.Equals(t, []Event{
+		{Subscriber: 1, Entity: 100, Kind: Leave, Position: *vector3.NewVector3(2e9, 1, 1)},
+		{Subscriber: 2, Entity: 100, Kind: Update, Position: *vector3.NewVector3(2e9, 1, 1)},
+		{Subscriber: 3, Entity: 100, Kind: Leave, Position: *vector3.NewVector3(2e9, 1, 1)},
+	}, m.Flush())
+
+	// From large to small views and back
+	m.Subscribe(2, *volume.NewBoxMinMax(0, 0, 0, 2, 2, 2))
+	m.Subscribe(3, *volume.NewBoxMinMax(-inf, -inf, -inf, inf, inf, inf))
+	utils.Equals(t, []Event{
+		{Subscriber: 2, Entity: 100, Kind: Leave, Position: *vector3.NewVector3(2e9, 1, 1)},
+		{Subscriber: 3, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(2e9, 1, 1)},
+	}, m.Flush())
+	m.Unsubscribe(3)
+	m.SetEntity(100, *vector3.NewVector3(1, 1, 1))
+	utils.Equals(t, []Event{
+		{Subscriber: 1, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(1, 1, 1)},
+		{Subscriber: 2, Entity: 100, Kind: Enter, Position: *vector3.NewVector3(1, 1, 1)},
+	}, m.Flush())
+	utils.Equals(t, 1, len(m.largeViews))
+}
+
+func BenchmarkManager_Flush(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	m := NewManager(50)
+	for s := uint64(0); s < 1000; s++ {
+		x, y := r.Float64()*2000, r.Float64()*2000
+		m.Subscribe(s, *volume.NewBoxMinMax(x, y, 0, x+100, y+100, 10))
+	}
+	for e := uint64(0); e < 10000; e++ {
+		m.SetEntity(e, *vector3.NewVector3(r.Float64()*2000, r.Float64()*2000, 5))
+	}
+	m.Flush()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for e := uint64(0); e < 1000; e++ {
+			m.SetEntity(e, *vector3.NewVector3(r.Float64()*2000, r.Float64()*2000, 5))
+		}
+		m.Flush()
+	}
+}
@@ -0,0 +1,88 @@
+package quantize
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// QuaternionQuantizer packs unit quaternions with the smallest three encoding: the largest component is
+// dropped, to be recomputed from the others, and its index stored in 2 bits. The three others, between
+// -1/√2 and 1/√2, are rounded to the nearest of 2^bits - 1 evenly spaced values, 0 being one of them
+type QuaternionQuantizer struct {
+	Bits uint
+}
+
+// NewQuaternionQuantizer returns a quantizer of rotations with bits per component, from 2 to 20 so that
+// a rotation fits in 64 bits
+func NewQuaternionQuantizer(bits uint) (*QuaternionQuantizer, error) {
+	if bits < 2 || bits > 20 {
+		return nil, utils.ErrQuantizationInvalidBits
+	}
+	return &QuaternionQuantizer{Bits: bits}, nil
+}
+
+// levels returns the largest integer of a component, even so that 0 is exact
+func (q *QuaternionQuantizer) levels() float64 {
+	return float64(uint64(1)<<q.Bits - 2)
+}
+
+// Encode packs the rotation of r in a single integer, the index of the dropped component in the highest
+// bits. r is normalized first and flipped so that the dropped component is positive, q and -q being the
+// same rotation
+func (q *QuaternionQuantizer) Encode(r quaternion.Quaternion) uint64 {
+	c := [4]float64{r.X, r.Y, r.Z, r.W}
+	norm := math.Sqrt(c[0]*c[0] + c[1]*c[1] + c[2]*c[2] + c[3]*c[3])
+	if norm == 0 || math.IsNaN(norm) {
+		c, norm = [4]float64{0, 0, 0, 1}, 1
+	}
+	largest := 0
+	for i := range c {
+		if math.Abs(c[i]) > math.Abs(c[largest]) {
+			largest = i
+		}
+	}
+	sign := 1 / norm
+	if c[largest] < 0 {
+		sign = -sign
+	}
+	code := uint64(largest)
+	for i := range c {
+		if i == largest {
+			continue
+		}
+		t := math.Max(0, math.Min(1, (c[i]*sign*math.Sqrt2+1)/2))
+		code = code<<q.Bits | uint64(math.Round(t*q.levels()))
+	}
+	return code
+}
+
+// Decode unpacks a rotation encoded by Encode, a unit quaternion
+func (q *QuaternionQuantizer) Decode(code uint64) quaternion.Quaternion {
+	var c [4]float64
+	largest := int(code >> (3 * q.Bits) & 3)
+	sum := 0.
+	for i := 3; i >= 0; i-- {
+		if i == largest {
+			continue
+		}
+		t := float64(code&(uint64(1)<<q.Bits-1)) / q.levels()
+		code >>= q.Bits
+		c[i] = (t*2 - 1) / math.Sqrt2
+		sum += c[i] * c[i]
+	}
+	c[largest] = math.Sqrt(math.Max(0, 1-sum))
+	// Rounding may push the three components past the unit sphere
+	if sum > 1 {
+		for i := range c {
+			c[i] /= math.Sqrt(sum)
+		}
+	}
+	return *quaternion.NewQuaternion(c[0], c[1], c[2], c[3])
+}
+
+// Precision returns the largest error of each of the three encoded components of a unit quaternion
+func (q *QuaternionQuantizer) Precision() float64 {
+	return math.Sqrt2 / q.levels() / 2
+}
@@ -0,0 +1,68 @@
+package quantize
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestNewQuaternionQuantizer(t *testing.T) {
+	_, err := NewQuaternionQuantizer(20)
+	utils.Equals(t, nil, err)
+	_, err = NewQuaternionQuantizer(21)
+	utils.Equals(t, utils.ErrQuantizationInvalidBits, err)
+	_, err = NewQuaternionQuantizer(1)
+	utils.Equals(t, utils.ErrQuantizationInvalidBits, err)
+}
+
+func TestQuaternionQuantizer_Bounds(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, bits := range []uint{6, 9, 12, 20} {
+		q, _ := NewQuaternionQuantizer(bits)
+		precision := q.Precision()
+		for i := 0; i < 1000; i++ {
+			c := [4]float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+			norm := math.Sqrt(c[0]*c[0] + c[1]*c[1] + c[2]*c[2] + c[3]*c[3])
+			for k := range c {
+				c[k] /= norm
+			}
+			code := q.Encode(*quaternion.NewQuaternion(c[0], c[1], c[2], c[3]))
+			utils.Equals(t, true, code < uint64(1)<<(2+3*bits))
+			got := q.Decode(code)
+			g := [4]float64{got.X, got.Y, got.Z, got.W}
+			// Same rotation, maybe of opposite sign
+			dot := 0.
+			for k := range c {
+				dot += c[k] * g[k]
+			}
+			if dot < 0 {
+				for k := range g {
+					g[k] = -g[k]
+				}
+			}
+			distance, norm := 0., 0.
+			largest := int(code >> (3 * bits))
+			for k := range c {
+				if k != largest {
+					utils.Equals(t, true, math.Abs(c[k]-g[k]) <= precision*(1+1e-9))
+				}
+				distance += (c[k] - g[k]) * (c[k] - g[k])
+				norm += g[k] * g[k]
+			}
+			utils.Equals(t, true, math.Abs(1-norm) < 1e-12)
+			utils.Equals(t, true, math.Sqrt(distance) <= 4*precision)
+		}
+	}
+}
+
+func TestQuaternionQuantizer_Encode(t *testing.T) {
+	q, _ := NewQuaternionQuantizer(10)
+	// The identity is exact, and so is its opposite
+	utils.Equals(t, *quaternion.NewQuaternion(0, 0, 0, 1), q.Decode(q.Encode(*quaternion.NewQuaternion(0, 0, 0, 1))))
+	utils.Equals(t, *quaternion.NewQuaternion(0, 0, 0, 1), q.Decode(q.Encode(*quaternion.NewQuaternion(0, 0, 0, -2))))
+	// The largest component is dropped
+	utils.Equals(t, uint64(1), q.Encode(*quaternion.NewQuaternion(0, 0.9, 0, 0.1))>>30)
+}
@@ -0,0 +1,103 @@
+package quantize
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// Vector3Quantizer packs positions inside a box into fixed point integers, rounded to the nearest of the
+// 2^bits evenly spaced values along each axis of the box
+type Vector3Quantizer struct {
+	Domain volume.Box
+	Bits   [3]uint
+}
+
+// NewVector3Quantizer returns a quantizer of the positions inside domain with the same bits along every axis
+// Bits go from 1 to 21 so that a position fits in 64 bits
+func NewVector3Quantizer(domain volume.Box, bits uint) (*Vector3Quantizer, error) {
+	return NewVector3QuantizerAxes(domain, [3]uint{bits, bits, bits})
+}
+
+// NewVector3QuantizerAxes returns a quantizer of the positions inside domain with bits per axis
+// Every axis has from 1 to 32 bits, 64 at most in total
+func NewVector3QuantizerAxes(domain volume.Box, bits [3]uint) (*Vector3Quantizer, error) {
+	total := uint(0)
+	for _, b := range bits {
+		if b < 1 || b > 32 {
+			return nil, utils.ErrQuantizationInvalidBits
+		}
+		total += b
+	}
+	if total > 64 {
+		return nil, utils.ErrQuantizationInvalidBits
+	}
+	return &Vector3Quantizer{Domain: domain, Bits: bits}, nil
+}
+
+// levels returns the largest integer of an axis
+func (q *Vector3Quantizer) levels(axis int) float64 {
+	return float64(uint64(1)<<q.Bits[axis] - 1)
+}
+
+func (q *Vector3Quantizer) bounds(axis int) (min, extent float64) {
+	switch axis {
+	case 0:
+		return q.Domain.Min.X, q.Domain.Max.X - q.Domain.Min.X
+	case 1:
+		return q.Domain.Min.Y, q.Domain.Max.Y - q.Domain.Min.Y
+	}
+	return q.Domain.Min.Z, q.Domain.Max.Z - q.Domain.Min.Z
+}
+
+// Quantize returns the integer coordinates of v, clamped to the domain
+func (q *Vector3Quantizer) Quantize(v vector3.Vector3) [3]uint32 {
+	var out [3]uint32
+	for axis, c := range [3]float64{v.X, v.Y, v.Z} {
+		min, extent := q.bounds(axis)
+		if extent <= 0 || math.IsNaN(c) {
+			continue
+		}
+		t := math.Max(0, math.Min(1, (c-min)/extent))
+		out[axis] = uint32(math.Round(t * q.levels(axis)))
+	}
+	return out
+}
+
+// Dequantize returns the position of integer coordinates
+func (q *Vector3Quantizer) Dequantize(c [3]uint32) vector3.Vector3 {
+	var out [3]float64
+	for axis := range out {
+		min, extent := q.bounds(axis)
+		out[axis] = min + float64(c[axis])/q.levels(axis)*extent
+	}
+	return *vector3.NewVector3(out[0], out[1], out[2])
+}
+
+// Encode packs v in a single integer, x in the lowest bits
+func (q *Vector3Quantizer) Encode(v vector3.Vector3) uint64 {
+	c := q.Quantize(v)
+	return uint64(c[0]) | uint64(c[1])<<q.Bits[0] | uint64(c[2])<<(q.Bits[0]+q.Bits[1])
+}
+
+// Decode unpacks a position encoded by Encode
+func (q *Vector3Quantizer) Decode(code uint64) vector3.Vector3 {
+	var c [3]uint32
+	for axis, b := range q.Bits {
+		c[axis] = uint32(code & (uint64(1)<<b - 1))
+		code >>= b
+	}
+	return q.Dequantize(c)
+}
+
+// Precision returns the largest error along each axis of a position inside the domain once decoded
+func (q *Vector3Quantizer) Precision() vector3.Vector3 {
+	var out [3]float64
+	for axis := range out {
+		_, extent := q.bounds(axis)
+		out[axis] = math.Max(0, extent) / q.levels(axis) / 2
+	}
+	return *vector3.NewVector3(out[0], out[1], out[2])
+}
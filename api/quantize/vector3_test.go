@@ -0,0 +1,54 @@
+package quantize
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestNewVector3Quantizer(t *testing.T) {
+	domain := *volume.NewBoxMinMax(-10, -10, -10, 10, 10, 10)
+	_, err := NewVector3Quantizer(domain, 21)
+	utils.Equals(t, nil, err)
+	_, err = NewVector3Quantizer(domain, 22)
+	utils.Equals(t, utils.ErrQuantizationInvalidBits, err)
+	_, err = NewVector3Quantizer(domain, 0)
+	utils.Equals(t, utils.ErrQuantizationInvalidBits, err)
+	_, err = NewVector3QuantizerAxes(domain, [3]uint{32, 16, 16})
+	utils.Equals(t, nil, err)
+	_, err = NewVector3QuantizerAxes(domain, [3]uint{32, 32, 1})
+	utils.Equals(t, utils.ErrQuantizationInvalidBits, err)
+}
+
+func TestVector3Quantizer_Bounds(t *testing.T) {
+	domain := *volume.NewBoxMinMax(-100, 0, -50, 100, 20, 50)
+	for _, bits := range [][3]uint{{8, 8, 8}, {16, 10, 16}, {21, 21, 21}, {32, 16, 16}} {
+		q, err := NewVector3QuantizerAxes(domain, bits)
+		utils.Equals(t, nil, err)
+		precision := q.Precision()
+		r := rand.New(rand.NewSource(int64(bits[0])))
+		for i := 0; i < 1000; i++ {
+			v := *vector3.NewVector3(-100+200*r.Float64(), 20*r.Float64(), -50+100*r.Float64())
+			got := q.Decode(q.Encode(v))
+			utils.Equals(t, true, math.Abs(got.X-v.X) <= precision.X*(1+1e-9))
+			utils.Equals(t, true, math.Abs(got.Y-v.Y) <= precision.Y*(1+1e-9))
+			utils.Equals(t, true, math.Abs(got.Z-v.Z) <= precision.Z*(1+1e-9))
+		}
+	}
+}
+
+func TestVector3Quantizer_Encode(t *testing.T) {
+	q, _ := NewVector3Quantizer(*volume.NewBoxMinMax(0, 0, 0, 1, 1, 1), 2)
+	// 4 values per axis: 0, 1/3, 2/3, 1
+	utils.Equals(t, [3]uint32{0, 1, 3}, q.Quantize(*vector3.NewVector3(0.1, 0.3, 0.9)))
+	utils.Equals(t, uint64(0|1<<2|3<<4), q.Encode(*vector3.NewVector3(0.1, 0.3, 0.9)))
+	// Clamped outside the domain
+	utils.Equals(t, [3]uint32{0, 3, 3}, q.Quantize(*vector3.NewVector3(-5, 5, 1)))
+	// The corners are exact
+	utils.Equals(t, *vector3.NewVector3(1, 0, 1), q.Decode(q.Encode(*vector3.NewVector3(1, 0, 1))))
+	utils.Equals(t, *vector3.NewVector3(1./6, 1./6, 1./6), q.Precision())
+}
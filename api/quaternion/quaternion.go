@@ -21,3 +21,35 @@ func ToQuaternion(yaw, pitch, roll float64) *Quaternion {
 
 	return NewQuaternion(cy*cp*cr+sy*sp*sr, cy*cp*sr-sy*sp*cr, sy*cp*sr+cy*sp*cr, sy*cp*cr-cy*sp*sr)
 }
+
+// Dot returns the dot product of two quaternions
+func (q Quaternion) Dot(o Quaternion) float64 {
+	return q.X*o.X + q.Y*o.Y + q.Z*o.Z + q.W*o.W
+}
+
+// Normalize returns the quaternion scaled to a norm of 1, the identity if it is zero
+func (q Quaternion) Normalize() Quaternion {
+	n := math.Sqrt(q.Dot(q))
+	if n == 0 {
+		return *NewQuaternion(0, 0, 0, 1)
+	}
+	return *NewQuaternion(q.X/n, q.Y/n, q.Z/n, q.W/n)
+}
+
+// Slerp returns the spherical linear interpolation of unit quaternions from q to o by t, along the shortest arc
+// t outside of [0, 1] extrapolates along the same arc
+func (q Quaternion) Slerp(o Quaternion, t float64) Quaternion {
+	d := q.Dot(o)
+	if d < 0 {
+		o = *NewQuaternion(-o.X, -o.Y, -o.Z, -o.W)
+		d = -d
+	}
+	a, b := 1-t, t
+	// Close quaternions are lerped, the sine below vanishing
+	if d < 0.9995 {
+		theta := math.Acos(d)
+		s := math.Sin(theta)
+		a, b = math.Sin((1-t)*theta)/s, math.Sin(t*theta)/s
+	}
+	return NewQuaternion(a*q.X+b*o.X, a*q.Y+b*o.Y, a*q.Z+b*o.Z, a*q.W+b*o.W).Normalize()
+}
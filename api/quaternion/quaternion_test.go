@@ -1,8 +1,11 @@
 package quaternion
 
 import (
+	"math"
 	"reflect"
 	"testing"
+
+	"github.com/louis030195/protometry/internal/utils"
 )
 
 func TestNewQuaternion(t *testing.T) {
@@ -27,3 +30,27 @@ func TestNewQuaternion(t *testing.T) {
 		})
 	}
 }
+
+func TestQuaternion_Normalize(t *testing.T) {
+	utils.Equals(t, *NewQuaternion(0, 0.6, 0, 0.8), NewQuaternion(0, 3, 0, 4).Normalize())
+	utils.Equals(t, *NewQuaternion(0, 0, 0, 1), NewQuaternion(0, 0, 0, 0).Normalize())
+}
+
+func TestQuaternion_Slerp(t *testing.T) {
+	// Rotations around z by 0 and 90 degrees
+	a := *NewQuaternion(0, 0, 0, 1)
+	b := *NewQuaternion(0, 0, math.Sin(math.Pi/4), math.Cos(math.Pi/4))
+	angle := func(q Quaternion) float64 { return 2 * math.Atan2(q.Z, q.W) }
+	for _, tt := range []struct{ t, want float64 }{{0, 0}, {0.5, math.Pi / 4}, {1, math.Pi / 2}, {1.5, 3 * math.Pi / 4}, {-0.5, -math.Pi / 4}} {
+		got := a.Slerp(b, tt.t)
+		utils.Equals(t, true, math.Abs(angle(got)-tt.want) < 1e-12)
+		utils.Equals(t, true, math.Abs(got.Dot(got)-1) < 1e-12)
+	}
+	// The shortest arc is taken from the opposite quaternion too
+	neg := *NewQuaternion(-b.X, -b.Y, -b.Z, -b.W)
+	got := a.Slerp(neg, 0.5)
+	utils.Equals(t, true, math.Abs(angle(got)-math.Pi/4) < 1e-12)
+	// Close quaternions
+	c := *NewQuaternion(0, 0, math.Sin(1e-4), math.Cos(1e-4))
+	utils.Equals(t, true, math.Abs(angle(a.Slerp(c, 0.5))-1e-4) < 1e-9)
+}
@@ -0,0 +1,27 @@
+package quaternion
+
+import (
+	"fmt"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// Format writes the quaternion as "(x, y, z, w)" for the fmt package, the components formatted with the
+// verb, %v giving their shortest exact form and %#v Go syntax
+// The generated String method keeps the protobuf text form
+func (q Quaternion) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "quaternion.Quaternion{X:%#v, Y:%#v, Z:%#v, W:%#v}", q.X, q.Y, q.Z, q.W)
+		return
+	}
+	utils.WriteTuple(f, verb, q.X, q.Y, q.Z, q.W)
+}
+
+// ParseQuaternion parses a quaternion written as "(x, y, z, w)", returning ErrTextMalformed otherwise
+func ParseQuaternion(s string) (*Quaternion, error) {
+	c, err := utils.ParseTuple(s, 4)
+	if err != nil {
+		return nil, err
+	}
+	return NewQuaternion(c[0], c[1], c[2], c[3]), nil
+}
@@ -0,0 +1,24 @@
+package quaternion
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestQuaternion_Format(t *testing.T) {
+	q := NewQuaternion(0, 0.5, 0, 1)
+	utils.Equals(t, "(0, 0.5, 0, 1)", fmt.Sprint(q))
+	utils.Equals(t, "(0.0, 0.5, 0.0, 1.0)", fmt.Sprintf("%.1f", *q))
+	utils.Equals(t, "quaternion.Quaternion{X:0, Y:0.5, Z:0, W:1}", fmt.Sprintf("%#v", q))
+}
+
+func TestParseQuaternion(t *testing.T) {
+	q := ToQuaternion(0.3, -1.2, 2)
+	got, err := ParseQuaternion(fmt.Sprint(q))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, *q, *got)
+	_, err = ParseQuaternion("(0, 0, 0)")
+	utils.Equals(t, utils.ErrTextMalformed, err)
+}
@@ -0,0 +1,54 @@
+package sdf
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+)
+
+// Grid is a signed distance field baked on a grid of samples, interpolated trilinearly between them
+type Grid struct {
+	*volume.ScalarGrid
+}
+
+// Bake samples the signed distance to a closed mesh on a grid of resolution samples along the longest side
+// of the mesh bounding box grown by padding, with the same spacing along every axis
+func Bake(m *volume.Mesh, resolution int, padding float64) *Grid {
+	bounds := volume.NewBoxMinMax(math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1))
+	for _, v := range m.Vertices {
+		bounds.EncapsulatePoint(*v)
+	}
+	if len(m.Vertices) == 0 {
+		bounds = volume.NewBoxMinMax(0, 0, 0, 0, 0, 0)
+	}
+	pad := *vector3.NewVector3(padding, padding, padding)
+	min := bounds.Min.Minus(pad)
+	size := bounds.GetSize().Plus(pad.Times(2))
+	if resolution < 2 {
+		resolution = 2
+	}
+	step := math.Max(size.X, math.Max(size.Y, size.Z)) / float64(resolution-1)
+	if step == 0 {
+		step = 1
+	}
+	var dims [3]int
+	for i, s := range [3]float64{size.X, size.Y, size.Z} {
+		dims[i] = int(math.Ceil(s/step-1e-9)) + 1
+	}
+	b := volume.NewBoxMinMax(min.X, min.Y, min.Z,
+		min.X+float64(dims[0]-1)*step, min.Y+float64(dims[1]-1)*step, min.Z+float64(dims[2]-1)*step)
+	h := volume.NewMeshBVH(m)
+	return &Grid{volume.NewScalarGrid(h.SignedDistance, *b, dims)}
+}
+
+// Distance returns the interpolated distance at p, past the grid the distance to the grid is added
+func (g *Grid) Distance(p vector3.Vector3) float64 {
+	clamped := vector3.Min(vector3.Max(p, *g.Bounds.Min), *g.Bounds.Max)
+	return g.Sample(p) + p.Distance(clamped)
+}
+
+// SDF returns the baked field as a distance function
+func (g *Grid) SDF() SDF {
+	return g.Distance
+}
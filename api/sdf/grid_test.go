@@ -0,0 +1,48 @@
+package sdf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestBake(t *testing.T) {
+	cube := volume.NewMeshSquareCuboid(2, true)
+	g := Bake(cube, 17, 1)
+	utils.Equals(t, [3]int{17, 17, 17}, g.Dims)
+	exact := Box(*volume.NewBoxMinMax(-1, -1, -1, 1, 1, 1))
+	for _, p := range []vector3.Vector3{
+		*vector3.NewVector3Zero(),
+		*vector3.NewVector3(0.5, 0, 0),
+		*vector3.NewVector3(1.5, 0.25, -0.25),
+		*vector3.NewVector3(-0.5, 1.75, 0.3),
+	} {
+		utils.Equals(t, true, math.Abs(g.Distance(p)-exact(p)) < 0.1)
+	}
+	// Samples are exact
+	utils.Equals(t, true, almostEqual(-1, g.At(8, 8, 8)))
+	utils.Equals(t, true, almostEqual(1, g.At(16, 8, 8)))
+
+	// Pointing away from the closest face
+	n := g.Gradient(*vector3.NewVector3(1.3, 0.1, 0.2)).Normalize()
+	utils.Equals(t, true, n.Dot(*vector3.NewVector3(1, 0, 0)) > 0.99)
+	n = g.Gradient(*vector3.NewVector3(0.1, -0.7, 0.2)).Normalize()
+	utils.Equals(t, true, n.Dot(*vector3.NewVector3(0, -1, 0)) > 0.99)
+
+	// Past the grid
+	utils.Equals(t, true, math.Abs(g.Distance(*vector3.NewVector3(5, 0, 0))-4) < 0.1)
+	utils.Equals(t, g.Distance(*vector3.NewVector3(1, 0.5, 0)), g.SDF()(*vector3.NewVector3(1, 0.5, 0)))
+	utils.Equals(t, math.Inf(1), g.Distance(*vector3.NewVector3(math.Inf(-1), 0, 0)))
+	utils.Equals(t, true, math.IsNaN(g.Distance(*vector3.NewVector3(0, math.NaN(), 0))))
+}
+
+func TestBake_Mesh(t *testing.T) {
+	// The baked field meshes back to the original shape
+	g := Bake(volume.NewMeshSquareCuboid(2, true), 16, 0.5)
+	m := volume.NewMeshIsosurface(volume.ScalarField(g.SDF()), g.Bounds, g.Dims, volume.IsosurfaceOptions{DualContouring: true})
+	utils.Equals(t, true, m.Validate(1e-9).IsValid())
+	utils.Equals(t, true, math.Abs(m.GetVolume()-8) < 0.1)
+}
@@ -0,0 +1,156 @@
+// Package sdf implements signed distance functions: primitives, their combinations and transforms,
+// and distance fields baked from meshes
+package sdf
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+)
+
+// SDF returns the signed distance from a point to a shape, negative inside
+// Combinations and transforms other than translations and rotations return bounds of the distance,
+// exact on the surface, which is enough to sphere trace or to mesh them
+type SDF func(p vector3.Vector3) float64
+
+// Sphere returns the distance to a sphere
+func Sphere(center vector3.Vector3, radius float64) SDF {
+	return func(p vector3.Vector3) float64 {
+		return p.Distance(center) - radius
+	}
+}
+
+// Box returns the distance to an axis aligned box
+func Box(b volume.Box) SDF {
+	return RoundedBox(b, 0)
+}
+
+// RoundedBox returns the distance to an axis aligned box with edges and corners rounded by radius,
+// the rounded box fits in b
+func RoundedBox(b volume.Box, radius float64) SDF {
+	center := b.GetCenter()
+	size := b.GetSize()
+	half := size.Times(0.5).Minus(*vector3.NewVector3(radius, radius, radius))
+	return func(p vector3.Vector3) float64 {
+		d := p.Minus(center)
+		q := d.Abs().Minus(half)
+		outside := vector3.Max(q, *vector3.NewVector3Zero())
+		return outside.Norm2() + math.Min(math.Max(q.X, math.Max(q.Y, q.Z)), 0) - radius
+	}
+}
+
+// Capsule returns the distance to the segment from a to b grown by radius
+func Capsule(a, b vector3.Vector3, radius float64) SDF {
+	ab := b.Minus(a)
+	length := ab.Dot(ab)
+	return func(p vector3.Vector3) float64 {
+		t := 0.
+		if length > 0 {
+			t = math.Min(math.Max(p.Minus(a).Dot(ab)/length, 0), 1)
+		}
+		return p.Distance(a.Plus(ab.Times(t))) - radius
+	}
+}
+
+// Torus returns the distance to a torus around the y axis through center
+func Torus(center vector3.Vector3, majorRadius, minorRadius float64) SDF {
+	return func(p vector3.Vector3) float64 {
+		d := p.Minus(center)
+		ring := math.Hypot(d.X, d.Z) - majorRadius
+		return math.Hypot(ring, d.Y) - minorRadius
+	}
+}
+
+// Plane returns the signed distance to the plane through point, the normal side being outside
+func Plane(point, normal vector3.Vector3) SDF {
+	n := normal.Normalize()
+	return func(p vector3.Vector3) float64 {
+		return n.Dot(p.Minus(point))
+	}
+}
+
+// Union returns the distance to the union of the shapes
+func Union(shapes ...SDF) SDF {
+	return func(p vector3.Vector3) float64 {
+		d := math.Inf(1)
+		for _, s := range shapes {
+			d = math.Min(d, s(p))
+		}
+		return d
+	}
+}
+
+// Intersection returns the distance to the intersection of the shapes
+func Intersection(shapes ...SDF) SDF {
+	return func(p vector3.Vector3) float64 {
+		d := math.Inf(-1)
+		for _, s := range shapes {
+			d = math.Max(d, s(p))
+		}
+		return d
+	}
+}
+
+// Subtraction returns the distance to a minus b
+func Subtraction(a, b SDF) SDF {
+	return func(p vector3.Vector3) float64 {
+		return math.Max(a(p), -b(p))
+	}
+}
+
+// SmoothUnion returns the distance to the union of a and b blended over k with a polynomial smooth minimum
+func SmoothUnion(a, b SDF, k float64) SDF {
+	if k <= 0 {
+		return Union(a, b)
+	}
+	return func(p vector3.Vector3) float64 {
+		da, db := a(p), b(p)
+		h := math.Min(math.Max(0.5+0.5*(db-da)/k, 0), 1)
+		return db + (da-db)*h - k*h*(1-h)
+	}
+}
+
+// Translate returns the shape moved by offset
+func Translate(s SDF, offset vector3.Vector3) SDF {
+	return func(p vector3.Vector3) float64 {
+		return s(p.Minus(offset))
+	}
+}
+
+// Rotate returns the shape rotated around the origin by the unit quaternion q
+func Rotate(s SDF, q quaternion.Quaternion) SDF {
+	inverse := *quaternion.NewQuaternion(-q.X, -q.Y, -q.Z, q.W)
+	return func(p vector3.Vector3) float64 {
+		return s(p.Rotate(inverse))
+	}
+}
+
+// Scale returns the shape scaled around the origin by factor
+func Scale(s SDF, factor float64) SDF {
+	return func(p vector3.Vector3) float64 {
+		return s(p.Times(1/factor)) * factor
+	}
+}
+
+// Transform returns the shape scaled, then rotated and finally moved to position, like a scene node
+func Transform(s SDF, position vector3.Vector3, rotation quaternion.Quaternion, scale float64) SDF {
+	return Translate(Rotate(Scale(s, scale), rotation), position)
+}
+
+// Gradient returns the gradient of the field at p by central differences, a unit vector pointing away
+// from the surface where the field is a true distance
+func Gradient(s SDF, p vector3.Vector3) vector3.Vector3 {
+	const h = 1e-6
+	return *vector3.NewVector3(
+		(s(*vector3.NewVector3(p.X+h, p.Y, p.Z))-s(*vector3.NewVector3(p.X-h, p.Y, p.Z)))/(2*h),
+		(s(*vector3.NewVector3(p.X, p.Y+h, p.Z))-s(*vector3.NewVector3(p.X, p.Y-h, p.Z)))/(2*h),
+		(s(*vector3.NewVector3(p.X, p.Y, p.Z+h))-s(*vector3.NewVector3(p.X, p.Y, p.Z-h)))/(2*h),
+	)
+}
+
+// Mesh extracts the surface of the shape inside the box, sampled on a grid of dims samples
+func (s SDF) Mesh(b volume.Box, dims [3]int, o volume.IsosurfaceOptions) *volume.Mesh {
+	return volume.NewMeshIsosurface(volume.ScalarField(s), b, dims, o)
+}
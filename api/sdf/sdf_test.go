@@ -0,0 +1,101 @@
+package sdf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPrimitives(t *testing.T) {
+	sphere := Sphere(*vector3.NewVector3(1, 0, 0), 2)
+	utils.Equals(t, -2., sphere(*vector3.NewVector3(1, 0, 0)))
+	utils.Equals(t, 1., sphere(*vector3.NewVector3(1, 3, 0)))
+
+	box := Box(*volume.NewBoxMinMax(-1, -2, -3, 1, 2, 3))
+	utils.Equals(t, -1., box(*vector3.NewVector3Zero()))
+	utils.Equals(t, 1., box(*vector3.NewVector3(0, 3, 0)))
+	utils.Equals(t, true, almostEqual(math.Sqrt(3), box(*vector3.NewVector3(2, 3, 4))))
+
+	rounded := RoundedBox(*volume.NewBoxMinMax(-1, -1, -1, 1, 1, 1), 0.5)
+	utils.Equals(t, 0., rounded(*vector3.NewVector3(1, 0, 0)))
+	// The corner is cut off
+	utils.Equals(t, true, rounded(*vector3.NewVector3(1, 1, 1)) > 0)
+	c := 0.5 + 0.5/math.Sqrt(3)
+	utils.Equals(t, true, almostEqual(0, rounded(*vector3.NewVector3(c, c, c))))
+
+	capsule := Capsule(*vector3.NewVector3Zero(), *vector3.NewVector3(0, 2, 0), 0.5)
+	utils.Equals(t, 0.5, capsule(*vector3.NewVector3(1, 1, 0)))
+	utils.Equals(t, 0.5, capsule(*vector3.NewVector3(0, 3, 0)))
+	utils.Equals(t, -0.5, capsule(*vector3.NewVector3(0, 1, 0)))
+
+	torus := Torus(*vector3.NewVector3Zero(), 2, 0.5)
+	utils.Equals(t, -0.5, torus(*vector3.NewVector3(0, 0, 2)))
+	utils.Equals(t, 1.5, torus(*vector3.NewVector3Zero()))
+	utils.Equals(t, 0.5, torus(*vector3.NewVector3(2, 1, 0)))
+
+	plane := Plane(*vector3.NewVector3(0, 1, 0), *vector3.NewVector3(0, 2, 0))
+	utils.Equals(t, 2., plane(*vector3.NewVector3(5, 3, 5)))
+	utils.Equals(t, -1., plane(*vector3.NewVector3(5, 0, -5)))
+}
+
+func TestCombinations(t *testing.T) {
+	a := Sphere(*vector3.NewVector3Zero(), 1)
+	b := Sphere(*vector3.NewVector3(1.5, 0, 0), 1)
+	p := *vector3.NewVector3(-1, 0, 0)
+	utils.Equals(t, 0., Union(a, b)(p))
+	utils.Equals(t, 1.5, Intersection(a, b)(p))
+	utils.Equals(t, -0.25, Intersection(a, b)(*vector3.NewVector3(0.75, 0, 0)))
+	utils.Equals(t, 0.25, Subtraction(a, b)(*vector3.NewVector3(0.75, 0, 0)))
+	utils.Equals(t, -0.5, Subtraction(a, b)(*vector3.NewVector3Zero()))
+
+	// The smooth union fills the gap between the shapes, and matches the union away from it
+	between := *vector3.NewVector3(0.75, 1, 0)
+	utils.Equals(t, true, SmoothUnion(a, b, 0.5)(between) < Union(a, b)(between))
+	utils.Equals(t, Union(a, b)(p), SmoothUnion(a, b, 0.5)(p))
+	utils.Equals(t, Union(a, b)(between), SmoothUnion(a, b, 0)(between))
+}
+
+func TestTransforms(t *testing.T) {
+	box := Box(*volume.NewBoxMinMax(-2, -0.5, -0.5, 2, 0.5, 0.5))
+	moved := Translate(box, *vector3.NewVector3(0, 0, 10))
+	utils.Equals(t, -0.5, moved(*vector3.NewVector3(0, 0, 10)))
+
+	// A quarter turn around y lays the box along z
+	q := *quaternion.NewQuaternion(0, math.Sin(math.Pi/4), 0, math.Cos(math.Pi/4))
+	turned := Rotate(box, q)
+	utils.Equals(t, true, almostEqual(0, turned(*vector3.NewVector3(0, 0, 2))))
+	utils.Equals(t, true, almostEqual(1.5, turned(*vector3.NewVector3(2, 0, 0))))
+
+	scaled := Scale(box, 2)
+	utils.Equals(t, 0., scaled(*vector3.NewVector3(4, 0, 0)))
+	utils.Equals(t, 1., scaled(*vector3.NewVector3(0, 2, 0)))
+
+	node := Transform(box, *vector3.NewVector3(0, 5, 0), q, 2)
+	utils.Equals(t, true, almostEqual(0, node(*vector3.NewVector3(0, 5, 4))))
+	utils.Equals(t, true, almostEqual(-1, node(*vector3.NewVector3(0, 5, 0))))
+}
+
+func TestGradient(t *testing.T) {
+	g := Gradient(Sphere(*vector3.NewVector3Zero(), 1), *vector3.NewVector3(0, 3, 4))
+	utils.Equals(t, true, g.Distance(*vector3.NewVector3(0, 0.6, 0.8)) < 1e-6)
+	g = Gradient(Box(*volume.NewBoxMinMax(-1, -1, -1, 1, 1, 1)), *vector3.NewVector3(0.2, -0.9, 0))
+	utils.Equals(t, true, g.Distance(*vector3.NewVector3(0, -1, 0)) < 1e-6)
+}
+
+func TestSDF_Mesh(t *testing.T) {
+	b := *volume.NewBoxMinMax(-2, -2, -2, 2, 2, 2)
+	s := Union(Sphere(*vector3.NewVector3(-0.5, 0, 0), 1), Sphere(*vector3.NewVector3(0.5, 0, 0), 1))
+	m := s.Mesh(b, [3]int{32, 32, 32}, volume.IsosurfaceOptions{})
+	utils.Equals(t, true, m.Validate(1e-9).IsValid())
+	// Two spheres minus their overlapping lens
+	exact := 2*4*math.Pi/3 - 2*math.Pi*(1-0.5)*(1-0.5)*(2+0.5)/3
+	utils.Equals(t, true, math.Abs(m.GetVolume()-exact) < 0.03*exact)
+}
@@ -0,0 +1,153 @@
+package snapshot
+
+import (
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// Sample is the state of an entity at a time of the sender clock
+// Velocity is optional, estimated from the previous sample when nil
+type Sample struct {
+	Time     float64
+	Position vector3.Vector3
+	Rotation quaternion.Quaternion
+	Velocity *vector3.Vector3
+}
+
+// Buffer holds the latest samples of an entity, to render it at a time between them, slightly in the past,
+// or past the latest by dead reckoning when samples are late
+// The delay to render at is sized from the interval between samples and the jitter of their arrival
+type Buffer struct {
+	// Capacity is the number of samples kept, 32 when 0
+	Capacity int
+	// MaxExtrapolation is how long past the latest sample the state is extrapolated, it is held after
+	MaxExtrapolation float64
+	samples          []Sample
+	// Smoothed interval between samples, jitter of their transit (RFC 3550) and offset between the clocks
+	interval, jitter, offset float64
+	// Transit of the latest sample pushed, NaN before the first
+	transit float64
+}
+
+// NewBuffer returns an empty buffer extrapolating up to maxExtrapolation
+func NewBuffer(maxExtrapolation float64) *Buffer {
+	return &Buffer{MaxExtrapolation: maxExtrapolation, transit: math.NaN()}
+}
+
+// Len returns the number of samples in the buffer
+func (b *Buffer) Len() int {
+	return len(b.samples)
+}
+
+// Push adds a sample received at arrival, a time of the receiver clock. Samples may arrive out of order,
+// the ones older than the buffer or duplicated are dropped
+func (b *Buffer) Push(s Sample, arrival float64) {
+	i := sort.Search(len(b.samples), func(i int) bool { return b.samples[i].Time >= s.Time })
+	if i < len(b.samples) && b.samples[i].Time == s.Time {
+		return
+	}
+	capacity := b.Capacity
+	if capacity <= 0 {
+		capacity = 32
+	}
+	if i == 0 && len(b.samples) >= capacity {
+		return
+	}
+
+	transit := arrival - s.Time
+	if math.IsNaN(b.transit) {
+		b.offset = transit
+	} else {
+		b.jitter += (math.Abs(transit-b.transit) - b.jitter) / 16
+		b.offset += (transit - b.offset) / 16
+	}
+	b.transit = transit
+	if i == len(b.samples) && i > 0 {
+		dt := s.Time - b.samples[i-1].Time
+		if b.interval == 0 {
+			b.interval = dt
+		} else {
+			b.interval += (dt - b.interval) / 16
+		}
+	}
+
+	b.samples = append(b.samples, Sample{})
+	copy(b.samples[i+1:], b.samples[i:])
+	b.samples[i] = s
+	if len(b.samples) > capacity {
+		b.samples = b.samples[len(b.samples)-capacity:]
+	}
+}
+
+// Interval returns the smoothed interval between samples
+func (b *Buffer) Interval() float64 {
+	return b.interval
+}
+
+// Jitter returns the smoothed variation of the transit time of the samples
+func (b *Buffer) Jitter() float64 {
+	return b.jitter
+}
+
+// Delay returns how far behind the latest sample to render so that a later one has most likely arrived:
+// one interval plus three times the jitter
+func (b *Buffer) Delay() float64 {
+	return b.interval + 3*b.jitter
+}
+
+// RenderTime returns the time of the sender clock to render at now, a time of the receiver clock
+func (b *Buffer) RenderTime(now float64) float64 {
+	return now - b.offset - b.Delay()
+}
+
+// velocity returns the velocity at the i-th sample, given or estimated from the previous one
+func (b *Buffer) velocity(i int) vector3.Vector3 {
+	s := b.samples[i]
+	if s.Velocity != nil {
+		return *s.Velocity
+	}
+	if i == 0 {
+		return *vector3.NewVector3Zero()
+	}
+	p := b.samples[i-1]
+	return s.Position.Minus(p.Position).Times(1 / (s.Time - p.Time))
+}
+
+// At returns the state at time, interpolated between the samples around it with Lerp and Slerp, the first
+// sample before them and extrapolated after them. It returns false when the buffer is empty
+func (b *Buffer) At(time float64) (Sample, bool) {
+	n := len(b.samples)
+	if n == 0 {
+		return Sample{}, false
+	}
+	if time <= b.samples[0].Time {
+		s := b.samples[0]
+		v := b.velocity(0)
+		return Sample{Time: time, Position: s.Position, Rotation: s.Rotation, Velocity: &v}, true
+	}
+	last := b.samples[n-1]
+	if time >= last.Time {
+		dt := math.Min(time-last.Time, math.Max(0, b.MaxExtrapolation))
+		v := b.velocity(n - 1)
+		out := Sample{Time: time, Position: last.Position.Plus(v.Times(dt)), Rotation: last.Rotation, Velocity: &v}
+		// Turning at the same rate as from the previous sample
+		if n > 1 && dt > 0 {
+			prev := b.samples[n-2]
+			out.Rotation = prev.Rotation.Slerp(last.Rotation, 1+dt/(last.Time-prev.Time))
+		}
+		return out, true
+	}
+	i := sort.Search(n, func(i int) bool { return b.samples[i].Time > time })
+	from, to := b.samples[i-1], b.samples[i]
+	t := (time - from.Time) / (to.Time - from.Time)
+	v := to.Position.Minus(from.Position).Times(1 / (to.Time - from.Time))
+	return Sample{
+		Time:     time,
+		Position: *from.Position.Lerp(&to.Position, t),
+		Rotation: from.Rotation.Slerp(to.Rotation, t),
+		Velocity: &v,
+	}, true
+}
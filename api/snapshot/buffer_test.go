@@ -0,0 +1,91 @@
+package snapshot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// yaw returns the rotation of angle around z
+func yaw(angle float64) quaternion.Quaternion {
+	return *quaternion.NewQuaternion(0, 0, math.Sin(angle/2), math.Cos(angle/2))
+}
+
+func TestBuffer_At(t *testing.T) {
+	b := NewBuffer(0.2)
+	_, ok := b.At(0)
+	utils.Equals(t, false, ok)
+
+	// Moving along x at 10 units per second and turning at 1 radian per second, out of order
+	for _, i := range []int{0, 2, 1, 3} {
+		time := float64(i) * 0.1
+		b.Push(Sample{Time: time, Position: *vector3.NewVector3(time*10, 0, 0), Rotation: yaw(time)}, time+0.05)
+	}
+	utils.Equals(t, 4, b.Len())
+
+	s, ok := b.At(0.15)
+	utils.Equals(t, true, ok)
+	utils.Equals(t, true, math.Abs(s.Position.X-1.5) < 1e-12)
+	utils.Equals(t, true, math.Abs(2*math.Atan2(s.Rotation.Z, s.Rotation.W)-0.15) < 1e-12)
+	utils.Equals(t, true, math.Abs(s.Velocity.X-10) < 1e-9)
+
+	// Before the first sample it is held
+	s, _ = b.At(-1)
+	utils.Equals(t, 0., s.Position.X)
+
+	// Past the latest sample it is extrapolated, up to MaxExtrapolation
+	s, _ = b.At(0.4)
+	utils.Equals(t, true, math.Abs(s.Position.X-4) < 1e-9)
+	utils.Equals(t, true, math.Abs(2*math.Atan2(s.Rotation.Z, s.Rotation.W)-0.4) < 1e-9)
+	s, _ = b.At(2)
+	utils.Equals(t, true, math.Abs(s.Position.X-5) < 1e-9)
+
+	// A given velocity is used for dead reckoning
+	b.Push(Sample{Time: 0.4, Position: *vector3.NewVector3(4, 0, 0), Rotation: yaw(0.4), Velocity: vector3.NewVector3(0, 1, 0)}, 0.45)
+	s, _ = b.At(0.5)
+	utils.Equals(t, true, math.Abs(s.Position.Y-0.1) < 1e-9 && math.Abs(s.Position.X-4) < 1e-9)
+}
+
+func TestBuffer_Capacity(t *testing.T) {
+	b := NewBuffer(0)
+	b.Capacity = 3
+	for i := 0; i < 5; i++ {
+		b.Push(Sample{Time: float64(i)}, float64(i))
+	}
+	utils.Equals(t, 3, b.Len())
+	// Too old and duplicated samples are dropped
+	b.Push(Sample{Time: 0.5}, 5)
+	b.Push(Sample{Time: 4}, 5)
+	utils.Equals(t, 3, b.Len())
+	s, _ := b.At(0)
+	utils.Equals(t, 0., s.Position.X)
+	utils.Equals(t, 0., s.Time)
+}
+
+func TestBuffer_Delay(t *testing.T) {
+	// Steady samples every 50ms don't need more than an interval
+	b := NewBuffer(0)
+	for i := 0; i < 100; i++ {
+		b.Push(Sample{Time: float64(i) * 0.05}, float64(i)*0.05+0.1)
+	}
+	utils.Equals(t, true, math.Abs(b.Interval()-0.05) < 1e-12)
+	utils.Equals(t, true, b.Jitter() < 1e-12)
+	utils.Equals(t, true, math.Abs(b.Delay()-0.05) < 1e-12)
+	// Rendering one interval behind the latest sample, in the sender clock
+	utils.Equals(t, true, math.Abs(b.RenderTime(99*0.05+0.1)-98*0.05) < 1e-9)
+
+	// Jittery arrivals grow the delay
+	j := NewBuffer(0)
+	for i := 0; i < 100; i++ {
+		late := 0.
+		if i%2 == 0 {
+			late = 0.03
+		}
+		j.Push(Sample{Time: float64(i) * 0.05}, float64(i)*0.05+0.1+late)
+	}
+	utils.Equals(t, true, j.Jitter() > 0.025 && j.Jitter() <= 0.03)
+	utils.Equals(t, true, j.Delay() > 0.05+0.075)
+}
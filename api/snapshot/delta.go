@@ -0,0 +1,328 @@
+package snapshot
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// State is the replicated state of an entity
+type State struct {
+	Position vector3.Vector3
+	Rotation quaternion.Quaternion
+}
+
+// fields returns the components of the state in the order of the bits of the delta flags
+func (s State) fields() [7]float64 {
+	return [7]float64{s.Position.X, s.Position.Y, s.Position.Z, s.Rotation.X, s.Rotation.Y, s.Rotation.Z, s.Rotation.W}
+}
+
+func stateOf(f [7]float64) State {
+	return State{
+		Position: *vector3.NewVector3(f[0], f[1], f[2]),
+		Rotation: *quaternion.NewQuaternion(f[3], f[4], f[5], f[6]),
+	}
+}
+
+// Snapshot is the state of every entity at a point of the simulation, sequences start at 1
+type Snapshot struct {
+	Sequence uint32
+	Entities map[uint64]State
+}
+
+// sortedIDs returns the ids of the entities in increasing order
+func sortedIDs(entities map[uint64]State) []uint64 {
+	ids := make([]uint64, 0, len(entities))
+	for id := range entities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	return append(buf, scratch[:binary.PutUvarint(scratch[:], v)]...)
+}
+
+func appendFloat64(buf []byte, v float64) []byte {
+	var scratch [8]byte
+	binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v))
+	return append(buf, scratch[:]...)
+}
+
+// Encoder writes the snapshots sent to one peer as deltas from the latest snapshot it acknowledged
+// Snapshots sent and not acknowledged yet are kept up to MaxHistory, 64 when 0
+type Encoder struct {
+	MaxHistory int
+	sequence   uint32
+	baseline   uint32
+	// history holds the baseline and the pending snapshots, sent after it in increasing sequence
+	history map[uint32]map[uint64]State
+	pending []uint32
+}
+
+// NewEncoder returns an encoder without baseline, its first snapshot holds every entity
+func NewEncoder() *Encoder {
+	return &Encoder{history: make(map[uint32]map[uint64]State)}
+}
+
+// Encode returns the delta of the entities from the baseline, numbered with the next sequence
+// Only the entities added, removed or changed since the baseline are written, with their changed fields
+// The wire format is made of little endian varints and float64:
+//
+//	sequence, baseline (0 when none), removed count, removed ids (delta from the previous one),
+//	changed count, then per changed entity its id (delta), a byte of flags of the fields written, the fields
+//
+// Fields of the added entities are relative to the zero state
+func (e *Encoder) Encode(entities map[uint64]State) []byte {
+	e.sequence++
+	copied := make(map[uint64]State, len(entities))
+	for id, s := range entities {
+		copied[id] = s
+	}
+	base := e.history[e.baseline]
+
+	buf := make([]byte, 0, 16+len(entities)*8)
+	buf = appendUvarint(buf, uint64(e.sequence))
+	buf = appendUvarint(buf, uint64(e.baseline))
+
+	var removed []uint64
+	for _, id := range sortedIDs(base) {
+		if _, ok := copied[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	buf = appendUvarint(buf, uint64(len(removed)))
+	previous := uint64(0)
+	for _, id := range removed {
+		buf = appendUvarint(buf, id-previous)
+		previous = id
+	}
+
+	type change struct {
+		id    uint64
+		flags byte
+		f     [7]float64
+	}
+	var changes []change
+	for _, id := range sortedIDs(copied) {
+		from, to := base[id].fields(), copied[id].fields()
+		c := change{id: id, f: to}
+		for i := range to {
+			if math.Float64bits(from[i]) != math.Float64bits(to[i]) {
+				c.flags |= 1 << i
+			}
+		}
+		if _, ok := base[id]; ok && c.flags == 0 {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	buf = appendUvarint(buf, uint64(len(changes)))
+	previous = 0
+	for _, c := range changes {
+		buf = appendUvarint(buf, c.id-previous)
+		previous = c.id
+		buf = append(buf, c.flags)
+		for i, v := range c.f {
+			if c.flags&(1<<i) != 0 {
+				buf = appendFloat64(buf, v)
+			}
+		}
+	}
+
+	e.history[e.sequence] = copied
+	e.pending = append(e.pending, e.sequence)
+	e.trim()
+	return buf
+}
+
+// trim drops the oldest snapshots waiting for an acknowledgement past MaxHistory
+func (e *Encoder) trim() {
+	max := e.MaxHistory
+	if max <= 0 {
+		max = 64
+	}
+	for len(e.pending) > max {
+		delete(e.history, e.pending[0])
+		e.pending = e.pending[1:]
+	}
+}
+
+// Ack records that the peer received the snapshot of sequence, which becomes the baseline if it is the
+// most recent acknowledged. Older snapshots are forgotten
+func (e *Encoder) Ack(sequence uint32) {
+	if _, ok := e.history[sequence]; !ok || sequence <= e.baseline {
+		return
+	}
+	delete(e.history, e.baseline)
+	kept := e.pending[:0]
+	for _, s := range e.pending {
+		switch {
+		case s < sequence:
+			delete(e.history, s)
+		case s > sequence:
+			kept = append(kept, s)
+		}
+	}
+	e.pending = kept
+	e.baseline = sequence
+}
+
+// Baseline returns the sequence of the snapshot the deltas are written from, 0 when none
+func (e *Encoder) Baseline() uint32 {
+	return e.baseline
+}
+
+// Decoder rebuilds the snapshots from the deltas of an Encoder, keeping the ones received since the
+// baseline of the latest delta as they may be the baselines of the next ones
+// Besides that baseline, the latest snapshots received are kept up to MaxHistory, 64 when 0, which should
+// match the MaxHistory of the Encoder
+type Decoder struct {
+	MaxHistory int
+	baseline   uint32
+	received   map[uint32]map[uint64]State
+}
+
+// NewDecoder returns a decoder without snapshots
+func NewDecoder() *Decoder {
+	return &Decoder{received: make(map[uint32]map[uint64]State)}
+}
+
+// trim drops the oldest snapshots past MaxHistory, the one of the latest baseline excepted
+func (d *Decoder) trim() {
+	max := d.MaxHistory
+	if max <= 0 {
+		max = 64
+	}
+	if len(d.received) <= max {
+		return
+	}
+	var sequences []uint32
+	for s := range d.received {
+		if s != d.baseline {
+			sequences = append(sequences, s)
+		}
+	}
+	if len(sequences) <= max {
+		return
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	for _, s := range sequences[:len(sequences)-max] {
+		delete(d.received, s)
+	}
+}
+
+// reader reads varints and float64 from a delta, remembering the first error
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = utils.ErrSnapshotMalformed
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil || len(r.data) < 1 {
+		r.err = utils.ErrSnapshotMalformed
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *reader) float64() float64 {
+	if r.err != nil || len(r.data) < 8 {
+		r.err = utils.ErrSnapshotMalformed
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.data))
+	r.data = r.data[8:]
+	return v
+}
+
+// count reads a number of items of at least size bytes each, checking they fit in the rest of the data
+func (r *reader) count(size int) int {
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.data)/size) {
+		r.err = utils.ErrSnapshotMalformed
+		return 0
+	}
+	return int(n)
+}
+
+// Decode returns the snapshot written by Encoder.Encode, whose sequence is to be acknowledged to the encoder
+func (d *Decoder) Decode(data []byte) (Snapshot, error) {
+	r := &reader{data: data}
+	seq, from := r.uvarint(), r.uvarint()
+	if r.err != nil {
+		return Snapshot{}, r.err
+	}
+	if seq == 0 || seq > math.MaxUint32 || from >= seq {
+		return Snapshot{}, utils.ErrSnapshotMalformed
+	}
+	sequence, baseline := uint32(seq), uint32(from)
+	base, ok := d.received[baseline]
+	if baseline != 0 && !ok {
+		return Snapshot{}, utils.ErrSnapshotUnknownBaseline
+	}
+	entities := make(map[uint64]State, len(base))
+	for id, s := range base {
+		entities[id] = s
+	}
+	id := uint64(0)
+	for i, n := 0, r.count(1); i < n; i++ {
+		id += r.uvarint()
+		delete(entities, id)
+	}
+	id = 0
+	for i, n := 0, r.count(2); i < n; i++ {
+		id += r.uvarint()
+		flags := r.byte()
+		f := entities[id].fields()
+		for k := range f {
+			if flags&(1<<k) != 0 {
+				f[k] = r.float64()
+			}
+		}
+		entities[id] = stateOf(f)
+	}
+	if r.err != nil {
+		return Snapshot{}, r.err
+	}
+	if len(r.data) != 0 {
+		return Snapshot{}, utils.ErrSnapshotMalformed
+	}
+
+	d.received[sequence] = entities
+	if baseline > d.baseline {
+		d.baseline = baseline
+		for s := range d.received {
+			if s < baseline {
+				delete(d.received, s)
+			}
+		}
+	}
+	d.trim()
+	copied := make(map[uint64]State, len(entities))
+	for id, s := range entities {
+		copied[id] = s
+	}
+	return Snapshot{Sequence: sequence, Entities: copied}, nil
+}
@@ -0,0 +1,130 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func state(x, y, z float64) State {
+	return State{Position: *vector3.NewVector3(x, y, z), Rotation: *quaternion.NewQuaternion(0, 0, 0, 1)}
+}
+
+func TestDelta(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+	world := map[uint64]State{1: state(1, 2, 3), 2: state(4, 5, 6), 40: state(7, 8, 9)}
+
+	// Without baseline every entity is written
+	full := e.Encode(world)
+	s, err := d.Decode(full)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, uint32(1), s.Sequence)
+	utils.Equals(t, world, s.Entities)
+	e.Ack(s.Sequence)
+	utils.Equals(t, uint32(1), e.Baseline())
+
+	// Nothing changed, nothing written but the header
+	empty := e.Encode(world)
+	utils.Equals(t, []byte{2, 1, 0, 0}, empty)
+	s, err = d.Decode(empty)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, world, s.Entities)
+
+	// One field moved, one entity removed and one added
+	world[2] = state(4, 5.5, 6)
+	delete(world, 40)
+	world[41] = state(0, 0, 1)
+	delta := e.Encode(world)
+	// Header, removed 40, changed 2 with y and 41 with z and w
+	utils.Equals(t, 3+1+1+(1+1+8)+(1+1+16), len(delta))
+	s, err = d.Decode(delta)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, uint32(3), s.Sequence)
+	utils.Equals(t, world, s.Entities)
+	utils.Equals(t, true, len(delta) < len(full))
+}
+
+func TestDeltaLoss(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+	world := map[uint64]State{1: state(0, 0, 0)}
+	s, _ := d.Decode(e.Encode(world))
+	e.Ack(s.Sequence)
+
+	// Lost packets and acks keep the deltas relative to the last acknowledged snapshot
+	for sequence := 2; sequence <= 6; sequence++ {
+		world[1] = state(float64(sequence), 0, 0)
+		delta := e.Encode(world)
+		if sequence%2 == 1 {
+			continue
+		}
+		s, err := d.Decode(delta)
+		utils.Equals(t, nil, err)
+		utils.Equals(t, world, s.Entities)
+	}
+	utils.Equals(t, uint32(1), e.Baseline())
+
+	// Out of order acks only move the baseline forward
+	e.Ack(5)
+	e.Ack(4)
+	utils.Equals(t, uint32(5), e.Baseline())
+	// The peer never got 5, the deltas can't be decoded
+	world[1] = state(9, 0, 0)
+	_, err := d.Decode(e.Encode(world))
+	utils.Equals(t, utils.ErrSnapshotUnknownBaseline, err)
+
+	// Unknown sequences are ignored
+	e.Ack(100)
+	utils.Equals(t, uint32(5), e.Baseline())
+}
+
+func TestDeltaHistory(t *testing.T) {
+	e := NewEncoder()
+	e.MaxHistory = 2
+	world := map[uint64]State{1: state(0, 0, 0)}
+	for i := 0; i < 4; i++ {
+		e.Encode(world)
+	}
+	// Only the 2 latest snapshots can still be acknowledged
+	e.Ack(2)
+	utils.Equals(t, uint32(0), e.Baseline())
+	e.Ack(3)
+	utils.Equals(t, uint32(3), e.Baseline())
+	utils.Equals(t, 2, len(e.history))
+}
+
+func TestDecoderHistory(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+	d.MaxHistory = 2
+	world := map[uint64]State{1: state(0, 0, 0)}
+	s, _ := d.Decode(e.Encode(world))
+	e.Ack(s.Sequence)
+	// Acks lost, every delta is from the same baseline and the decoder doesn't grow past its history
+	for i := 1; i <= 10; i++ {
+		world[1] = state(float64(i), 0, 0)
+		s, err := d.Decode(e.Encode(world))
+		utils.Equals(t, nil, err)
+		utils.Equals(t, world, s.Entities)
+		utils.Equals(t, true, len(d.received) <= 3)
+	}
+	// The baseline and the latest snapshot are kept
+	_, ok := d.received[1]
+	utils.Equals(t, true, ok)
+	e.Ack(11)
+	world[1] = state(20, 0, 0)
+	s, err := d.Decode(e.Encode(world))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, world, s.Entities)
+}
+
+func TestDecoder_Malformed(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+	data := e.Encode(map[uint64]State{1: state(1, 2, 3)})
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(append([]byte(nil), data...), 0), {0, 0, 0, 0}, {1, 1, 0, 0}, {1, 0, 200, 1}} {
+		_, err := d.Decode(bad)
+		utils.Equals(t, utils.ErrSnapshotMalformed, err)
+	}
+	_, err := d.Decode(data)
+	utils.Equals(t, nil, err)
+}
@@ -0,0 +1,154 @@
+package vector3
+
+import (
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// Vector3Batch holds vectors as a structure of arrays, one slice per component of the same length, for
+// operations on many vectors at once without allocations and with contiguous memory accesses
+type Vector3Batch struct {
+	X, Y, Z []float64
+}
+
+// NewVector3Batch returns a batch of n zero vectors
+func NewVector3Batch(n int) *Vector3Batch {
+	return &Vector3Batch{X: make([]float64, n), Y: make([]float64, n), Z: make([]float64, n)}
+}
+
+// NewVector3BatchOf returns a batch holding a copy of the vectors
+func NewVector3BatchOf(vs []*Vector3) *Vector3Batch {
+	b := NewVector3Batch(len(vs))
+	for i, v := range vs {
+		b.X[i], b.Y[i], b.Z[i] = v.X, v.Y, v.Z
+	}
+	return b
+}
+
+// Len returns the number of vectors in the batch
+func (b *Vector3Batch) Len() int {
+	return len(b.X)
+}
+
+// At returns the i-th vector
+func (b *Vector3Batch) At(i int) Vec3 {
+	return Vec3{b.X[i], b.Y[i], b.Z[i]}
+}
+
+// Set replaces the i-th vector
+func (b *Vector3Batch) Set(i int, v Vec3) {
+	b.X[i], b.Y[i], b.Z[i] = v.X, v.Y, v.Z
+}
+
+// Append adds v at the end of the batch
+func (b *Vector3Batch) Append(v Vec3) {
+	b.X, b.Y, b.Z = append(b.X, v.X), append(b.Y, v.Y), append(b.Z, v.Z)
+}
+
+// Vector3s returns a copy of the vectors as protobuf vectors
+func (b *Vector3Batch) Vector3s() []*Vector3 {
+	out := make([]*Vector3, b.Len())
+	values := make([]Vector3, b.Len())
+	for i := range out {
+		values[i] = Vector3{X: b.X[i], Y: b.Y[i], Z: b.Z[i]}
+		out[i] = &values[i]
+	}
+	return out
+}
+
+// components returns the slices of the components cut to the same length, so that the compiler drops the
+// bounds checks of the loops over them
+func (b *Vector3Batch) components() (x, y, z []float64) {
+	n := b.Len()
+	return b.X[:n], b.Y[:n], b.Z[:n]
+}
+
+// Add adds o to the batch element-wise, failing if they are not of the same length
+// In-place
+func (b *Vector3Batch) Add(o *Vector3Batch) error {
+	return b.AddScaled(o, 1)
+}
+
+// AddScaled adds o scaled by m to the batch element-wise, like positions moved by velocities times a time
+// step, failing if they are not of the same length
+// In-place
+func (b *Vector3Batch) AddScaled(o *Vector3Batch, m float64) error {
+	if o.Len() != b.Len() {
+		return utils.ErrVector3otSameSize
+	}
+	x, y, z := b.components()
+	ox, oy, oz := o.X[:len(x)], o.Y[:len(x)], o.Z[:len(x)]
+	for i := range x {
+		x[i] += ox[i] * m
+	}
+	for i := range y {
+		y[i] += oy[i] * m
+	}
+	for i := range z {
+		z[i] += oz[i] * m
+	}
+	return nil
+}
+
+// Translate adds v to every vector
+// In-place
+func (b *Vector3Batch) Translate(v Vec3) {
+	x, y, z := b.components()
+	for i := range x {
+		x[i] += v.X
+	}
+	for i := range y {
+		y[i] += v.Y
+	}
+	for i := range z {
+		z[i] += v.Z
+	}
+}
+
+// Scale multiplies every vector by m
+// In-place
+func (b *Vector3Batch) Scale(m float64) {
+	x, y, z := b.components()
+	for i := range x {
+		x[i] *= m
+	}
+	for i := range y {
+		y[i] *= m
+	}
+	for i := range z {
+		z[i] *= m
+	}
+}
+
+// Dot writes the dot products of the vectors of b and o to out, failing if the three are not of the same
+// length
+func (b *Vector3Batch) Dot(o *Vector3Batch, out []float64) error {
+	if o.Len() != b.Len() || len(out) != b.Len() {
+		return utils.ErrVector3otSameSize
+	}
+	x, y, z := b.components()
+	ox, oy, oz := o.X[:len(x)], o.Y[:len(x)], o.Z[:len(x)]
+	out = out[:len(x)]
+	for i := range out {
+		out[i] = x[i]*ox[i] + y[i]*oy[i] + z[i]*oz[i]
+	}
+	return nil
+}
+
+// Transform scales every vector, then rotates it by the unit quaternion and finally moves it by position,
+// like the points of a scene node
+// In-place
+func (b *Vector3Batch) Transform(position Vec3, rotation quaternion.Quaternion, scale float64) {
+	// The rotation as a matrix, scaled
+	qx, qy, qz, qw := rotation.X, rotation.Y, rotation.Z, rotation.W
+	m00, m01, m02 := (1-2*(qy*qy+qz*qz))*scale, 2*(qx*qy-qz*qw)*scale, 2*(qx*qz+qy*qw)*scale
+	m10, m11, m12 := 2*(qx*qy+qz*qw)*scale, (1-2*(qx*qx+qz*qz))*scale, 2*(qy*qz-qx*qw)*scale
+	m20, m21, m22 := 2*(qx*qz-qy*qw)*scale, 2*(qy*qz+qx*qw)*scale, (1-2*(qx*qx+qy*qy))*scale
+	x, y, z := b.components()
+	for i := range x {
+		px, py, pz := x[i], y[i], z[i]
+		x[i] = m00*px + m01*py + m02*pz + position.X
+		y[i] = m10*px + m11*py + m12*pz + position.Y
+		z[i] = m20*px + m21*py + m22*pz + position.Z
+	}
+}
@@ -0,0 +1,106 @@
+package vector3
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func randomVectors(r *rand.Rand, n int) []*Vector3 {
+	vs := make([]*Vector3, n)
+	for i := range vs {
+		vs[i] = NewVector3(r.Float64()*20-10, r.Float64()*20-10, r.Float64()*20-10)
+	}
+	return vs
+}
+
+func TestVector3Batch(t *testing.T) {
+	b := NewVector3Batch(2)
+	utils.Equals(t, 2, b.Len())
+	b.Set(1, Vec3{1, 2, 3})
+	b.Append(Vec3{4, 5, 6})
+	utils.Equals(t, 3, b.Len())
+	utils.Equals(t, Vec3{}, b.At(0))
+	utils.Equals(t, Vec3{4, 5, 6}, b.At(2))
+
+	vs := b.Vector3s()
+	utils.Equals(t, 3, len(vs))
+	utils.Equals(t, true, vs[1].Equal(*NewVector3(1, 2, 3)))
+	// The vectors returned are copies
+	vs[1].X = 10
+	utils.Equals(t, 1., b.At(1).X)
+	utils.Equals(t, Vec3{10, 2, 3}, NewVector3BatchOf(vs).At(1))
+}
+
+func TestVector3Batch_Arithmetic(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	as, bs := randomVectors(r, 100), randomVectors(r, 100)
+	a, b := NewVector3BatchOf(as), NewVector3BatchOf(bs)
+
+	dots := make([]float64, 100)
+	utils.Equals(t, nil, a.Dot(b, dots))
+	for i := range dots {
+		utils.Equals(t, as[i].Dot(*bs[i]), dots[i])
+	}
+
+	utils.Equals(t, nil, a.Add(b))
+	for i := range as {
+		utils.Equals(t, as[i].Vec3().Plus(bs[i].Vec3()), a.At(i))
+	}
+	utils.Equals(t, nil, a.AddScaled(b, -1))
+	a.Scale(2)
+	a.Translate(Vec3{1, 0, -1})
+	for i := range as {
+		utils.Equals(t, true, a.At(i).Distance(as[i].Vec3().Times(2).Plus(Vec3{1, 0, -1})) < 1e-12)
+	}
+
+	short := NewVector3Batch(99)
+	utils.Equals(t, utils.ErrVector3otSameSize, a.Add(short))
+	utils.Equals(t, utils.ErrVector3otSameSize, a.Dot(short, dots))
+	utils.Equals(t, utils.ErrVector3otSameSize, a.Dot(b, dots[:99]))
+}
+
+func TestVector3Batch_Transform(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	vs := randomVectors(r, 100)
+	b := NewVector3BatchOf(vs)
+	q := quaternion.ToQuaternion(0.3, 1.2, -2.1)
+	position := Vec3{1, -2, 3}
+	b.Transform(position, *q, 1.5)
+	for i, v := range vs {
+		exp := v.Times(1.5).Rotate(*q).Vec3().Plus(position)
+		utils.Equals(t, true, b.At(i).Distance(exp) < 1e-12)
+	}
+}
+
+func BenchmarkVector3Batch_Transform(b *testing.B) {
+	batch := NewVector3BatchOf(randomVectors(rand.New(rand.NewSource(1)), 100000))
+	q := quaternion.ToQuaternion(0.3, 1.2, -2.1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch.Transform(Vec3{1, 2, 3}, *q, 1)
+	}
+}
+
+func BenchmarkVector3Batch_AddScaled(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	positions, velocities := NewVector3BatchOf(randomVectors(r, 100000)), NewVector3BatchOf(randomVectors(r, 100000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		positions.AddScaled(velocities, 1./60)
+	}
+}
+
+// The same step on protobuf vectors, for comparison
+func BenchmarkVector3_AddScaled(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	positions, velocities := randomVectors(r, 100000), randomVectors(r, 100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, p := range positions {
+			*p = p.Plus(velocities[j].Times(1. / 60))
+		}
+	}
+}
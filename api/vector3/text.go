@@ -0,0 +1,27 @@
+package vector3
+
+import (
+	"fmt"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// Format writes the vector as "(x, y, z)" for the fmt package, the components formatted with the verb,
+// %v giving their shortest exact form and %#v Go syntax
+// The generated String method keeps the protobuf text form
+func (v Vector3) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "vector3.Vector3{X:%#v, Y:%#v, Z:%#v}", v.X, v.Y, v.Z)
+		return
+	}
+	utils.WriteTuple(f, verb, v.X, v.Y, v.Z)
+}
+
+// ParseVector3 parses a vector written as "(x, y, z)", returning ErrTextMalformed otherwise
+func ParseVector3(s string) (*Vector3, error) {
+	c, err := utils.ParseTuple(s, 3)
+	if err != nil {
+		return nil, err
+	}
+	return NewVector3(c[0], c[1], c[2]), nil
+}
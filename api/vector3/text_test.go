@@ -0,0 +1,41 @@
+package vector3
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestVector3_Format(t *testing.T) {
+	v := NewVector3(1, -2.5, 0.1)
+	utils.Equals(t, "(1, -2.5, 0.1)", fmt.Sprint(v))
+	utils.Equals(t, "(1, -2.5, 0.1)", fmt.Sprintf("%v", *v))
+	utils.Equals(t, "(1.00, -2.50, 0.10)", fmt.Sprintf("%.2f", v))
+	utils.Equals(t, "(+1, -2.5, +0.1)", fmt.Sprintf("%+g", v))
+	utils.Equals(t, "vector3.Vector3{X:1, Y:-2.5, Z:0.1}", fmt.Sprintf("%#v", v))
+	utils.Equals(t, "(NaN, +Inf, 1e+300)", fmt.Sprint(NewVector3(math.NaN(), math.Inf(1), 1e300)))
+	utils.Equals(t, "<nil>", fmt.Sprint((*Vector3)(nil)))
+	// The protobuf text form is kept
+	utils.Equals(t, "x:1 y:-2.5 z:0.1 ", v.String())
+}
+
+func TestParseVector3(t *testing.T) {
+	for _, v := range []*Vector3{NewVector3(1, -2.5, 0.1), NewVector3(1.0/3, 1e-300, -7e22), NewVector3Max()} {
+		got, err := ParseVector3(fmt.Sprint(v))
+		utils.Equals(t, nil, err)
+		utils.Equals(t, *v, *got)
+	}
+	got, err := ParseVector3("  ( 1,2 ,  3e2 ) ")
+	utils.Equals(t, nil, err)
+	utils.Equals(t, *NewVector3(1, 2, 300), *got)
+	got, err = ParseVector3("(-Inf, inf, NaN)")
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, math.IsInf(got.X, -1) && math.IsInf(got.Y, 1) && math.IsNaN(got.Z))
+
+	for _, s := range []string{"", "()", "1, 2, 3", "(1, 2)", "(1, 2, 3, 4)", "(1, , 3)", "(1, 2, x)", "((1, 2, 3)", "(1, 2, 3))"} {
+		_, err := ParseVector3(s)
+		utils.Equals(t, utils.ErrTextMalformed, err)
+	}
+}
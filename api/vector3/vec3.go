@@ -0,0 +1,171 @@
+package vector3
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/louis030195/protometry/api/quaternion"
+)
+
+// Vec3 is a plain 3-d vector, without the protobuf fields of Vector3, for hot loops
+// It has the layout of the start of Vector3 so that a *Vector3 can be used as a *Vec3 without copy
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// Vec3f is a plain 3-d vector of float32, half the memory of Vec3
+type Vec3f struct {
+	X, Y, Z float32
+}
+
+// AsVec3 needs the components at the start of Vector3 like in Vec3, these fail to compile otherwise
+var (
+	_ = [1]struct{}{}[unsafe.Offsetof(Vector3{}.X)]
+	_ = [1]struct{}{}[unsafe.Offsetof(Vector3{}.Y)-unsafe.Offsetof(Vec3{}.Y)]
+	_ = [1]struct{}{}[unsafe.Offsetof(Vector3{}.Z)-unsafe.Offsetof(Vec3{}.Z)]
+)
+
+// AsVec3 returns v seen as a Vec3, sharing its memory: writes through one are seen by the other
+func AsVec3(v *Vector3) *Vec3 {
+	return (*Vec3)(unsafe.Pointer(&v.X))
+}
+
+// Vec3 returns a copy of the components of v
+func (v Vector3) Vec3() Vec3 {
+	return Vec3{v.X, v.Y, v.Z}
+}
+
+// Vector3 returns the protobuf vector of the components of v
+func (v Vec3) Vector3() Vector3 {
+	return Vector3{X: v.X, Y: v.Y, Z: v.Z}
+}
+
+// Vec3f returns v rounded to float32
+func (v Vec3) Vec3f() Vec3f {
+	return Vec3f{float32(v.X), float32(v.Y), float32(v.Z)}
+}
+
+// Plus returns the sum of v and v2
+func (v Vec3) Plus(v2 Vec3) Vec3 {
+	return Vec3{v.X + v2.X, v.Y + v2.Y, v.Z + v2.Z}
+}
+
+// Minus returns the difference of v and v2
+func (v Vec3) Minus(v2 Vec3) Vec3 {
+	return Vec3{v.X - v2.X, v.Y - v2.Y, v.Z - v2.Z}
+}
+
+// Times returns v scaled by m
+func (v Vec3) Times(m float64) Vec3 {
+	return Vec3{v.X * m, v.Y * m, v.Z * m}
+}
+
+// Dot returns the dot product of v and v2
+func (v Vec3) Dot(v2 Vec3) float64 {
+	return v.X*v2.X + v.Y*v2.Y + v.Z*v2.Z
+}
+
+// Cross returns the cross product of v and v2
+func (v Vec3) Cross(v2 Vec3) Vec3 {
+	return Vec3{v.Y*v2.Z - v.Z*v2.Y, v.Z*v2.X - v.X*v2.Z, v.X*v2.Y - v.Y*v2.X}
+}
+
+// Norm returns the square of the length of v, like Vector3.Norm
+func (v Vec3) Norm() float64 {
+	return v.Dot(v)
+}
+
+// Norm2 returns the length of v, like Vector3.Norm2
+func (v Vec3) Norm2() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Normalize returns the unit vector in the direction of v, the zero vector if v is zero
+func (v Vec3) Normalize() Vec3 {
+	n := v.Norm2()
+	if n == 0 {
+		return Vec3{}
+	}
+	return Vec3{v.X / n, v.Y / n, v.Z / n}
+}
+
+// Distance returns the distance between v and v2
+func (v Vec3) Distance(v2 Vec3) float64 {
+	return v.Minus(v2).Norm2()
+}
+
+// Lerp returns the linear interpolation from v to v2 by t
+func (v Vec3) Lerp(v2 Vec3, t float64) Vec3 {
+	return Vec3{v.X + (v2.X-v.X)*t, v.Y + (v2.Y-v.Y)*t, v.Z + (v2.Z-v.Z)*t}
+}
+
+// Rotate returns the vector rotated by the unit quaternion q
+func (v Vec3) Rotate(q quaternion.Quaternion) Vec3 {
+	u := Vec3{q.X, q.Y, q.Z}
+	t := u.Cross(v).Times(2)
+	return v.Plus(t.Times(q.W)).Plus(u.Cross(t))
+}
+
+// Vec3 returns v widened to float64
+func (v Vec3f) Vec3() Vec3 {
+	return Vec3{float64(v.X), float64(v.Y), float64(v.Z)}
+}
+
+// Vector3 returns the protobuf vector of the components of v
+func (v Vec3f) Vector3() Vector3 {
+	return Vector3{X: float64(v.X), Y: float64(v.Y), Z: float64(v.Z)}
+}
+
+// Plus returns the sum of v and v2
+func (v Vec3f) Plus(v2 Vec3f) Vec3f {
+	return Vec3f{v.X + v2.X, v.Y + v2.Y, v.Z + v2.Z}
+}
+
+// Minus returns the difference of v and v2
+func (v Vec3f) Minus(v2 Vec3f) Vec3f {
+	return Vec3f{v.X - v2.X, v.Y - v2.Y, v.Z - v2.Z}
+}
+
+// Times returns v scaled by m
+func (v Vec3f) Times(m float32) Vec3f {
+	return Vec3f{v.X * m, v.Y * m, v.Z * m}
+}
+
+// Dot returns the dot product of v and v2
+func (v Vec3f) Dot(v2 Vec3f) float32 {
+	return v.X*v2.X + v.Y*v2.Y + v.Z*v2.Z
+}
+
+// Cross returns the cross product of v and v2
+func (v Vec3f) Cross(v2 Vec3f) Vec3f {
+	return Vec3f{v.Y*v2.Z - v.Z*v2.Y, v.Z*v2.X - v.X*v2.Z, v.X*v2.Y - v.Y*v2.X}
+}
+
+// Norm returns the square of the length of v
+func (v Vec3f) Norm() float32 {
+	return v.Dot(v)
+}
+
+// Norm2 returns the length of v
+func (v Vec3f) Norm2() float32 {
+	return float32(math.Sqrt(float64(v.Dot(v))))
+}
+
+// Normalize returns the unit vector in the direction of v, the zero vector if v is zero
+func (v Vec3f) Normalize() Vec3f {
+	n := v.Norm2()
+	if n == 0 {
+		return Vec3f{}
+	}
+	return Vec3f{v.X / n, v.Y / n, v.Z / n}
+}
+
+// Distance returns the distance between v and v2
+func (v Vec3f) Distance(v2 Vec3f) float32 {
+	return v.Minus(v2).Norm2()
+}
+
+// Lerp returns the linear interpolation from v to v2 by t
+func (v Vec3f) Lerp(v2 Vec3f, t float32) Vec3f {
+	return Vec3f{v.X + (v2.X-v.X)*t, v.Y + (v2.Y-v.Y)*t, v.Z + (v2.Z-v.Z)*t}
+}
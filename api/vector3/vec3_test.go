@@ -0,0 +1,61 @@
+package vector3
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/quaternion"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestAsVec3(t *testing.T) {
+	v := NewVector3(1, 2, 3)
+	p := AsVec3(v)
+	utils.Equals(t, Vec3{1, 2, 3}, *p)
+	// The memory is shared both ways
+	p.Y = 5
+	utils.Equals(t, 5., v.Y)
+	v.Z = -1
+	utils.Equals(t, Vec3{1, 5, -1}, *p)
+	utils.Equals(t, Vec3{1, 5, -1}, v.Vec3())
+	utils.Equals(t, true, p.Vector3().Equal(*v))
+}
+
+func TestVec3(t *testing.T) {
+	a, b := Vec3{1, 2, 3}, Vec3{4, -5, 6}
+	utils.Equals(t, Vec3{5, -3, 9}, a.Plus(b))
+	utils.Equals(t, Vec3{-3, 7, -3}, a.Minus(b))
+	utils.Equals(t, Vec3{2, 4, 6}, a.Times(2))
+	utils.Equals(t, 12., a.Dot(b))
+	utils.Equals(t, Vec3{27, 6, -13}, a.Cross(b))
+	utils.Equals(t, 14., a.Norm())
+	utils.Equals(t, 5., Vec3{3, 0, 4}.Norm2())
+	utils.Equals(t, Vec3{0.6, 0, 0.8}, Vec3{3, 0, 4}.Normalize())
+	utils.Equals(t, Vec3{}, Vec3{}.Normalize())
+	utils.Equals(t, 5., Vec3{3, 0, 0}.Distance(Vec3{0, 4, 0}))
+	utils.Equals(t, Vec3{2.5, -1.5, 4.5}, a.Lerp(b, 0.5))
+
+	q := quaternion.ToQuaternion(0.4, -1.1, 2.3)
+	exp := NewVector3(1, 2, 3).Rotate(*q)
+	utils.Equals(t, true, a.Rotate(*q).Distance(exp.Vec3()) < 1e-12)
+}
+
+func TestVec3f(t *testing.T) {
+	a, b := Vec3f{1, 2, 3}, Vec3f{4, -5, 6}
+	utils.Equals(t, a, Vec3{1, 2, 3}.Vec3f())
+	utils.Equals(t, Vec3{1, 2, 3}, a.Vec3())
+	utils.Equals(t, true, a.Vector3().Equal(*NewVector3(1, 2, 3)))
+	utils.Equals(t, Vec3f{5, -3, 9}, a.Plus(b))
+	utils.Equals(t, Vec3f{-3, 7, -3}, a.Minus(b))
+	utils.Equals(t, Vec3f{0.5, 1, 1.5}, a.Times(0.5))
+	utils.Equals(t, float32(12), a.Dot(b))
+	utils.Equals(t, Vec3f{27, 6, -13}, a.Cross(b))
+	utils.Equals(t, float32(14), a.Norm())
+	utils.Equals(t, float32(5), Vec3f{3, 0, 4}.Norm2())
+	utils.Equals(t, true, math.Abs(float64(Vec3f{1, 1, 1}.Normalize().Norm2())-1) < 1e-6)
+	utils.Equals(t, Vec3f{}, Vec3f{}.Normalize())
+	utils.Equals(t, float32(5), Vec3f{3, 0, 0}.Distance(Vec3f{0, 4, 0}))
+	utils.Equals(t, Vec3f{2.5, -1.5, 4.5}, a.Lerp(b, 0.5))
+	// Rounded to float32
+	utils.Equals(t, float32(0.1), Vec3{0.1, 0, 0}.Vec3f().X)
+}
@@ -73,11 +73,12 @@ func (v Vector3) Norm2() float64 { return math.Sqrt(v.Norm()) }
 
 // Normalize returns a new unit vector in the same direction as a.
 func (v Vector3) Normalize() Vector3 {
-	n2 := v.Norm2()
-	if n2 == 0 {
+	n := v.Norm2()
+	if n == 0 {
 		return *NewVector3(0, 0, 0)
 	}
-	return v.Times(1 / math.Sqrt(n2))
+	v.Divide(n)
+	return v
 }
 
 // Abs returns the vector with non-negative components.
@@ -155,7 +156,7 @@ func (v Vector3) Dot(v2 Vector3) float64 {
 
 // Cross returns the standard cross product of a and b.
 func (v Vector3) Cross(v2 Vector3) *Vector3 {
-	return NewVector3(v.Y*v2.Z-v.Z*v2.Y, v.Z*v2.X-v.X*v2.Z, v.X*v2.Y-v.Y*v.X)
+	return NewVector3(v.Y*v2.Z-v.Z*v2.Y, v.Z*v2.X-v.X*v2.Z, v.X*v2.Y-v.Y*v2.X)
 }
 
 // Distance returns the Euclidean distance between a and b.
@@ -166,6 +167,13 @@ func (v Vector3) Angle(v2 Vector3) float64 {
 	return math.Atan2(v.Cross(v2).Norm(), v.Dot(v2))
 }
 
+// Rotate returns the vector rotated by the unit quaternion q
+func (v Vector3) Rotate(q quaternion.Quaternion) Vector3 {
+	u := *NewVector3(q.X, q.Y, q.Z)
+	t := u.Cross(v).Times(2)
+	return v.Plus(t.Times(q.W)).Plus(*u.Cross(t))
+}
+
 // Min Returns the a vector where each component is the lesser of the
 // corresponding component in this and the specified vector.
 // Not in-place
@@ -208,6 +216,39 @@ func Morton3D(v Vector3) uint { // TODO: decoder
 	return xx*4 + yy*2 + zz
 }
 
+// spreadBits inserts two zeros between each of the 21 lower bits of v
+func spreadBits(v uint64) uint64 {
+	v &= 0x1FFFFF
+	v = (v | v<<32) & 0x1F00000000FFFF
+	v = (v | v<<16) & 0x1F0000FF0000FF
+	v = (v | v<<8) & 0x100F00F00F00F00F
+	v = (v | v<<4) & 0x10C30C30C30C30C3
+	v = (v | v<<2) & 0x1249249249249249
+	return v
+}
+
+// compactBits is the inverse of spreadBits
+func compactBits(v uint64) uint64 {
+	v &= 0x1249249249249249
+	v = (v | v>>2) & 0x10C30C30C30C30C3
+	v = (v | v>>4) & 0x100F00F00F00F00F
+	v = (v | v>>8) & 0x1F0000FF0000FF
+	v = (v | v>>16) & 0x1F00000000FFFF
+	v = (v | v>>32) & 0x1FFFFF
+	return v
+}
+
+// MortonEncode interleaves the 21 lower bits of integer coordinates into a 63-bit Morton code,
+// x taking the most significant bit of each triplet like Morton3D
+func MortonEncode(x, y, z uint32) uint64 {
+	return spreadBits(uint64(x))<<2 | spreadBits(uint64(y))<<1 | spreadBits(uint64(z))
+}
+
+// MortonDecode returns the integer coordinates of a code built by MortonEncode
+func MortonDecode(code uint64) (x, y, z uint32) {
+	return uint32(compactBits(code >> 2)), uint32(compactBits(code >> 1)), uint32(compactBits(code))
+}
+
 func randFloat(min, max float64) float64 {
 	return min + rand.Float64()*(max-min)
 }
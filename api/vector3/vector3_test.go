@@ -1,6 +1,7 @@
 package vector3
 
 import (
+    "github.com/louis030195/protometry/api/quaternion"
     "github.com/louis030195/protometry/internal/utils"
     "math"
 	"reflect"
@@ -106,6 +107,29 @@ func TestMorton3D(t *testing.T) {
 	}
 }
 
+func TestMortonEncode(t *testing.T) {
+	utils.Equals(t, uint64(0), MortonEncode(0, 0, 0))
+	utils.Equals(t, uint64(4), MortonEncode(1, 0, 0))
+	utils.Equals(t, uint64(2), MortonEncode(0, 1, 0))
+	utils.Equals(t, uint64(1), MortonEncode(0, 0, 1))
+	utils.Equals(t, uint64(1<<63-1), MortonEncode(1<<21-1, 1<<21-1, 1<<21-1))
+	// Same ordering as the float version on the unit cube
+	utils.Equals(t, uint64(Morton3D(*NewVector3(0.5, 0.25, 0.75))), MortonEncode(512, 256, 768))
+	for _, c := range [][3]uint32{{0, 0, 0}, {1, 2, 3}, {1023, 7, 123456}, {1<<21 - 1, 0, 1 << 20}} {
+		x, y, z := MortonDecode(MortonEncode(c[0], c[1], c[2]))
+		utils.Equals(t, c, [3]uint32{x, y, z})
+	}
+}
+
+func TestVector3_Rotate(t *testing.T) {
+	// Quarter turn around y
+	q := quaternion.NewQuaternion(0, math.Sin(math.Pi/4), 0, math.Cos(math.Pi/4))
+	r := NewVector3(1, 0, 0).Rotate(*q)
+	utils.Equals(t, true, r.Minus(*NewVector3(0, 0, -1)).Norm2() < 1e-12)
+	r = NewVector3(1, 2, 3).Rotate(*quaternion.NewQuaternion(0, 0, 0, 1))
+	utils.Equals(t, *NewVector3(1, 2, 3), r)
+}
+
 func TestMin(t *testing.T) {
 	type args struct {
 		a Vector3
@@ -317,3 +341,15 @@ func TestVector3_Equal(t *testing.T) {
         })
     }
 }
+
+func TestVector3_Normalize(t *testing.T) {
+	a := NewVector3(3, 0, 4)
+	utils.Equals(t, true, a.Normalize().Equal(*NewVector3(0.6, 0, 0.8)))
+	utils.Equals(t, true, NewVector3Zero().Normalize().Equal(*NewVector3Zero()))
+}
+
+func TestVector3_Cross(t *testing.T) {
+	a := NewVector3(1, 2, 3)
+	b := NewVector3(4, 5, 6)
+	utils.Equals(t, true, a.Cross(*b).Equal(*NewVector3(-3, 6, -3)))
+}
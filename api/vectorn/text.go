@@ -0,0 +1,32 @@
+package vectorn
+
+import (
+	"fmt"
+
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// Format writes the vector as "(a, b, c, ...)" for the fmt package, like vector3.Vector3.Format
+func (v VectorN) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "vectorn.VectorN{Dimensions:%#v}", v.Dimensions)
+		return
+	}
+	utils.WriteTuple(f, verb, v.Dimensions...)
+}
+
+// ParseVectorN parses a vector written as "(a, b, c, ...)" of any number of dimensions, returning
+// ErrTextMalformed otherwise
+func ParseVectorN(s string) (*VectorN, error) {
+	items, err := utils.SplitTuple(s)
+	if err != nil {
+		return nil, err
+	}
+	d := make([]float64, len(items))
+	for i, item := range items {
+		if d[i], err = utils.ParseFloat(item); err != nil {
+			return nil, err
+		}
+	}
+	return &VectorN{Dimensions: d}, nil
+}
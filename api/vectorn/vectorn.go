@@ -0,0 +1,257 @@
+package vectorn
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// NewVectorN constructs a VectorN of the given dimensions, copying them
+func NewVectorN(dimensions ...float64) *VectorN {
+	d := make([]float64, len(dimensions))
+	copy(d, dimensions)
+	return &VectorN{Dimensions: d}
+}
+
+// NewVectorNZero constructs a VectorN of n dimensions initialized with 0
+func NewVectorNZero(n int) *VectorN {
+	return &VectorN{Dimensions: make([]float64, n)}
+}
+
+// NewVectorNOne constructs a VectorN of n dimensions initialized with 1
+func NewVectorNOne(n int) *VectorN {
+	return NewVectorNZero(n).Fill(1)
+}
+
+// FromVector3 returns the VectorN of 3 dimensions of v
+func FromVector3(v vector3.Vector3) *VectorN {
+	return NewVectorN(v.X, v.Y, v.Z)
+}
+
+// Vector3 returns v as a Vector3, failing with ErrVectorInvalidDimension if it is not of 3 dimensions
+func (v VectorN) Vector3() (*vector3.Vector3, error) {
+	if v.Len() != 3 {
+		return nil, utils.ErrVectorInvalidDimension
+	}
+	return vector3.NewVector3(v.Dimensions[0], v.Dimensions[1], v.Dimensions[2]), nil
+}
+
+// Clone a vector
+func (v *VectorN) Clone() *VectorN {
+	return NewVectorN(v.Dimensions...)
+}
+
+// Len returns the number of dimensions of the vector
+func (v VectorN) Len() int {
+	return len(v.Dimensions)
+}
+
+// Get returns the i-th dimension, failing with ErrVectorInvalidIndex if it is out of range
+func (v VectorN) Get(i int) (float64, error) {
+	if i < 0 || i >= v.Len() {
+		return 0, utils.ErrVectorInvalidIndex
+	}
+	return v.Dimensions[i], nil
+}
+
+// Set replaces the i-th dimension, failing with ErrVectorInvalidIndex if it is out of range
+// In-place
+func (v *VectorN) Set(i int, x float64) error {
+	if i < 0 || i >= v.Len() {
+		return utils.ErrVectorInvalidIndex
+	}
+	v.Dimensions[i] = x
+	return nil
+}
+
+// Fill sets every dimension to x and returns v
+// In-place
+func (v *VectorN) Fill(x float64) *VectorN {
+	for i := range v.Dimensions {
+		v.Dimensions[i] = x
+	}
+	return v
+}
+
+// Equal reports whether v and v2 have the same dimensions within a small epsilon, like vector3.Vector3.Equal
+func (v VectorN) Equal(v2 VectorN) bool {
+	const epsilon = 1e-16
+	if v.Len() != v2.Len() {
+		return false
+	}
+	for i, x := range v.Dimensions {
+		if math.Abs(x-v2.Dimensions[i]) >= epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// sameSize returns ErrVector3otSameSize if v and v2 have not the same number of dimensions
+func sameSize(v, v2 VectorN) error {
+	if v.Len() != v2.Len() {
+		return utils.ErrVector3otSameSize
+	}
+	return nil
+}
+
+// combine returns the vector of f applied to the dimensions of v and v2, failing if they are not the same size
+func combine(v, v2 VectorN, f func(a, b float64) float64) (VectorN, error) {
+	if err := sameSize(v, v2); err != nil {
+		return VectorN{}, err
+	}
+	d := make([]float64, v.Len())
+	for i, x := range v.Dimensions {
+		d[i] = f(x, v2.Dimensions[i])
+	}
+	return VectorN{Dimensions: d}, nil
+}
+
+// Sum returns the sum of all the dimensions of the vector
+func (v VectorN) Sum() float64 {
+	s := 0.
+	for _, x := range v.Dimensions {
+		s += x
+	}
+	return s
+}
+
+// Abs returns the vector with non-negative dimensions
+// Not in-place
+func (v VectorN) Abs() VectorN {
+	d := make([]float64, v.Len())
+	for i, x := range v.Dimensions {
+		d[i] = math.Abs(x)
+	}
+	return VectorN{Dimensions: d}
+}
+
+// Plus returns the sum of v and v2, failing if they are not the same size
+// Not in-place
+func (v VectorN) Plus(v2 VectorN) (VectorN, error) {
+	return combine(v, v2, func(a, b float64) float64 { return a + b })
+}
+
+// Add adds v2 to v element-wise, failing if they are not the same size
+// In-place
+func (v *VectorN) Add(v2 *VectorN) error {
+	if err := sameSize(*v, *v2); err != nil {
+		return err
+	}
+	for i, x := range v2.Dimensions {
+		v.Dimensions[i] += x
+	}
+	return nil
+}
+
+// Minus returns the difference of v and v2, failing if they are not the same size
+// Not in-place
+func (v VectorN) Minus(v2 VectorN) (VectorN, error) {
+	return combine(v, v2, func(a, b float64) float64 { return a - b })
+}
+
+// Subtract subtracts v2 from v element-wise, failing if they are not the same size
+// In-place
+func (v *VectorN) Subtract(v2 *VectorN) error {
+	if err := sameSize(*v, *v2); err != nil {
+		return err
+	}
+	for i, x := range v2.Dimensions {
+		v.Dimensions[i] -= x
+	}
+	return nil
+}
+
+// Times returns the scalar product of v and m
+// Not in-place
+func (v VectorN) Times(m float64) VectorN {
+	c := v.Clone()
+	c.Scale(m)
+	return *c
+}
+
+// Scale rescales the vector by m
+// In-place
+func (v *VectorN) Scale(m float64) {
+	for i := range v.Dimensions {
+		v.Dimensions[i] *= m
+	}
+}
+
+// Divide will return infinities or NaN in case of division by 0
+// In-place
+func (v *VectorN) Divide(m float64) {
+	for i := range v.Dimensions {
+		v.Dimensions[i] /= m
+	}
+}
+
+// Dot returns the dot product of v and v2, failing if they are not the same size
+func (v VectorN) Dot(v2 VectorN) (float64, error) {
+	if err := sameSize(v, v2); err != nil {
+		return 0, err
+	}
+	s := 0.
+	for i, x := range v.Dimensions {
+		s += x * v2.Dimensions[i]
+	}
+	return s, nil
+}
+
+// Norm returns the square of the length of v, like vector3.Vector3.Norm
+func (v VectorN) Norm() float64 {
+	s := 0.
+	for _, x := range v.Dimensions {
+		s += x * x
+	}
+	return s
+}
+
+// Norm2 returns the length of v, like vector3.Vector3.Norm2
+func (v VectorN) Norm2() float64 { return math.Sqrt(v.Norm()) }
+
+// Normalize returns the unit vector in the same direction as v, the zero vector if v is zero
+// Not in-place
+func (v VectorN) Normalize() VectorN {
+	n := v.Norm2()
+	if n == 0 {
+		return *NewVectorNZero(v.Len())
+	}
+	c := v.Clone()
+	c.Divide(n)
+	return *c
+}
+
+// Distance returns the Euclidean distance between v and v2, failing if they are not the same size
+func (v VectorN) Distance(v2 VectorN) (float64, error) {
+	if err := sameSize(v, v2); err != nil {
+		return 0, err
+	}
+	s := 0.
+	for i, x := range v.Dimensions {
+		d := x - v2.Dimensions[i]
+		s += d * d
+	}
+	return math.Sqrt(s), nil
+}
+
+// Lerp returns the linear interpolation between v and v2 by f, failing if they are not the same size
+// Not in-place
+func (v VectorN) Lerp(v2 VectorN, f float64) (VectorN, error) {
+	return combine(v, v2, func(a, b float64) float64 { return (b-a)*f + a })
+}
+
+// Min returns the vector where each dimension is the lesser of the corresponding dimension in v and v2,
+// failing if they are not the same size
+// Not in-place
+func Min(v VectorN, v2 VectorN) (VectorN, error) {
+	return combine(v, v2, math.Min)
+}
+
+// Max returns the vector where each dimension is the greater of the corresponding dimension in v and v2,
+// failing if they are not the same size
+// Not in-place
+func Max(v VectorN, v2 VectorN) (VectorN, error) {
+	return combine(v, v2, math.Max)
+}
@@ -0,0 +1,80 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: vectorn.proto
+
+package vectorn
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// VectorN is a vector of any number of dimensions, like features for machine learning or states of agents
+type VectorN struct {
+	Dimensions           []float64 `protobuf:"fixed64,1,rep,packed,name=dimensions,proto3" json:"dimensions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *VectorN) Reset()         { *m = VectorN{} }
+func (m *VectorN) String() string { return proto.CompactTextString(m) }
+func (*VectorN) ProtoMessage()    {}
+func (*VectorN) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c38cd87c3fa235d4, []int{0}
+}
+
+func (m *VectorN) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VectorN.Unmarshal(m, b)
+}
+func (m *VectorN) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VectorN.Marshal(b, m, deterministic)
+}
+func (m *VectorN) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VectorN.Merge(m, src)
+}
+func (m *VectorN) XXX_Size() int {
+	return xxx_messageInfo_VectorN.Size(m)
+}
+func (m *VectorN) XXX_DiscardUnknown() {
+	xxx_messageInfo_VectorN.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VectorN proto.InternalMessageInfo
+
+func (m *VectorN) GetDimensions() []float64 {
+	if m != nil {
+		return m.Dimensions
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*VectorN)(nil), "protometry.vectorn.VectorN")
+}
+
+func init() {
+	proto.RegisterFile("vectorn.proto", fileDescriptor_c38cd87c3fa235d4)
+}
+
+var fileDescriptor_c38cd87c3fa235d4 = []byte{
+	// 110 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x4b, 0x4d, 0x2e,
+	0xc9, 0x2f, 0xca, 0xd3, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0x02, 0x53, 0xb9, 0xa9, 0x25,
+	0x45, 0x95, 0x7a, 0x50, 0x19, 0x25, 0x4d, 0x2e, 0xf6, 0x30, 0x30, 0xd3, 0x4f, 0x48, 0x8e, 0x8b,
+	0x2b, 0x25, 0x33, 0x37, 0x35, 0xaf, 0x38, 0x33, 0x3f, 0xaf, 0x58, 0x82, 0x51, 0x81, 0x59, 0x83,
+	0x31, 0x08, 0x49, 0xc4, 0xc9, 0x91, 0x4b, 0x2c, 0x39, 0x3f, 0x57, 0x0f, 0xd3, 0x10, 0x27, 0x98,
+	0x11, 0x01, 0x8c, 0x51, 0xec, 0x50, 0xb1, 0x55, 0x4c, 0x42, 0x01, 0x08, 0x85, 0x50, 0xf9, 0x24,
+	0x36, 0xb0, 0x66, 0x63, 0xc0, 0x00, 0x9d, 0x64, 0xdb, 0x78, 0x99, 0x00, 0x00, 0x00,
+}
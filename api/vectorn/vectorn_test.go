@@ -0,0 +1,140 @@
+package vectorn
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestNewVectorN(t *testing.T) {
+	d := []float64{1, 2, 3, 4}
+	v := NewVectorN(d...)
+	d[0] = 10
+	utils.Equals(t, []float64{1, 2, 3, 4}, v.Dimensions)
+	utils.Equals(t, 4, v.Len())
+	utils.Equals(t, []float64{0, 0, 0, 0, 0}, NewVectorNZero(5).Dimensions)
+	utils.Equals(t, []float64{1, 1}, NewVectorNOne(2).Dimensions)
+	c := v.Clone()
+	c.Dimensions[0] = 7
+	utils.Equals(t, 1., v.Dimensions[0])
+}
+
+func TestVectorN_GetSet(t *testing.T) {
+	v := NewVectorN(1, 2, 3)
+	x, err := v.Get(2)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 3., x)
+	_, err = v.Get(3)
+	utils.Equals(t, utils.ErrVectorInvalidIndex, err)
+	_, err = v.Get(-1)
+	utils.Equals(t, utils.ErrVectorInvalidIndex, err)
+	utils.Equals(t, nil, v.Set(0, 5))
+	utils.Equals(t, []float64{5, 2, 3}, v.Dimensions)
+	utils.Equals(t, utils.ErrVectorInvalidIndex, v.Set(3, 5))
+}
+
+func TestVectorN_Vector3(t *testing.T) {
+	v := FromVector3(*vector3.NewVector3(1, 2, 3))
+	utils.Equals(t, []float64{1, 2, 3}, v.Dimensions)
+	v3, err := v.Vector3()
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, v3.Equal(*vector3.NewVector3(1, 2, 3)))
+	_, err = NewVectorN(1, 2).Vector3()
+	utils.Equals(t, utils.ErrVectorInvalidDimension, err)
+}
+
+func TestVectorN_Arithmetic(t *testing.T) {
+	a, b := *NewVectorN(1, 2, 3, 4), *NewVectorN(4, -5, 6, -7)
+	p, err := a.Plus(b)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []float64{5, -3, 9, -3}, p.Dimensions)
+	m, err := a.Minus(b)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []float64{-3, 7, -3, 11}, m.Dimensions)
+	utils.Equals(t, []float64{2, 4, 6, 8}, a.Times(2).Dimensions)
+	utils.Equals(t, []float64{1, 2, 3, 4}, a.Dimensions)
+	utils.Equals(t, []float64{4, 5, 6, 7}, b.Abs().Dimensions)
+	utils.Equals(t, 10., a.Sum())
+
+	c := a.Clone()
+	utils.Equals(t, nil, c.Add(&b))
+	utils.Equals(t, p.Dimensions, c.Dimensions)
+	utils.Equals(t, nil, c.Subtract(&b))
+	utils.Equals(t, a.Dimensions, c.Dimensions)
+	c.Scale(3)
+	c.Divide(3)
+	utils.Equals(t, a.Dimensions, c.Dimensions)
+
+	l, err := a.Lerp(b, 0.5)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []float64{2.5, -1.5, 4.5, -1.5}, l.Dimensions)
+}
+
+func TestVectorN_Norms(t *testing.T) {
+	a, b := *NewVectorN(1, 2, 3, 4), *NewVectorN(4, -5, 6, -7)
+	d, err := a.Dot(b)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, -16., d)
+	utils.Equals(t, 30., a.Norm())
+	utils.Equals(t, math.Sqrt(30), a.Norm2())
+	utils.Equals(t, []float64{0.6, 0, -0.8}, NewVectorN(3, 0, -4).Normalize().Dimensions)
+	utils.Equals(t, []float64{0, 0}, NewVectorNZero(2).Normalize().Dimensions)
+	dist, err := NewVectorN(0, 3, 0, 0, 0).Distance(*NewVectorN(0, 0, 0, 4, 0))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 5., dist)
+}
+
+func TestVectorN_MinMax(t *testing.T) {
+	a, b := *NewVectorN(1, -2, 3, 4), *NewVectorN(4, -5, 6, -7)
+	min, err := Min(a, b)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []float64{1, -5, 3, -7}, min.Dimensions)
+	max, err := Max(a, b)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []float64{4, -2, 6, 4}, max.Dimensions)
+}
+
+func TestVectorN_Equal(t *testing.T) {
+	utils.Equals(t, true, NewVectorN(1, 2, 3, 4).Equal(*NewVectorN(1, 2, 3, 4)))
+	utils.Equals(t, false, NewVectorN(1, 2, 3, 4).Equal(*NewVectorN(1, 2, 3, 5)))
+	utils.Equals(t, false, NewVectorN(1, 2, 3).Equal(*NewVectorN(1, 2, 3, 0)))
+}
+
+func TestVectorN_NotSameSize(t *testing.T) {
+	a, b := *NewVectorN(1, 2, 3, 4), *NewVectorN(1, 2, 3)
+	_, err := a.Plus(b)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	_, err = a.Minus(b)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	_, err = a.Dot(b)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	_, err = a.Distance(b)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	_, err = a.Lerp(b, 0.5)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	_, err = Min(a, b)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	_, err = Max(a, b)
+	utils.Equals(t, utils.ErrVector3otSameSize, err)
+	utils.Equals(t, utils.ErrVector3otSameSize, a.Add(&b))
+	utils.Equals(t, utils.ErrVector3otSameSize, a.Subtract(&b))
+	utils.Equals(t, []float64{1, 2, 3, 4}, a.Dimensions)
+}
+
+func TestVectorN_Text(t *testing.T) {
+	v := NewVectorN(1.5, -2, 0.25, 8)
+	utils.Equals(t, "(1.5, -2, 0.25, 8)", fmt.Sprint(v))
+	utils.Equals(t, "(1.50, -2.00, 0.25, 8.00)", fmt.Sprintf("%.2f", v))
+	utils.Equals(t, "vectorn.VectorN{Dimensions:[]float64{1.5, -2, 0.25, 8}}", fmt.Sprintf("%#v", v))
+	p, err := ParseVectorN(fmt.Sprint(v))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, v.Dimensions, p.Dimensions)
+	p, err = ParseVectorN("()")
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 0, p.Len())
+	_, err = ParseVectorN("(1, x)")
+	utils.Equals(t, utils.ErrTextMalformed, err)
+}
@@ -126,5 +126,4 @@ func (b *Box) EncapsulateBox(o Box) *Box {
 // Intersection returns the intersection between two boxes
 func (b Box) Intersection(o Box) vector3.Vector3 {
     panic(errors.New("intersection not implemented"))
-    return vector3.Vector3{}
 }
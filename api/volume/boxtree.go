@@ -0,0 +1,394 @@
+package volume
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// boxTreeNode is a node of a BoxTree, a leaf when left is -1
+type boxTreeNode struct {
+	min, max            [3]float64
+	parent, left, right int
+	height              int
+	id                  uint64
+}
+
+func (n *boxTreeNode) isLeaf() bool {
+	return n.left == -1
+}
+
+// BoxTree is a dynamic bounding volume hierarchy of boxes keyed by id, balanced by rotations like an AVL
+// tree, for spatial queries on moving objects
+type BoxTree struct {
+	nodes  []boxTreeNode
+	free   []int
+	root   int
+	leaves map[uint64]int
+}
+
+// NewBoxTree returns an empty tree
+func NewBoxTree() *BoxTree {
+	return &BoxTree{root: -1, leaves: make(map[uint64]int)}
+}
+
+func boxBounds(b Box) (min, max [3]float64) {
+	return [3]float64{b.Min.X, b.Min.Y, b.Min.Z}, [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+}
+
+func boundsBox(min, max [3]float64) Box {
+	return *NewBoxMinMax(min[0], min[1], min[2], max[0], max[1], max[2])
+}
+
+// mergedArea returns the surface area of the box around both bounds, the cost of a node
+func mergedArea(amin, amax, bmin, bmax [3]float64) float64 {
+	var d [3]float64
+	for i := range d {
+		d[i] = math.Max(amax[i], bmax[i]) - math.Min(amin[i], bmin[i])
+	}
+	return 2 * (d[0]*d[1] + d[1]*d[2] + d[2]*d[0])
+}
+
+func (t *BoxTree) allocate() int {
+	if n := len(t.free); n > 0 {
+		i := t.free[n-1]
+		t.free = t.free[:n-1]
+		return i
+	}
+	t.nodes = append(t.nodes, boxTreeNode{})
+	return len(t.nodes) - 1
+}
+
+// Len returns the number of boxes in the tree
+func (t *BoxTree) Len() int {
+	return len(t.leaves)
+}
+
+// Get returns the box of id
+func (t *BoxTree) Get(id uint64) (Box, bool) {
+	i, ok := t.leaves[id]
+	if !ok {
+		return Box{}, false
+	}
+	return boundsBox(t.nodes[i].min, t.nodes[i].max), true
+}
+
+// Insert adds the box of id, replacing its previous one
+func (t *BoxTree) Insert(id uint64, b Box) {
+	t.Remove(id)
+	leaf := t.allocate()
+	min, max := boxBounds(b)
+	t.nodes[leaf] = boxTreeNode{min: min, max: max, parent: -1, left: -1, right: -1, id: id}
+	t.leaves[id] = leaf
+	if t.root == -1 {
+		t.root = leaf
+		return
+	}
+
+	// Descend towards the sibling whose merged box grows the least (Catto, Dynamic AABB Tree)
+	sibling := t.root
+	for !t.nodes[sibling].isLeaf() {
+		n := t.nodes[sibling]
+		area := mergedArea(n.min, n.max, n.min, n.max)
+		combined := mergedArea(n.min, n.max, min, max)
+		// Cost of making a new parent here, and the cost pushed down to the children
+		cost := 2 * combined
+		inherited := 2 * (combined - area)
+		child := func(c int) float64 {
+			cn := t.nodes[c]
+			grown := mergedArea(cn.min, cn.max, min, max)
+			if cn.isLeaf() {
+				return grown + inherited
+			}
+			return grown - mergedArea(cn.min, cn.max, cn.min, cn.max) + inherited
+		}
+		left, right := child(n.left), child(n.right)
+		if cost < left && cost < right {
+			break
+		}
+		if left < right {
+			sibling = n.left
+		} else {
+			sibling = n.right
+		}
+	}
+
+	oldParent := t.nodes[sibling].parent
+	parent := t.allocate()
+	t.nodes[parent] = boxTreeNode{parent: oldParent, left: sibling, right: leaf}
+	t.nodes[sibling].parent = parent
+	t.nodes[leaf].parent = parent
+	switch {
+	case oldParent == -1:
+		t.root = parent
+	case t.nodes[oldParent].left == sibling:
+		t.nodes[oldParent].left = parent
+	default:
+		t.nodes[oldParent].right = parent
+	}
+	t.refit(parent)
+}
+
+// Remove removes the box of id, returning whether it was in the tree
+func (t *BoxTree) Remove(id uint64) bool {
+	leaf, ok := t.leaves[id]
+	if !ok {
+		return false
+	}
+	delete(t.leaves, id)
+	t.free = append(t.free, leaf)
+	if leaf == t.root {
+		t.root = -1
+		return true
+	}
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].left
+	if sibling == leaf {
+		sibling = t.nodes[parent].right
+	}
+	t.free = append(t.free, parent)
+	t.nodes[sibling].parent = grandParent
+	switch {
+	case grandParent == -1:
+		t.root = sibling
+	case t.nodes[grandParent].left == parent:
+		t.nodes[grandParent].left = sibling
+	default:
+		t.nodes[grandParent].right = sibling
+	}
+	if grandParent != -1 {
+		t.refit(grandParent)
+	}
+	return true
+}
+
+// refit updates the boxes and heights from i up to the root, balancing on the way
+func (t *BoxTree) refit(i int) {
+	for i != -1 {
+		i = t.balance(i)
+		t.fit(i)
+		i = t.nodes[i].parent
+	}
+}
+
+// balance rotates the taller grandchild of an unbalanced node a up, returning the node now in its place
+func (t *BoxTree) balance(a int) int {
+	na := &t.nodes[a]
+	if na.isLeaf() || na.height < 2 {
+		return a
+	}
+	b, c := na.left, na.right
+	diff := t.nodes[c].height - t.nodes[b].height
+	switch {
+	case diff > 1:
+		return t.rotate(a, c)
+	case diff < -1:
+		return t.rotate(a, b)
+	}
+	return a
+}
+
+// rotate lifts up the child up of a, which takes the shorter child of up in its place
+func (t *BoxTree) rotate(a, up int) int {
+	nu := &t.nodes[up]
+	f, g := nu.left, nu.right
+	// up takes the place of a
+	nu.left = a
+	nu.parent = t.nodes[a].parent
+	t.nodes[a].parent = up
+	switch {
+	case nu.parent == -1:
+		t.root = up
+	case t.nodes[nu.parent].left == a:
+		t.nodes[nu.parent].left = up
+	default:
+		t.nodes[nu.parent].right = up
+	}
+	// The taller grandchild stays under up, the other goes under a
+	keep, move := f, g
+	if t.nodes[f].height < t.nodes[g].height {
+		keep, move = g, f
+	}
+	t.nodes[up].right = keep
+	if t.nodes[a].left == up {
+		t.nodes[a].left = move
+	} else {
+		t.nodes[a].right = move
+	}
+	t.nodes[move].parent = a
+	t.fit(a)
+	t.fit(up)
+	return up
+}
+
+// fit updates the box and height of an inner node from its children
+func (t *BoxTree) fit(i int) {
+	n := &t.nodes[i]
+	l, r := t.nodes[n.left], t.nodes[n.right]
+	for k := range n.min {
+		n.min[k] = math.Min(l.min[k], r.min[k])
+		n.max[k] = math.Max(l.max[k], r.max[k])
+	}
+	n.height = 1 + maxInt(l.height, r.height)
+}
+
+// walk visits the nodes whose box passes test, calling leaf on the leaves until it returns false
+func (t *BoxTree) walk(test func(n *boxTreeNode) bool, leaf func(n *boxTreeNode) bool) {
+	if t.root == -1 {
+		return
+	}
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		n := &t.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !test(n) {
+			continue
+		}
+		if n.isLeaf() {
+			if !leaf(n) {
+				return
+			}
+			continue
+		}
+		stack = append(stack, n.right, n.left)
+	}
+}
+
+// Query calls f with the boxes intersecting b until it returns false
+func (t *BoxTree) Query(b Box, f func(id uint64, b Box) bool) {
+	min, max := boxBounds(b)
+	t.walk(func(n *boxTreeNode) bool {
+		for k := range min {
+			if n.max[k] < min[k] || max[k] < n.min[k] {
+				return false
+			}
+		}
+		return true
+	}, func(n *boxTreeNode) bool {
+		return f(n.id, boundsBox(n.min, n.max))
+	})
+}
+
+// boundsDistance returns the squared distance from p to the bounds, 0 inside
+func boundsDistance(p [3]float64, min, max [3]float64) float64 {
+	var d float64
+	for k := range p {
+		if p[k] < min[k] {
+			d += (min[k] - p[k]) * (min[k] - p[k])
+		} else if p[k] > max[k] {
+			d += (p[k] - max[k]) * (p[k] - max[k])
+		}
+	}
+	return d
+}
+
+// QuerySphere calls f with the boxes intersecting the sphere until it returns false
+func (t *BoxTree) QuerySphere(center vector3.Vector3, radius float64, f func(id uint64, b Box) bool) {
+	p := [3]float64{center.X, center.Y, center.Z}
+	r2 := radius * radius
+	t.walk(func(n *boxTreeNode) bool {
+		return boundsDistance(p, n.min, n.max) <= r2
+	}, func(n *boxTreeNode) bool {
+		return f(n.id, boundsBox(n.min, n.max))
+	})
+}
+
+// rayBounds returns the distance along the ray where it enters the bounds, 0 from inside, and whether it
+// does before maxDistance. inverse holds the inverses of the direction components
+func rayBounds(origin, inverse [3]float64, min, max [3]float64, maxDistance float64) (float64, bool) {
+	near, far := 0., maxDistance
+	for k := range origin {
+		t1, t2 := (min[k]-origin[k])*inverse[k], (max[k]-origin[k])*inverse[k]
+		// Rays parallel to a slab from inside of it give NaN from 0 * Inf
+		if math.IsNaN(t1) || math.IsNaN(t2) {
+			continue
+		}
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		near, far = math.Max(near, t1), math.Min(far, t2)
+		if near > far {
+			return 0, false
+		}
+	}
+	return near, true
+}
+
+// Raycast calls f with the boxes the ray from origin along direction crosses within maxDistance, in no
+// particular order, and the distance where it enters them, until it returns false
+// Distances are in units of the length of direction
+func (t *BoxTree) Raycast(origin, direction vector3.Vector3, maxDistance float64, f func(id uint64, b Box, distance float64) bool) {
+	o := [3]float64{origin.X, origin.Y, origin.Z}
+	inverse := [3]float64{1 / direction.X, 1 / direction.Y, 1 / direction.Z}
+	var distance float64
+	t.walk(func(n *boxTreeNode) bool {
+		var ok bool
+		distance, ok = rayBounds(o, inverse, n.min, n.max, maxDistance)
+		return ok
+	}, func(n *boxTreeNode) bool {
+		return f(n.id, boundsBox(n.min, n.max), distance)
+	})
+}
+
+// boxTreeQueue is a min heap of nodes by distance
+type boxTreeQueue []boxTreeEntry
+
+type boxTreeEntry struct {
+	node     int
+	distance float64
+}
+
+func (q boxTreeQueue) Len() int            { return len(q) }
+func (q boxTreeQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q boxTreeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *boxTreeQueue) Push(x interface{}) { *q = append(*q, x.(boxTreeEntry)) }
+func (q *boxTreeQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// Nearest returns the ids of the k boxes closest to p, the closest first, and their distances, 0 for the
+// boxes holding p. Ties are broken by id
+func (t *BoxTree) Nearest(p vector3.Vector3, k int) ([]uint64, []float64) {
+	if t.root == -1 || k <= 0 {
+		return nil, nil
+	}
+	point := [3]float64{p.X, p.Y, p.Z}
+	q := &boxTreeQueue{{t.root, boundsDistance(point, t.nodes[t.root].min, t.nodes[t.root].max)}}
+	var ids []uint64
+	var distances []float64
+	for q.Len() > 0 {
+		e := heap.Pop(q).(boxTreeEntry)
+		n := t.nodes[e.node]
+		if len(ids) == k && e.distance > distances[k-1] {
+			break
+		}
+		if !n.isLeaf() {
+			for _, c := range []int{n.left, n.right} {
+				heap.Push(q, boxTreeEntry{c, boundsDistance(point, t.nodes[c].min, t.nodes[c].max)})
+			}
+			continue
+		}
+		// Insertion keeping the results sorted by distance then id
+		i := len(ids)
+		for i > 0 && (distances[i-1] > e.distance || distances[i-1] == e.distance && ids[i-1] > n.id) {
+			i--
+		}
+		if i >= k {
+			continue
+		}
+		ids = append(ids[:i], append([]uint64{n.id}, ids[i:]...)...)
+		distances = append(distances[:i], append([]float64{e.distance}, distances[i:]...)...)
+		if len(ids) > k {
+			ids, distances = ids[:k], distances[:k]
+		}
+	}
+	for i := range distances {
+		distances[i] = math.Sqrt(distances[i])
+	}
+	return ids, distances
+}
@@ -0,0 +1,221 @@
+package volume
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// randomBox returns a box of size up to 2 inside a cube of side 20
+func randomBox(r *rand.Rand) Box {
+	x, y, z := r.Float64()*20-10, r.Float64()*20-10, r.Float64()*20-10
+	return *NewBoxMinMax(x, y, z, x+r.Float64()*2, y+r.Float64()*2, z+r.Float64()*2)
+}
+
+func sortedKeys(ids map[uint64]bool) []uint64 {
+	keys := make([]uint64, 0, len(ids))
+	for id := range ids {
+		keys = append(keys, id)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// checkBoxTree verifies that the nodes hold their children and are balanced
+func checkBoxTree(t *testing.T, tree *BoxTree) {
+	var visit func(i, parent int) int
+	visit = func(i, parent int) int {
+		n := tree.nodes[i]
+		utils.Equals(t, parent, n.parent)
+		if n.isLeaf() {
+			return 0
+		}
+		l, r := visit(n.left, i), visit(n.right, i)
+		utils.Equals(t, true, l-r <= 1 && r-l <= 1)
+		for _, c := range []int{n.left, n.right} {
+			for k := 0; k < 3; k++ {
+				utils.Equals(t, true, n.min[k] <= tree.nodes[c].min[k] && tree.nodes[c].max[k] <= n.max[k])
+			}
+		}
+		utils.Equals(t, 1+maxInt(l, r), n.height)
+		return n.height
+	}
+	if tree.root != -1 {
+		visit(tree.root, -1)
+	}
+}
+
+func TestBoxTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewBoxTree()
+	boxes := make(map[uint64]Box)
+	for i := 0; i < 2000; i++ {
+		id := uint64(r.Intn(300))
+		if r.Intn(4) == 0 {
+			_, ok := boxes[id]
+			utils.Equals(t, ok, tree.Remove(id))
+			delete(boxes, id)
+		} else {
+			boxes[id] = randomBox(r)
+			tree.Insert(id, boxes[id])
+		}
+	}
+	checkBoxTree(t, tree)
+	utils.Equals(t, len(boxes), tree.Len())
+	for id, b := range boxes {
+		got, ok := tree.Get(id)
+		utils.Equals(t, true, ok)
+		utils.Equals(t, true, got.Min.Equal(*b.Min) && got.Max.Equal(*b.Max))
+	}
+
+	for i := 0; i < 50; i++ {
+		q := randomBox(r)
+		max := q.Max.Plus(*vector3.NewVector3(3, 3, 3))
+		q.Max = &max
+		exp, got := make(map[uint64]bool), make(map[uint64]bool)
+		for id, b := range boxes {
+			if b.Intersects(q) {
+				exp[id] = true
+			}
+		}
+		tree.Query(q, func(id uint64, _ Box) bool {
+			got[id] = true
+			return true
+		})
+		utils.Equals(t, sortedKeys(exp), sortedKeys(got))
+
+		center, radius := *q.Min, r.Float64()*4
+		exp, got = make(map[uint64]bool), make(map[uint64]bool)
+		for id, b := range boxes {
+			min, max := boxBounds(b)
+			if boundsDistance([3]float64{center.X, center.Y, center.Z}, min, max) <= radius*radius {
+				exp[id] = true
+			}
+		}
+		tree.QuerySphere(center, radius, func(id uint64, _ Box) bool {
+			got[id] = true
+			return true
+		})
+		utils.Equals(t, sortedKeys(exp), sortedKeys(got))
+	}
+
+	_, ok := tree.Get(1000)
+	utils.Equals(t, false, ok)
+	utils.Equals(t, false, tree.Remove(1000))
+	for id := range boxes {
+		tree.Remove(id)
+	}
+	utils.Equals(t, 0, tree.Len())
+	utils.Equals(t, -1, tree.root)
+}
+
+func TestBoxTree_Query(t *testing.T) {
+	tree := NewBoxTree()
+	for i := 0; i < 10; i++ {
+		x := float64(i * 2)
+		tree.Insert(uint64(i), *NewBoxMinMax(x, 0, 0, x+1, 1, 1))
+	}
+	// Touching boxes intersect, and the query stops when f returns false
+	var ids []uint64
+	tree.Query(*NewBoxMinMax(3, 0, 0, 6, 1, 1), func(id uint64, _ Box) bool {
+		ids = append(ids, id)
+		return true
+	})
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	utils.Equals(t, []uint64{1, 2, 3}, ids)
+	count := 0
+	tree.Query(*NewBoxMinMax(-10, -10, -10, 30, 10, 10), func(uint64, Box) bool {
+		count++
+		return count < 4
+	})
+	utils.Equals(t, 4, count)
+
+	// Moving a box replaces it
+	tree.Insert(0, *NewBoxMinMax(100, 0, 0, 101, 1, 1))
+	ids = nil
+	tree.Query(*NewBoxMinMax(-1, -1, -1, 1, 1, 1), func(id uint64, _ Box) bool {
+		ids = append(ids, id)
+		return true
+	})
+	utils.Equals(t, []uint64(nil), ids)
+	utils.Equals(t, 10, tree.Len())
+}
+
+func TestBoxTree_Raycast(t *testing.T) {
+	tree := NewBoxTree()
+	for i := 0; i < 10; i++ {
+		x := float64(i * 2)
+		tree.Insert(uint64(i), *NewBoxMinMax(x, 0, 0, x+1, 1, 1))
+	}
+	hits := make(map[uint64]float64)
+	collect := func(id uint64, _ Box, distance float64) bool {
+		hits[id] = distance
+		return true
+	}
+	tree.Raycast(*vector3.NewVector3(-1, 0.5, 0.5), *vector3.NewVector3(1, 0, 0), 6, collect)
+	utils.Equals(t, map[uint64]float64{0: 1, 1: 3, 2: 5}, hits)
+
+	// From inside the distance is 0, along a face the ray still hits
+	hits = make(map[uint64]float64)
+	tree.Raycast(*vector3.NewVector3(0.5, 0, 0.5), *vector3.NewVector3(1, 0, 0), 2, collect)
+	utils.Equals(t, map[uint64]float64{0: 0, 1: 1.5}, hits)
+
+	hits = make(map[uint64]float64)
+	tree.Raycast(*vector3.NewVector3(0.5, 2, 0.5), *vector3.NewVector3(0, -2, 0), math.Inf(1), collect)
+	utils.Equals(t, map[uint64]float64{0: 0.5}, hits)
+
+	hits = make(map[uint64]float64)
+	tree.Raycast(*vector3.NewVector3(0.5, 2, 0.5), *vector3.NewVector3(0, 1, 0), math.Inf(1), collect)
+	utils.Equals(t, map[uint64]float64{}, hits)
+}
+
+func TestBoxTree_Nearest(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	tree := NewBoxTree()
+	boxes := make(map[uint64]Box)
+	for i := uint64(0); i < 200; i++ {
+		boxes[i] = randomBox(r)
+		tree.Insert(i, boxes[i])
+	}
+	for i := 0; i < 50; i++ {
+		p := *vector3.NewVector3(r.Float64()*24-12, r.Float64()*24-12, r.Float64()*24-12)
+		point := [3]float64{p.X, p.Y, p.Z}
+		all := make([]uint64, 0, len(boxes))
+		distances := make(map[uint64]float64)
+		for id, b := range boxes {
+			min, max := boxBounds(b)
+			all = append(all, id)
+			distances[id] = math.Sqrt(boundsDistance(point, min, max))
+		}
+		sort.Slice(all, func(i, j int) bool {
+			if distances[all[i]] != distances[all[j]] {
+				return distances[all[i]] < distances[all[j]]
+			}
+			return all[i] < all[j]
+		})
+		ids, got := tree.Nearest(p, 7)
+		utils.Equals(t, all[:7], ids)
+		for j, id := range ids {
+			utils.Equals(t, distances[id], got[j])
+		}
+	}
+
+	ids, _ := tree.Nearest(*vector3.NewVector3Zero(), 500)
+	utils.Equals(t, 200, len(ids))
+	ids, _ = tree.Nearest(*vector3.NewVector3Zero(), 0)
+	utils.Equals(t, 0, len(ids))
+	ids, _ = NewBoxTree().Nearest(*vector3.NewVector3Zero(), 3)
+	utils.Equals(t, 0, len(ids))
+}
+
+func BenchmarkBoxTree_Insert(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewBoxTree()
+	for i := 0; i < b.N; i++ {
+		tree.Insert(uint64(i%10000), randomBox(r))
+	}
+}
@@ -0,0 +1,401 @@
+package volume
+
+import (
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// bvhLeafSize is the most triangles a leaf holds
+const bvhLeafSize = 4
+
+// bvhTriangle is a triangle of the mesh with its index in Mesh.Tris / 3
+type bvhTriangle struct {
+	a, b, c vector3.Vector3
+	index   int
+}
+
+func (t *bvhTriangle) centroid() vector3.Vector3 {
+	return t.a.Plus(t.b).Plus(t.c).Times(1. / 3)
+}
+
+// bvhNode is an inner node with two children or a leaf holding triangles[start:start+count]
+type bvhNode struct {
+	min, max     vector3.Vector3
+	left, right  int
+	start, count int
+}
+
+// MeshBVH is a bounding volume hierarchy over the triangles of a mesh, answering closest point,
+// distance and containment queries in logarithmic time
+// It copies the triangles, so it doesn't see the later edits of the mesh
+type MeshBVH struct {
+	triangles []bvhTriangle
+	nodes     []bvhNode
+}
+
+// NewMeshBVH builds the hierarchy of the mesh triangles, splitting the nodes at the median centroid
+// along their longest side. Incomplete and out of range triangles are ignored
+func NewMeshBVH(m *Mesh) *MeshBVH {
+	h := &MeshBVH{triangles: m.triangles()}
+	if len(h.triangles) > 0 {
+		h.build(0, len(h.triangles))
+	}
+	return h
+}
+
+// build creates the node of triangles[start:end] and its descendants, returning its index
+func (h *MeshBVH) build(start, end int) int {
+	n := bvhNode{min: *vector3.NewVector3Max(), max: *vector3.NewVector3Min()}
+	centroidMin, centroidMax := *vector3.NewVector3Max(), *vector3.NewVector3Min()
+	for _, t := range h.triangles[start:end] {
+		n.min = vector3.Min(n.min, vector3.Min(t.a, vector3.Min(t.b, t.c)))
+		n.max = vector3.Max(n.max, vector3.Max(t.a, vector3.Max(t.b, t.c)))
+		c := t.centroid()
+		centroidMin, centroidMax = vector3.Min(centroidMin, c), vector3.Max(centroidMax, c)
+	}
+	index := len(h.nodes)
+	h.nodes = append(h.nodes, n)
+	if end-start <= bvhLeafSize {
+		h.nodes[index].start, h.nodes[index].count = start, end-start
+		return index
+	}
+	extent := centroidMax.Minus(centroidMin)
+	axis := 0
+	if extent.Y > component(extent, axis) {
+		axis = 1
+	}
+	if extent.Z > component(extent, axis) {
+		axis = 2
+	}
+	part := h.triangles[start:end]
+	sort.Slice(part, func(i, j int) bool {
+		return component(part[i].centroid(), axis) < component(part[j].centroid(), axis)
+	})
+	mid := (start + end) / 2
+	left := h.build(start, mid)
+	right := h.build(mid, end)
+	h.nodes[index].left, h.nodes[index].right = left, right
+	return index
+}
+
+// boxDistance returns the squared distance from p to the box of a node
+func (n *bvhNode) boxDistance(p vector3.Vector3) float64 {
+	d := vector3.Max(vector3.Max(n.min.Minus(p), p.Minus(n.max)), *vector3.NewVector3Zero())
+	return d.Norm()
+}
+
+// ClosestPoint returns the point of the surface closest to p and the index of its triangle,
+// ok is false if the mesh has no triangle
+func (h *MeshBVH) ClosestPoint(p vector3.Vector3) (closest vector3.Vector3, triangle int, ok bool) {
+	if len(h.nodes) == 0 {
+		return closest, -1, false
+	}
+	best := math.Inf(1)
+	var visit func(node int)
+	visit = func(node int) {
+		n := &h.nodes[node]
+		if n.boxDistance(p) >= best {
+			return
+		}
+		if n.count > 0 {
+			for i := n.start; i < n.start+n.count; i++ {
+				t := &h.triangles[i]
+				q := ClosestPointOnTriangle(p, t.a, t.b, t.c)
+				if d := q.Minus(p).Norm(); d < best {
+					best, closest, triangle = d, q, t.index
+				}
+			}
+			return
+		}
+		// Nearest child first so that the other one is more likely to be pruned
+		first, second := n.left, n.right
+		if h.nodes[second].boxDistance(p) < h.nodes[first].boxDistance(p) {
+			first, second = second, first
+		}
+		visit(first)
+		visit(second)
+	}
+	visit(0)
+	return closest, triangle, true
+}
+
+// Distance returns the distance from p to the surface, +Inf if the mesh has no triangle
+func (h *MeshBVH) Distance(p vector3.Vector3) float64 {
+	q, _, ok := h.ClosestPoint(p)
+	if !ok {
+		return math.Inf(1)
+	}
+	return p.Distance(q)
+}
+
+// rayBox returns whether the ray hits the box of a node before tMax
+func (n *bvhNode) rayBox(origin, inverse vector3.Vector3, tMax float64) bool {
+	tMin := 0.
+	for axis := 0; axis < 3; axis++ {
+		o, inv := component(origin, axis), component(inverse, axis)
+		t0 := (component(n.min, axis) - o) * inv
+		t1 := (component(n.max, axis) - o) * inv
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		// NaN when the ray runs along a side of the box, keep the bounds
+		if t0 > tMin {
+			tMin = t0
+		}
+		if t1 < tMax {
+			tMax = t1
+		}
+		if tMin > tMax {
+			return false
+		}
+	}
+	return true
+}
+
+// rayTriangle returns the distance along the ray to the triangle, with Möller-Trumbore
+func rayTriangle(origin, direction vector3.Vector3, t *bvhTriangle) (float64, bool) {
+	const epsilon = 1e-12
+	e1, e2 := t.b.Minus(t.a), t.c.Minus(t.a)
+	pv := direction.Cross(e2)
+	det := e1.Dot(*pv)
+	if math.Abs(det) < epsilon {
+		return 0, false
+	}
+	inv := 1 / det
+	tv := origin.Minus(t.a)
+	u := tv.Dot(*pv) * inv
+	if u < 0 || u > 1 {
+		return 0, false
+	}
+	qv := tv.Cross(e1)
+	v := direction.Dot(*qv) * inv
+	if v < 0 || u+v > 1 {
+		return 0, false
+	}
+	d := e2.Dot(*qv) * inv
+	return d, d >= 0
+}
+
+// raycast visits the triangles hit by the ray before maxDistance
+func (h *MeshBVH) raycast(origin, direction vector3.Vector3, maxDistance float64, hit func(t *bvhTriangle, distance float64)) {
+	if len(h.nodes) == 0 {
+		return
+	}
+	inverse := *vector3.NewVector3(1/direction.X, 1/direction.Y, 1/direction.Z)
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := &h.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !n.rayBox(origin, inverse, maxDistance) {
+			continue
+		}
+		if n.count == 0 {
+			stack = append(stack, n.left, n.right)
+			continue
+		}
+		for i := n.start; i < n.start+n.count; i++ {
+			if d, ok := rayTriangle(origin, direction, &h.triangles[i]); ok && d <= maxDistance {
+				hit(&h.triangles[i], d)
+			}
+		}
+	}
+}
+
+// Raycast returns the distance along the direction to the first triangle hit from origin and its index,
+// ok is false if the ray misses the mesh
+func (h *MeshBVH) Raycast(origin, direction vector3.Vector3) (distance float64, triangle int, ok bool) {
+	distance, triangle = math.Inf(1), -1
+	h.raycast(origin, direction, math.Inf(1), func(t *bvhTriangle, d float64) {
+		if d < distance {
+			distance, triangle = d, t.index
+		}
+	})
+	return distance, triangle, triangle != -1
+}
+
+// containsDirections are the rays cast from a point to count the crossings of the surface, skewed so that
+// they rarely run along the edges of axis aligned meshes
+var containsDirections = [3]vector3.Vector3{
+	*vector3.NewVector3(0.5773, 0.6181, 0.5333),
+	*vector3.NewVector3(-0.7071, 0.1423, -0.6925),
+	*vector3.NewVector3(0.1234, -0.9129, 0.3891),
+}
+
+// Contains returns whether p is inside the closed mesh by ray parity, the majority of 3 rays deciding
+// so that a ray grazing an edge doesn't flip the result
+func (h *MeshBVH) Contains(p vector3.Vector3) bool {
+	votes := 0
+	for _, d := range containsDirections {
+		crossings := 0
+		h.raycast(p, d, math.Inf(1), func(*bvhTriangle, float64) { crossings++ })
+		if crossings%2 == 1 {
+			votes++
+		}
+	}
+	return votes >= 2
+}
+
+// SignedDistance returns the distance from p to the closed mesh, negative inside
+func (h *MeshBVH) SignedDistance(p vector3.Vector3) float64 {
+	d := h.Distance(p)
+	if h.Contains(p) {
+		return -d
+	}
+	return d
+}
+
+// gap returns the squared distance between the box of a node and the box from min to max
+func (n *bvhNode) gap(min, max vector3.Vector3) float64 {
+	d := vector3.Max(vector3.Max(n.min.Minus(max), min.Minus(n.max)), *vector3.NewVector3Zero())
+	return d.Norm()
+}
+
+// IntersectsBox returns whether the surface crosses the box or, the mesh being closed, holds it
+func (h *MeshBVH) IntersectsBox(b Box) bool {
+	if len(h.nodes) == 0 {
+		return false
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := &h.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if n.gap(*b.Min, *b.Max) > 0 {
+			continue
+		}
+		if n.count == 0 {
+			stack = append(stack, n.left, n.right)
+			continue
+		}
+		for i := n.start; i < n.start+n.count; i++ {
+			t := &h.triangles[i]
+			if TriangleIntersectsBox(t.a, t.b, t.c, b) {
+				return true
+			}
+		}
+	}
+	return h.Contains(b.GetCenter())
+}
+
+// SegmentDistance returns the distance from the segment pq to the surface, +Inf if the mesh has no triangle
+func (h *MeshBVH) SegmentDistance(p, q vector3.Vector3) float64 {
+	best := math.Inf(1)
+	if len(h.nodes) == 0 {
+		return best
+	}
+	min, max := vector3.Min(p, q), vector3.Max(p, q)
+	var visit func(node int)
+	visit = func(node int) {
+		n := &h.nodes[node]
+		// The box of the segment is within its distance, a lower bound of the distance to the node
+		if n.gap(min, max) >= best*best {
+			return
+		}
+		if n.count > 0 {
+			for i := n.start; i < n.start+n.count; i++ {
+				t := &h.triangles[i]
+				best = math.Min(best, SegmentTriangleDistance(p, q, t.a, t.b, t.c))
+			}
+			return
+		}
+		first, second := n.left, n.right
+		if h.nodes[second].gap(min, max) < h.nodes[first].gap(min, max) {
+			first, second = second, first
+		}
+		visit(first)
+		visit(second)
+	}
+	visit(0)
+	return best
+}
+
+// IntersectsCapsule returns whether the surface comes within radius of the segment pq or, the mesh being
+// closed, holds the capsule
+func (h *MeshBVH) IntersectsCapsule(p, q vector3.Vector3, radius float64) bool {
+	return h.SegmentDistance(p, q) <= radius || h.Contains(p)
+}
+
+// The mesh queries check every triangle, a MeshBVH built once answers repeated queries in logarithmic time
+
+// triangles returns the complete, in range triangles of the mesh
+func (m *Mesh) triangles() []bvhTriangle {
+	var out []bvhTriangle
+	for i := 0; i < m.triangleCount(); i++ {
+		if m.isOutOfRange(i) {
+			continue
+		}
+		a, b, c := m.triangle(i)
+		out = append(out, bvhTriangle{a: a, b: b, c: c, index: i})
+	}
+	return out
+}
+
+// ClosestPoint returns the point of the surface closest to p, p itself if the mesh has no triangle
+func (m *Mesh) ClosestPoint(p vector3.Vector3) vector3.Vector3 {
+	closest, best := p, math.Inf(1)
+	for _, t := range m.triangles() {
+		q := ClosestPointOnTriangle(p, t.a, t.b, t.c)
+		if d := q.Minus(p).Norm(); d < best {
+			best, closest = d, q
+		}
+	}
+	return closest
+}
+
+// Distance returns the distance from p to the surface of the mesh, +Inf if it has no triangle
+func (m *Mesh) Distance(p vector3.Vector3) float64 {
+	best := math.Inf(1)
+	for _, t := range m.triangles() {
+		best = math.Min(best, ClosestPointOnTriangle(p, t.a, t.b, t.c).Minus(p).Norm())
+	}
+	return math.Sqrt(best)
+}
+
+// Contains returns whether p is inside the closed mesh, by the same ray parity vote as MeshBVH.Contains
+func (m *Mesh) Contains(p vector3.Vector3) bool {
+	triangles := m.triangles()
+	votes := 0
+	for _, d := range containsDirections {
+		crossings := 0
+		for i := range triangles {
+			if _, ok := rayTriangle(p, d, &triangles[i]); ok {
+				crossings++
+			}
+		}
+		if crossings%2 == 1 {
+			votes++
+		}
+	}
+	return votes >= 2
+}
+
+// SignedDistance returns the distance from p to the closed mesh, negative inside
+func (m *Mesh) SignedDistance(p vector3.Vector3) float64 {
+	d := m.Distance(p)
+	if m.Contains(p) {
+		return -d
+	}
+	return d
+}
+
+// IntersectsBox returns whether the surface crosses the box or, the mesh being closed, holds it
+func (m *Mesh) IntersectsBox(b Box) bool {
+	for _, t := range m.triangles() {
+		if TriangleIntersectsBox(t.a, t.b, t.c, b) {
+			return true
+		}
+	}
+	return m.Contains(b.GetCenter())
+}
+
+// IntersectsCapsule returns whether the surface comes within radius of the segment pq or, the mesh being
+// closed, holds the capsule
+func (m *Mesh) IntersectsCapsule(p, q vector3.Vector3, radius float64) bool {
+	for _, t := range m.triangles() {
+		if SegmentTriangleDistance(p, q, t.a, t.b, t.c) <= radius {
+			return true
+		}
+	}
+	return m.Contains(p)
+}
@@ -0,0 +1,92 @@
+package volume
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestMeshBVH_ClosestPoint(t *testing.T) {
+	m := testSphere(1, 16, 32)
+	h := NewMeshBVH(m)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		p := *vector3.NewVector3(r.Float64()*4-2, r.Float64()*4-2, r.Float64()*4-2)
+		q, tri, ok := h.ClosestPoint(p)
+		utils.Equals(t, true, ok)
+		// Against the mesh checking every triangle
+		utils.Equals(t, true, almostEqual(m.Distance(p), p.Distance(q)))
+		utils.Equals(t, m.Contains(p), h.Contains(p))
+		a, b, c := m.triangle(tri)
+		utils.Equals(t, true, ClosestPointOnTriangle(p, a, b, c).Distance(q) < 1e-12)
+	}
+
+	_, _, ok := NewMeshBVH(&Mesh{}).ClosestPoint(*vector3.NewVector3Zero())
+	utils.Equals(t, false, ok)
+	utils.Equals(t, math.Inf(1), NewMeshBVH(&Mesh{}).Distance(*vector3.NewVector3Zero()))
+}
+
+func TestMeshBVH_Raycast(t *testing.T) {
+	h := NewMeshBVH(NewMeshSquareCuboid(2, true))
+	d, tri, ok := h.Raycast(*vector3.NewVector3(-5, 0.1, 0.2), *vector3.NewVector3(1, 0, 0))
+	utils.Equals(t, true, ok)
+	utils.Equals(t, true, almostEqual(4, d))
+	utils.Equals(t, true, tri >= 0 && tri < 12)
+	_, _, ok = h.Raycast(*vector3.NewVector3(-5, 3, 0), *vector3.NewVector3(1, 0, 0))
+	utils.Equals(t, false, ok)
+	// From inside
+	d, _, ok = h.Raycast(*vector3.NewVector3Zero(), *vector3.NewVector3(0, -1, 0))
+	utils.Equals(t, true, ok)
+	utils.Equals(t, true, almostEqual(1, d))
+}
+
+func TestMesh_SignedDistance(t *testing.T) {
+	m := NewMeshSquareCuboid(2, true)
+	utils.Equals(t, true, m.Contains(*vector3.NewVector3Zero()))
+	utils.Equals(t, true, m.Contains(*vector3.NewVector3(0.99, -0.99, 0.5)))
+	utils.Equals(t, false, m.Contains(*vector3.NewVector3(1.01, 0, 0)))
+	utils.Equals(t, true, almostEqual(-1, m.SignedDistance(*vector3.NewVector3Zero())))
+	utils.Equals(t, true, almostEqual(-0.25, m.SignedDistance(*vector3.NewVector3(0, 0.75, 0))))
+	utils.Equals(t, true, almostEqual(math.Sqrt(3), m.SignedDistance(*vector3.NewVector3(2, 2, 2))))
+	utils.Equals(t, true, almostEqual(1, m.Distance(*vector3.NewVector3Zero())))
+	utils.Equals(t, true, m.ClosestPoint(*vector3.NewVector3(0.2, 0.3, 5)).Distance(*vector3.NewVector3(0.2, 0.3, 1)) < 1e-12)
+	// Exactly on the axes of the edges of the cube
+	utils.Equals(t, true, m.Contains(*vector3.NewVector3(0, 0, 0.5)))
+
+	s := NewMeshBVH(testSphere(1, 16, 32))
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		p := *vector3.NewVector3(r.Float64()*3-1.5, r.Float64()*3-1.5, r.Float64()*3-1.5)
+		// Away from the facets, inside the polyhedron is inside the sphere
+		if n := p.Norm2(); n < 0.95 || n > 1.01 {
+			utils.Equals(t, n < 1, s.Contains(p))
+		}
+	}
+}
+
+func TestMesh_EditedInPlace(t *testing.T) {
+	m := NewMeshSquareCuboid(2, true)
+	h := NewMeshBVH(m)
+	utils.Equals(t, true, m.Contains(*vector3.NewVector3Zero()))
+	// The mesh queries see the vertices moved in place, a kept hierarchy is a snapshot of the mesh
+	for _, v := range m.Vertices {
+		v.Add(vector3.NewVector3(10, 0, 0))
+	}
+	utils.Equals(t, false, m.Contains(*vector3.NewVector3Zero()))
+	utils.Equals(t, true, m.Contains(*vector3.NewVector3(10, 0, 0)))
+	utils.Equals(t, 9., m.Distance(*vector3.NewVector3Zero()))
+	utils.Equals(t, true, h.Contains(*vector3.NewVector3Zero()))
+	utils.Equals(t, true, NewMeshBVH(m).Contains(*vector3.NewVector3(10, 0, 0)))
+}
+
+func BenchmarkMeshBVH_ClosestPoint(b *testing.B) {
+	h := NewMeshBVH(testSphere(1, 64, 128))
+	p := *vector3.NewVector3(0.3, 1.2, -0.4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.ClosestPoint(p)
+	}
+}
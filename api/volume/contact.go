@@ -0,0 +1,100 @@
+package volume
+
+import (
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// Contact is a pair of crossing triangles of two meshes, by index in Tris / 3,
+// with the segment along which they cross
+type Contact struct {
+	TriangleA, TriangleB int
+	Start, End           vector3.Vector3
+}
+
+// overlaps returns whether the boxes of two nodes overlap
+func (n *bvhNode) overlaps(o *bvhNode) bool {
+	return !(n.max.X < o.min.X || o.max.X < n.min.X ||
+		n.max.Y < o.min.Y || o.max.Y < n.min.Y ||
+		n.max.Z < o.min.Z || o.max.Z < n.min.Z)
+}
+
+// crossings descends both hierarchies together and calls visit for every pair of crossing triangles,
+// stopping as soon as it returns false
+func (h *MeshBVH) crossings(o *MeshBVH, visit func(c Contact) bool) {
+	if len(h.nodes) == 0 || len(o.nodes) == 0 {
+		return
+	}
+	stack := [][2]int{{0, 0}}
+	for len(stack) > 0 {
+		pair := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		a, b := &h.nodes[pair[0]], &o.nodes[pair[1]]
+		if !a.overlaps(b) {
+			continue
+		}
+		switch {
+		case a.count > 0 && b.count > 0:
+			for i := a.start; i < a.start+a.count; i++ {
+				ta := &h.triangles[i]
+				for j := b.start; j < b.start+b.count; j++ {
+					tb := &o.triangles[j]
+					start, end, ok := IntersectTriangles(ta.a, ta.b, ta.c, tb.a, tb.b, tb.c)
+					if ok && !visit(Contact{TriangleA: ta.index, TriangleB: tb.index, Start: start, End: end}) {
+						return
+					}
+				}
+			}
+		// Descend into the inner node, the larger one if both are
+		case b.count > 0 || (a.count == 0 && a.max.Minus(a.min).Norm() >= b.max.Minus(b.min).Norm()):
+			stack = append(stack, [2]int{a.left, pair[1]}, [2]int{a.right, pair[1]})
+		default:
+			stack = append(stack, [2]int{pair[0], b.left}, [2]int{pair[0], b.right})
+		}
+	}
+}
+
+// Contacts returns the crossing triangles of both hierarchies
+func (h *MeshBVH) Contacts(o *MeshBVH) []Contact {
+	var contacts []Contact
+	h.crossings(o, func(c Contact) bool {
+		contacts = append(contacts, c)
+		return true
+	})
+	return contacts
+}
+
+// Crosses returns whether any triangle of the hierarchy crosses one of the other
+func (h *MeshBVH) Crosses(o *MeshBVH) bool {
+	crossed := false
+	h.crossings(o, func(Contact) bool {
+		crossed = true
+		return false
+	})
+	return crossed
+}
+
+// Intersects returns whether the surfaces cross or one closed mesh holds the other
+func (h *MeshBVH) Intersects(o *MeshBVH) bool {
+	if len(h.triangles) == 0 || len(o.triangles) == 0 {
+		return false
+	}
+	if h.Crosses(o) {
+		return true
+	}
+	return h.Contains(o.triangles[0].a) || o.Contains(h.triangles[0].a)
+}
+
+// Contacts returns the pairs of crossing triangles of the meshes and where they cross
+// It builds the hierarchies of both meshes, MeshBVH.Contacts reuses kept ones
+func (m *Mesh) Contacts(o *Mesh) []Contact {
+	return NewMeshBVH(m).Contacts(NewMeshBVH(o))
+}
+
+// IntersectingTriangles returns the pairs of crossing triangles of the meshes, by index in Tris / 3
+func (m *Mesh) IntersectingTriangles(o *Mesh) [][2]int {
+	var pairs [][2]int
+	for _, c := range m.Contacts(o) {
+		pairs = append(pairs, [2]int{c.TriangleA, c.TriangleB})
+	}
+	return pairs
+}
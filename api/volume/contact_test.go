@@ -0,0 +1,118 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestMesh_Intersects(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	b := translated(a, *vector3.NewVector3(0.5, 0.5, 0.5))
+	utils.Equals(t, true, a.Intersects(b))
+	utils.Equals(t, true, b.Intersects(a))
+
+	far := translated(a, *vector3.NewVector3(1.5, 0, 0))
+	utils.Equals(t, false, a.Intersects(far))
+
+	// Held inside without crossing
+	small := NewMeshSquareCuboid(0.2, true)
+	utils.Equals(t, true, a.Intersects(small))
+	utils.Equals(t, true, small.Intersects(a))
+
+	sphere := testSphere(0.3, 8, 16)
+	utils.Equals(t, true, a.Intersects(translated(sphere, *vector3.NewVector3(0.7, 0, 0))))
+	utils.Equals(t, false, a.Intersects(translated(sphere, *vector3.NewVector3(0.9, 0, 0))))
+
+	utils.Equals(t, true, a.Intersects(&Sphere{Center: vector3.NewVector3(0.7, 0, 0), Radius: 0.3}))
+	utils.Equals(t, true, a.Intersects(&Sphere{Center: vector3.NewVector3Zero(), Radius: 0.1}))
+	utils.Equals(t, false, a.Intersects(&Sphere{Center: vector3.NewVector3(2, 0, 0), Radius: 1}))
+
+	utils.Equals(t, false, a.Intersects(&Mesh{}))
+
+	// A capsule without axis is the ball of diameter Width
+	utils.Equals(t, true, a.Intersects(&Capsule{Center: vector3.NewVector3(0.7, 0, 0), Width: 0.6}))
+	utils.Equals(t, false, a.Intersects(&Capsule{Center: vector3.NewVector3(0.7, 0, 0), Width: 0.2}))
+	utils.Equals(t, true, a.Intersects(&Capsule{Width: 0.1}))
+
+	// Other volumes are false, even the ones asking the mesh back
+	utils.Equals(t, false, a.Intersects(stubVolume{}))
+	utils.Equals(t, false, stubVolume{}.Intersects(a))
+	utils.Equals(t, false, a.Intersects(nil))
+
+	// Kept hierarchies
+	h := NewMeshBVH(a)
+	utils.Equals(t, true, h.Intersects(NewMeshBVH(b)))
+	utils.Equals(t, true, h.Intersects(NewMeshBVH(small)))
+	utils.Equals(t, false, h.Intersects(NewMeshBVH(far)))
+	utils.Equals(t, false, h.Intersects(NewMeshBVH(&Mesh{})))
+}
+
+// stubVolume is a volume of another package, delegating its intersection test to the other volume
+type stubVolume struct{}
+
+func (s stubVolume) Fit(Volume) bool          { return false }
+func (s stubVolume) Intersects(v Volume) bool { return v.Intersects(s) }
+func (s stubVolume) Average(Volume) Volume    { return nil }
+func (s stubVolume) Mutate(float64) Volume    { return nil }
+
+func TestMesh_IntersectsBox(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	utils.Equals(t, true, a.IntersectsBox(*NewBoxMinMax(0.4, 0.4, 0.4, 1, 1, 1)))
+	utils.Equals(t, false, a.IntersectsBox(*NewBoxMinMax(0.6, -0.1, -0.1, 1, 0.1, 0.1)))
+	// Held inside, and holding the mesh
+	utils.Equals(t, true, a.IntersectsBox(*NewBoxMinMax(-0.1, -0.1, -0.1, 0.1, 0.1, 0.1)))
+	utils.Equals(t, true, a.IntersectsBox(*NewBoxMinMax(-5, -5, -5, 5, 5, 5)))
+	// Next to a slanted face, inside the bounding box of the mesh but not the mesh
+	s := testSphere(1, 16, 32)
+	utils.Equals(t, false, s.IntersectsBox(*NewBoxMinMax(0.8, 0.8, 0.8, 1, 1, 1)))
+	utils.Equals(t, true, s.IntersectsBox(*NewBoxMinMax(0.5, 0.5, 0.5, 1, 1, 1)))
+	utils.Equals(t, false, (&Mesh{}).IntersectsBox(*NewBoxMinMax(-1, -1, -1, 1, 1, 1)))
+}
+
+func TestMesh_IntersectsCapsule(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	p, q := *vector3.NewVector3(-2, 0.7, 0), *vector3.NewVector3(2, 0.7, 0)
+	utils.Equals(t, true, a.IntersectsCapsule(p, q, 0.3))
+	utils.Equals(t, false, a.IntersectsCapsule(p, q, 0.1))
+	// Through the mesh, and held inside it
+	utils.Equals(t, true, a.IntersectsCapsule(*vector3.NewVector3(-2, 0, 0), *vector3.NewVector3(2, 0, 0), 0))
+	utils.Equals(t, true, a.IntersectsCapsule(*vector3.NewVector3(-0.1, 0, 0), *vector3.NewVector3(0.1, 0, 0), 0.05))
+	utils.Equals(t, true, almostEqual(0.2, NewMeshBVH(a).SegmentDistance(p, q)))
+}
+
+func TestMesh_Contacts(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	b := translated(a, *vector3.NewVector3(0.5, 0.5, 0.5))
+	contacts := a.Contacts(b)
+	utils.Equals(t, true, len(contacts) > 0)
+	for _, c := range contacts {
+		// The surfaces cross on the faces of the overlapping corner
+		for _, p := range []vector3.Vector3{c.Start, c.End} {
+			utils.Equals(t, true, p.X >= -1e-9 && p.Y >= -1e-9 && p.Z >= -1e-9)
+			utils.Equals(t, true, p.X <= 0.5+1e-9 && p.Y <= 0.5+1e-9 && p.Z <= 0.5+1e-9)
+		}
+		ta, tb := a.Tris[c.TriangleA*3:c.TriangleA*3+3], b.Tris[c.TriangleB*3:c.TriangleB*3+3]
+		_, _, ok := IntersectTriangles(*a.Vertices[ta[0]], *a.Vertices[ta[1]], *a.Vertices[ta[2]],
+			*b.Vertices[tb[0]], *b.Vertices[tb[1]], *b.Vertices[tb[2]])
+		utils.Equals(t, true, ok)
+	}
+	utils.Equals(t, len(contacts), len(a.IntersectingTriangles(b)))
+
+	// Same pairs as checking them all
+	s := testSphere(0.6, 12, 24)
+	brute := 0
+	for i := 0; i < a.triangleCount(); i++ {
+		for j := 0; j < s.triangleCount(); j++ {
+			a0, a1, a2 := a.triangle(i)
+			b0, b1, b2 := s.triangle(j)
+			if _, _, ok := IntersectTriangles(a0, a1, a2, b0, b1, b2); ok {
+				brute++
+			}
+		}
+	}
+	utils.Equals(t, brute, len(a.IntersectingTriangles(s)))
+	utils.Equals(t, true, brute > 0)
+	utils.Equals(t, 0, len(a.Contacts(translated(a, *vector3.NewVector3(3, 0, 0)))))
+}
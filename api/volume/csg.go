@@ -0,0 +1,425 @@
+package volume
+
+import (
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// csgEpsilon is the thickness of the planes when classifying points against them
+const csgEpsilon = 1e-5
+
+// csgVertex is a polygon corner carrying the attributes interpolated when polygons are split
+type csgVertex struct {
+	pos, normal, uv vector3.Vector3
+}
+
+func (v csgVertex) lerp(o csgVertex, t float64) csgVertex {
+	return csgVertex{
+		pos:    *v.pos.Lerp(&o.pos, t),
+		normal: *v.normal.Lerp(&o.normal, t),
+		uv:     *v.uv.Lerp(&o.uv, t),
+	}
+}
+
+type csgPlane struct {
+	normal vector3.Vector3
+	w      float64
+}
+
+func (p csgPlane) flipped() csgPlane {
+	return csgPlane{normal: p.normal.Times(-1), w: -p.w}
+}
+
+type csgPolygon struct {
+	vertices []csgVertex
+	plane    csgPlane
+}
+
+func (p csgPolygon) flipped() csgPolygon {
+	vertices := make([]csgVertex, len(p.vertices))
+	for i, v := range p.vertices {
+		v.normal = v.normal.Times(-1)
+		vertices[len(p.vertices)-1-i] = v
+	}
+	return csgPolygon{vertices: vertices, plane: p.plane.flipped()}
+}
+
+const (
+	csgCoplanar = 0
+	csgFront    = 1
+	csgBack     = 2
+	csgSpanning = 3
+)
+
+// split sorts the polygon in one of the four lists, cutting it in two if it spans the plane
+func (p csgPlane) split(polygon csgPolygon, coplanarFront, coplanarBack, front, back *[]csgPolygon) {
+	types := make([]int, len(polygon.vertices))
+	polygonType := 0
+	for i, v := range polygon.vertices {
+		t := p.normal.Dot(v.pos) - p.w
+		switch {
+		case t < -csgEpsilon:
+			types[i] = csgBack
+		case t > csgEpsilon:
+			types[i] = csgFront
+		default:
+			types[i] = csgCoplanar
+		}
+		polygonType |= types[i]
+	}
+	switch polygonType {
+	case csgCoplanar:
+		if p.normal.Dot(polygon.plane.normal) > 0 {
+			*coplanarFront = append(*coplanarFront, polygon)
+		} else {
+			*coplanarBack = append(*coplanarBack, polygon)
+		}
+	case csgFront:
+		*front = append(*front, polygon)
+	case csgBack:
+		*back = append(*back, polygon)
+	case csgSpanning:
+		var f, b []csgVertex
+		n := len(polygon.vertices)
+		for i := 0; i < n; i++ {
+			j := (i + 1) % n
+			ti, tj := types[i], types[j]
+			vi, vj := polygon.vertices[i], polygon.vertices[j]
+			if ti != csgBack {
+				f = append(f, vi)
+			}
+			if ti != csgFront {
+				b = append(b, vi)
+			}
+			if ti|tj == csgSpanning {
+				t := (p.w - p.normal.Dot(vi.pos)) / p.normal.Dot(vj.pos.Minus(vi.pos))
+				v := vi.lerp(vj, t)
+				f = append(f, v)
+				b = append(b, v)
+			}
+		}
+		if len(f) >= 3 {
+			*front = append(*front, csgPolygon{vertices: f, plane: polygon.plane})
+		}
+		if len(b) >= 3 {
+			*back = append(*back, csgPolygon{vertices: b, plane: polygon.plane})
+		}
+	}
+}
+
+// csgNode is a node of a BSP tree, the polygons lie in its plane
+type csgNode struct {
+	plane       *csgPlane
+	front, back *csgNode
+	polygons    []csgPolygon
+}
+
+func newCsgNode(polygons []csgPolygon) *csgNode {
+	n := &csgNode{}
+	n.build(polygons)
+	return n
+}
+
+// invert swaps solid and empty space
+func (n *csgNode) invert() {
+	for i := range n.polygons {
+		n.polygons[i] = n.polygons[i].flipped()
+	}
+	if n.plane != nil {
+		p := n.plane.flipped()
+		n.plane = &p
+	}
+	if n.front != nil {
+		n.front.invert()
+	}
+	if n.back != nil {
+		n.back.invert()
+	}
+	n.front, n.back = n.back, n.front
+}
+
+// clipPolygons removes the parts of the polygons inside the tree
+func (n *csgNode) clipPolygons(polygons []csgPolygon) []csgPolygon {
+	if n.plane == nil {
+		return append([]csgPolygon(nil), polygons...)
+	}
+	var front, back []csgPolygon
+	for _, p := range polygons {
+		n.plane.split(p, &front, &back, &front, &back)
+	}
+	if n.front != nil {
+		front = n.front.clipPolygons(front)
+	}
+	if n.back != nil {
+		back = n.back.clipPolygons(back)
+	} else {
+		back = nil
+	}
+	return append(front, back...)
+}
+
+// clipTo removes the parts of this tree polygons inside the other tree
+func (n *csgNode) clipTo(o *csgNode) {
+	n.polygons = o.clipPolygons(n.polygons)
+	if n.front != nil {
+		n.front.clipTo(o)
+	}
+	if n.back != nil {
+		n.back.clipTo(o)
+	}
+}
+
+func (n *csgNode) allPolygons() []csgPolygon {
+	polygons := append([]csgPolygon(nil), n.polygons...)
+	if n.front != nil {
+		polygons = append(polygons, n.front.allPolygons()...)
+	}
+	if n.back != nil {
+		polygons = append(polygons, n.back.allPolygons()...)
+	}
+	return polygons
+}
+
+func (n *csgNode) build(polygons []csgPolygon) {
+	if len(polygons) == 0 {
+		return
+	}
+	if n.plane == nil {
+		p := polygons[0].plane
+		n.plane = &p
+	}
+	var front, back []csgPolygon
+	for _, p := range polygons {
+		n.plane.split(p, &n.polygons, &n.polygons, &front, &back)
+	}
+	if len(front) > 0 {
+		if n.front == nil {
+			n.front = &csgNode{}
+		}
+		n.front.build(front)
+	}
+	if len(back) > 0 {
+		if n.back == nil {
+			n.back = &csgNode{}
+		}
+		n.back.build(back)
+	}
+}
+
+// csgPolygons converts the non-degenerate triangles of the mesh to polygons,
+// using the face normal where the mesh has no normals
+func (m *Mesh) csgPolygons() []csgPolygon {
+	src := m.RemoveDegenerateTriangles(0)
+	hasNormals := len(src.Normals) == len(src.Vertices)
+	hasUvs := len(src.Uvs) == len(src.Vertices)
+	polygons := make([]csgPolygon, 0, src.triangleCount())
+	for i := 0; i < src.triangleCount(); i++ {
+		a, b, c := src.triangle(i)
+		normal := b.Minus(a).Cross(c.Minus(a)).Normalize()
+		p := csgPolygon{plane: csgPlane{normal: normal, w: normal.Dot(a)}}
+		for _, v := range src.Tris[i*3 : i*3+3] {
+			cv := csgVertex{pos: *src.Vertices[v], normal: normal}
+			if hasNormals {
+				cv.normal = *src.Normals[v]
+			}
+			if hasUvs {
+				cv.uv = *src.Uvs[v]
+			}
+			p.vertices = append(p.vertices, cv)
+		}
+		polygons = append(polygons, p)
+	}
+	return polygons
+}
+
+// csgMesh triangulates the polygons back into a mesh, sharing the vertices with identical attributes
+// Normals and uvs are only output when one of the operands had them
+func csgMesh(polygons []csgPolygon, normals, uvs bool) *Mesh {
+	// Cuts computed from both sides of an edge can differ by a rounding error, snap them together first
+	positions := &Mesh{}
+	for _, p := range polygons {
+		for i := range p.vertices {
+			positions.Vertices = append(positions.Vertices, &p.vertices[i].pos)
+		}
+	}
+	snap := positions.weldMap(csgEpsilon * 1e-3)
+
+	type key struct {
+		pos        int32
+		normal, uv [3]float64
+	}
+	out := &Mesh{}
+	index := make(map[key]int32)
+	add := func(v csgVertex, pos int32) int32 {
+		k := key{pos: pos}
+		if normals {
+			k.normal = [3]float64{v.normal.X, v.normal.Y, v.normal.Z}
+		}
+		if uvs {
+			k.uv = [3]float64{v.uv.X, v.uv.Y, v.uv.Z}
+		}
+		if i, ok := index[k]; ok {
+			return i
+		}
+		i := int32(len(out.Vertices))
+		index[k] = i
+		out.Vertices = append(out.Vertices, positions.Vertices[pos].Clone())
+		if normals {
+			n := v.normal.Normalize()
+			out.Normals = append(out.Normals, &n)
+		}
+		if uvs {
+			out.Uvs = append(out.Uvs, v.uv.Clone())
+		}
+		return i
+	}
+	offset := 0
+	for _, p := range polygons {
+		corners := make([]int32, len(p.vertices))
+		for i, v := range p.vertices {
+			corners[i] = add(v, snap[offset+i])
+		}
+		offset += len(p.vertices)
+		for i := 1; i+1 < len(corners); i++ {
+			out.Tris = append(out.Tris, corners[0], corners[i], corners[i+1])
+		}
+	}
+	return out.RemoveDegenerateTriangles(0).fixTJunctions(csgEpsilon)
+}
+
+// fixTJunctions splits the triangles having a vertex of another triangle in the middle of one of their edges,
+// so that the surface is closed in terms of shared vertices and not only geometrically
+// In-place
+func (m *Mesh) fixTJunctions(tolerance float64) *Mesh {
+	hasNormals := len(m.Normals) == len(m.Vertices)
+	hasUvs := len(m.Uvs) == len(m.Vertices)
+	for changed := true; changed; {
+		changed = false
+		weld := m.weldMap(0)
+		welded := make([]int32, len(m.Tris))
+		for i, v := range m.Tris {
+			welded[i] = weld[v]
+		}
+		edges := newEdgeTable(welded, nil)
+		var candidates []int32
+		seen := make(map[int32]bool)
+		for _, key := range edges.keys {
+			if len(edges.uses[key]) != 1 {
+				continue
+			}
+			for _, v := range []int32{key.a, key.b} {
+				if !seen[v] {
+					seen[v] = true
+					candidates = append(candidates, v)
+				}
+			}
+		}
+		split := make([]bool, m.triangleCount())
+		for _, key := range edges.keys {
+			uses := edges.uses[key]
+			if len(uses) != 1 || split[uses[0].face] {
+				continue
+			}
+			f := uses[0].face
+			a, b := *m.Vertices[key.a], *m.Vertices[key.b]
+			ab := b.Minus(a)
+			length2 := ab.Dot(ab)
+			best, bestT := int32(-1), 1.
+			for _, v := range candidates {
+				if v == key.a || v == key.b {
+					continue
+				}
+				p := *m.Vertices[v]
+				t := p.Minus(a).Dot(ab) / length2
+				if t <= 0 || t >= 1 || t >= bestT {
+					continue
+				}
+				closest := a.Plus(ab.Times(t))
+				if closest.Distance(p) < tolerance {
+					best, bestT = v, t
+				}
+			}
+			if best == -1 {
+				continue
+			}
+			// Rotate the triangle so that the split edge comes first
+			t := m.Tris[f*3 : f*3+3]
+			k := 0
+			for newEdgeKey(weld[t[k]], weld[t[(k+1)%3]]) != key {
+				k++
+			}
+			i0, i1, i2 := t[k], t[(k+1)%3], t[(k+2)%3]
+			// New vertex on the edge, with the attributes of this triangle rather than the ones of best
+			s := bestT
+			if weld[i0] == key.b {
+				s = 1 - bestT
+			}
+			v := best
+			if hasNormals || hasUvs {
+				v = int32(len(m.Vertices))
+				m.Vertices = append(m.Vertices, m.Vertices[best].Clone())
+			}
+			if hasNormals {
+				n := m.Normals[i0].Lerp(m.Normals[i1], s).Normalize()
+				m.Normals = append(m.Normals, &n)
+			}
+			if hasUvs {
+				m.Uvs = append(m.Uvs, m.Uvs[i0].Lerp(m.Uvs[i1], s))
+			}
+			t[0], t[1], t[2] = i0, v, i2
+			m.Tris = append(m.Tris, v, i1, i2)
+			split[f] = true
+			split = append(split, true)
+			changed = true
+		}
+	}
+	return m
+}
+
+// csgOperands builds the BSP trees of both meshes
+func csgOperands(a, b *Mesh) (*csgNode, *csgNode, bool, bool) {
+	normals := len(a.Normals) > 0 || len(b.Normals) > 0
+	uvs := len(a.Uvs) > 0 || len(b.Uvs) > 0
+	return newCsgNode(a.csgPolygons()), newCsgNode(b.csgPolygons()), normals, uvs
+}
+
+// Union returns the solid made of the space inside either of the closed meshes
+// Not in-place
+func (m *Mesh) Union(o *Mesh) *Mesh {
+	a, b, normals, uvs := csgOperands(m, o)
+	a.clipTo(b)
+	b.clipTo(a)
+	b.invert()
+	b.clipTo(a)
+	b.invert()
+	a.build(b.allPolygons())
+	return csgMesh(a.allPolygons(), normals, uvs)
+}
+
+// Intersection returns the solid made of the space inside both closed meshes
+// Not in-place
+func (m *Mesh) Intersection(o *Mesh) *Mesh {
+	a, b, normals, uvs := csgOperands(m, o)
+	a.invert()
+	b.clipTo(a)
+	b.invert()
+	a.clipTo(b)
+	b.clipTo(a)
+	a.build(b.allPolygons())
+	a.invert()
+	return csgMesh(a.allPolygons(), normals, uvs)
+}
+
+// Difference returns the solid made of the space inside this closed mesh but outside the other one
+// Not in-place
+func (m *Mesh) Difference(o *Mesh) *Mesh {
+	a, b, normals, uvs := csgOperands(m, o)
+	a.invert()
+	a.clipTo(b)
+	b.clipTo(a)
+	b.invert()
+	b.clipTo(a)
+	b.invert()
+	a.build(b.allPolygons())
+	a.invert()
+	return csgMesh(a.allPolygons(), normals, uvs)
+}
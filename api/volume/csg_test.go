@@ -0,0 +1,77 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// translated returns a copy of the mesh moved by offset
+func translated(m *Mesh, offset vector3.Vector3) *Mesh {
+	out := m.copyVertices()
+	out.Tris = append(out.Tris, m.Tris...)
+	for _, v := range out.Vertices {
+		v.Add(&offset)
+	}
+	return out
+}
+
+func TestMesh_Union(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	b := translated(a, *vector3.NewVector3(0.5, 0.5, 0.5))
+	u := a.Union(b)
+	utils.Equals(t, true, almostEqual(1.875, u.GetVolume()))
+	utils.Equals(t, true, u.Validate(1e-9).IsValid())
+	// Not in-place
+	utils.Equals(t, true, almostEqual(1, a.GetVolume()))
+
+	// Disjoint operands are kept as they are
+	c := translated(a, *vector3.NewVector3(3, 0, 0))
+	utils.Equals(t, true, almostEqual(2, a.Union(c).GetVolume()))
+}
+
+func TestMesh_Intersection(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	b := translated(a, *vector3.NewVector3(0.5, 0.5, 0.5))
+	i := a.Intersection(b)
+	utils.Equals(t, true, almostEqual(0.125, i.GetVolume()))
+	utils.Equals(t, true, i.Validate(1e-9).IsValid())
+
+	c := translated(a, *vector3.NewVector3(3, 0, 0))
+	utils.Equals(t, 0, len(a.Intersection(c).Tris))
+}
+
+func TestMesh_Difference(t *testing.T) {
+	a := NewMeshSquareCuboid(1, true)
+	b := translated(a, *vector3.NewVector3(0.5, 0.5, 0.5))
+	d := a.Difference(b)
+	utils.Equals(t, true, almostEqual(0.875, d.GetVolume()))
+	utils.Equals(t, true, d.Validate(1e-9).IsValid())
+
+	// Carving a hole through a wall
+	wall := NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(4, 3, 0.5))
+	door := NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(1, 2, 1))
+	d = wall.Difference(door)
+	utils.Equals(t, true, almostEqual(6-1, d.GetVolume()))
+	utils.Equals(t, true, d.Validate(1e-9).IsValid())
+	h, _ := NewHalfEdgeMesh(d)
+	// A solid with a hole through it has genus 1
+	utils.Equals(t, 0, h.EulerCharacteristic())
+}
+
+func TestMesh_CsgAttributes(t *testing.T) {
+	a := unwrappedCuboid()
+	a.RecalculateNormals()
+	b := testSphere(0.6, 12, 24)
+	b.RecalculateNormals()
+	d := a.Difference(b)
+	utils.Equals(t, len(d.Vertices), len(d.Normals))
+	utils.Equals(t, len(d.Vertices), len(d.Uvs))
+	utils.Equals(t, true, d.WeldVertices(1e-9).Validate(1e-9).IsValid())
+	utils.Equals(t, true, d.GetVolume() < 1 && d.GetVolume() > 0.1)
+	for _, n := range d.Normals {
+		utils.Equals(t, true, math.Abs(n.Norm2()-1) < 1e-6)
+	}
+}
@@ -0,0 +1,266 @@
+package volume
+
+import (
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// DecompositionOptions controls Mesh.ConvexDecomposition
+type DecompositionOptions struct {
+	// MaxHulls bounds the number of convex parts
+	MaxHulls int
+	// MaxConcavity stops splitting the parts whose hull exceeds their volume by less than
+	// this fraction of the volume of the whole mesh hull
+	MaxConcavity float64
+	// Resolution is the number of voxels along the longest side of the mesh bounding box, 32 if 0
+	Resolution int
+}
+
+// voxelPart is a set of voxels of a dense grid, with the hull of its boundary voxels
+type voxelPart struct {
+	voxels    [][3]int
+	hull      *Mesh
+	concavity float64
+}
+
+// decomposer splits the solid voxels of a mesh along axis aligned planes
+type decomposer struct {
+	origin vector3.Vector3
+	size   float64
+	dims   [3]int
+	total  float64
+}
+
+// columnCrossings returns the heights at which the vertical line through (x, y) crosses the triangles, sorted
+func columnCrossings(m *Mesh, x, y float64) []float64 {
+	var zs []float64
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := m.triangle(i)
+		// Barycentric coordinates of (x, y) in the triangle projected on the xy plane
+		d := (b.Y-c.Y)*(a.X-c.X) + (c.X-b.X)*(a.Y-c.Y)
+		if d == 0 {
+			continue
+		}
+		l1 := ((b.Y-c.Y)*(x-c.X) + (c.X-b.X)*(y-c.Y)) / d
+		l2 := ((c.Y-a.Y)*(x-c.X) + (a.X-c.X)*(y-c.Y)) / d
+		l3 := 1 - l1 - l2
+		if l1 < 0 || l2 < 0 || l3 < 0 {
+			continue
+		}
+		zs = append(zs, l1*a.Z+l2*b.Z+l3*c.Z)
+	}
+	sort.Float64s(zs)
+	return zs
+}
+
+// solidVoxels returns the voxels of a dims grid of cubes of size side from origin whose center is inside the
+// closed mesh, by ray parity along z
+func solidVoxels(m *Mesh, origin vector3.Vector3, side float64, dims [3]int) [][3]int {
+	var voxels [][3]int
+	// Slightly off center so that the rays don't run along the edges of grid aligned meshes
+	const jitter = 1e-7
+	for i := 0; i < dims[0]; i++ {
+		for j := 0; j < dims[1]; j++ {
+			x := origin.X + (float64(i)+0.5+jitter)*side
+			y := origin.Y + (float64(j)+0.5+jitter*math.Sqrt2)*side
+			zs := columnCrossings(m, x, y)
+			for k := 0; k < dims[2]; k++ {
+				z := origin.Z + (float64(k)+0.5)*side
+				below := sort.SearchFloat64s(zs, z)
+				if below%2 == 1 {
+					voxels = append(voxels, [3]int{i, j, k})
+				}
+			}
+		}
+	}
+	return voxels
+}
+
+// evaluate builds the hull of the part voxels and its concavity
+func (d *decomposer) evaluate(voxels [][3]int) *voxelPart {
+	p := &voxelPart{voxels: voxels}
+	in := make(map[[3]int]bool, len(voxels))
+	for _, v := range voxels {
+		in[v] = true
+	}
+	corners := make(map[[3]int]bool)
+	for _, v := range voxels {
+		boundary := false
+		for _, n := range [6][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}} {
+			if !in[[3]int{v[0] + n[0], v[1] + n[1], v[2] + n[2]}] {
+				boundary = true
+				break
+			}
+		}
+		if !boundary {
+			continue
+		}
+		for c := 0; c < 8; c++ {
+			corners[[3]int{v[0] + c&1, v[1] + c>>1&1, v[2] + c>>2&1}] = true
+		}
+	}
+	points := make([]vector3.Vector3, 0, len(corners))
+	for c := range corners {
+		points = append(points, *vector3.NewVector3(
+			d.origin.X+float64(c[0])*d.size,
+			d.origin.Y+float64(c[1])*d.size,
+			d.origin.Z+float64(c[2])*d.size,
+		))
+	}
+	// Map iteration order is random, sort for reproducible hulls
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.Z < b.Z
+	})
+	hull, err := NewMeshConvexHull(points)
+	if err != nil {
+		return p
+	}
+	p.hull = hull
+	volume := float64(len(voxels)) * d.size * d.size * d.size
+	p.concavity = math.Max(0, hull.GetVolume()-volume)
+	if d.total > 0 {
+		p.concavity /= d.total
+	}
+	return p
+}
+
+// split cuts the part along the best axis aligned plane, the one minimizing the concavity of both sides
+// Planes are first tried every few voxels, then around the best coarse one
+func (d *decomposer) split(p *voxelPart) (*voxelPart, *voxelPart) {
+	var best [2]*voxelPart
+	bestCost, bestAxis, bestCut := math.Inf(1), -1, 0
+	try := func(axis, cut int) {
+		var below, above [][3]int
+		for _, v := range p.voxels {
+			if v[axis] < cut {
+				below = append(below, v)
+			} else {
+				above = append(above, v)
+			}
+		}
+		if len(below) == 0 || len(above) == 0 {
+			return
+		}
+		a, b := d.evaluate(below), d.evaluate(above)
+		if a.hull == nil || b.hull == nil {
+			return
+		}
+		if cost := a.concavity + b.concavity; cost < bestCost {
+			best, bestCost, bestAxis, bestCut = [2]*voxelPart{a, b}, cost, axis, cut
+		}
+	}
+	var lo, hi [3]int
+	for axis := 0; axis < 3; axis++ {
+		lo[axis], hi[axis] = math.MaxInt32, math.MinInt32
+		for _, v := range p.voxels {
+			lo[axis], hi[axis] = minInt(lo[axis], v[axis]), maxInt(hi[axis], v[axis])
+		}
+	}
+	step := 1
+	for axis := 0; axis < 3; axis++ {
+		step = maxInt(step, (hi[axis]-lo[axis])/8)
+	}
+	for axis := 0; axis < 3; axis++ {
+		for cut := lo[axis] + 1; cut <= hi[axis]; cut += step {
+			try(axis, cut)
+		}
+	}
+	if bestAxis != -1 && step > 1 {
+		axis, coarse := bestAxis, bestCut
+		for cut := maxInt(lo[axis]+1, coarse-step+1); cut < coarse+step && cut <= hi[axis]; cut++ {
+			if cut != coarse {
+				try(axis, cut)
+			}
+		}
+	}
+	return best[0], best[1]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ConvexDecomposition approximates a closed mesh by at most MaxHulls convex hulls, in the fashion of V-HACD:
+// the mesh is voxelized then recursively cut along the axis aligned planes reducing concavity the most
+// The hulls enclose the voxels so they can exceed the mesh by up to a voxel
+func (m *Mesh) ConvexDecomposition(o DecompositionOptions) ([]*Mesh, error) {
+	if o.Resolution <= 0 {
+		o.Resolution = 32
+	}
+	if o.MaxHulls <= 0 {
+		o.MaxHulls = 1
+	}
+	hull, err := m.ConvexHull()
+	if err != nil {
+		return nil, err
+	}
+	if o.MaxHulls == 1 {
+		return []*Mesh{hull}, nil
+	}
+	bounds := NewBoxMinMax(math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1))
+	for _, v := range hull.Vertices {
+		bounds.EncapsulatePoint(*v)
+	}
+	size := bounds.GetSize()
+	side := math.Max(size.X, math.Max(size.Y, size.Z)) / float64(o.Resolution)
+	d := &decomposer{origin: *bounds.Min, size: side}
+	d.dims = [3]int{
+		int(math.Ceil(size.X/side - 1e-9)),
+		int(math.Ceil(size.Y/side - 1e-9)),
+		int(math.Ceil(size.Z/side - 1e-9)),
+	}
+	for i := range d.dims {
+		d.dims[i] = maxInt(d.dims[i], 1)
+	}
+
+	voxels := solidVoxels(m, d.origin, side, d.dims)
+	root := d.evaluate(voxels)
+	if root.hull == nil {
+		return []*Mesh{hull}, nil
+	}
+	d.total = root.hull.GetVolume()
+	root.concavity /= d.total
+	parts := []*voxelPart{root}
+	for len(parts) < o.MaxHulls {
+		// Split the most concave part
+		worst := 0
+		for i, p := range parts {
+			if p.concavity > parts[worst].concavity {
+				worst = i
+			}
+		}
+		if parts[worst].concavity <= o.MaxConcavity {
+			break
+		}
+		a, b := d.split(parts[worst])
+		if a == nil {
+			break
+		}
+		parts[worst] = a
+		parts = append(parts, b)
+	}
+	hulls := make([]*Mesh, len(parts))
+	for i, p := range parts {
+		hulls[i] = p.hull
+	}
+	return hulls, nil
+}
@@ -0,0 +1,46 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// lShape returns a closed L made of a 2x1x1 box and a unit cube on top of its left half
+func lShape() *Mesh {
+	return NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(2, 1, 1)).
+		Union(translated(NewMeshSquareCuboid(1, true), *vector3.NewVector3(-0.5, 1, 0)))
+}
+
+func TestMesh_ConvexDecomposition(t *testing.T) {
+	hulls, err := lShape().ConvexDecomposition(DecompositionOptions{MaxHulls: 4, MaxConcavity: 0.01, Resolution: 20})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 2, len(hulls))
+	volume := 0.
+	for _, h := range hulls {
+		utils.Equals(t, true, h.Validate(1e-9).IsValid())
+		volume += h.GetVolume()
+	}
+	// The L is aligned with the voxels, so the parts are exact
+	utils.Equals(t, true, almostEqual(3, volume))
+}
+
+func TestMesh_ConvexDecompositionBounds(t *testing.T) {
+	// A convex mesh needs no cut
+	hulls, err := NewMeshSquareCuboid(1, true).ConvexDecomposition(DecompositionOptions{MaxHulls: 8, MaxConcavity: 0.01, Resolution: 8})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 1, len(hulls))
+
+	// A torus like ring of 4 boxes needs at least 4 parts, but is capped to 3
+	ring := NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(3, 3, 1)).
+		Difference(NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(1, 1, 2)))
+	hulls, err = ring.ConvexDecomposition(DecompositionOptions{MaxHulls: 3, MaxConcavity: 0.001, Resolution: 12})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 3, len(hulls))
+
+	hulls, err = ring.ConvexDecomposition(DecompositionOptions{MaxHulls: 1})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, math.Abs(hulls[0].GetVolume()-9) < 1e-9)
+}
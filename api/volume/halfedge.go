@@ -0,0 +1,257 @@
+package volume
+
+import (
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// HalfEdge is one side of a triangle edge, oriented along the triangle winding
+type HalfEdge struct {
+	// Origin is the vertex the half-edge starts from
+	Origin int32
+	// Twin is the opposite half-edge in the neighbour triangle, -1 on boundary or non-manifold edges
+	Twin int
+	// Next is the following half-edge around the same triangle
+	Next int
+	// Face is the triangle the half-edge belongs to
+	Face int
+}
+
+// HalfEdgeMesh is the adjacency structure of a triangle Mesh
+// The half-edges of the triangle f are 3f, 3f+1 and 3f+2
+type HalfEdgeMesh struct {
+	Vertices  []*vector3.Vector3
+	Normals   []*vector3.Vector3
+	Uvs       []*vector3.Vector3
+	HalfEdges []HalfEdge
+	// vertexEdge is one outgoing half-edge per vertex, -1 for isolated vertices
+	vertexEdge []int
+	// directed maps an ordered pair of vertices to the half-edge joining them
+	directed map[[2]int32]int
+}
+
+// NewHalfEdgeMesh builds the adjacency structure of a mesh
+// Edges shared by more than two triangles or traversed twice in the same direction have no twin
+func NewHalfEdgeMesh(m *Mesh) (*HalfEdgeMesh, error) {
+	if len(m.Tris)%3 != 0 {
+		return nil, utils.ErrMeshIncompleteTriangle
+	}
+	h := &HalfEdgeMesh{
+		Vertices:   m.Vertices,
+		Normals:    m.Normals,
+		Uvs:        m.Uvs,
+		HalfEdges:  make([]HalfEdge, len(m.Tris)),
+		vertexEdge: make([]int, len(m.Vertices)),
+		directed:   make(map[[2]int32]int, len(m.Tris)),
+	}
+	for i := range h.vertexEdge {
+		h.vertexEdge[i] = -1
+	}
+	count := make(map[edgeKey]int, len(m.Tris))
+	for i, v := range m.Tris {
+		if v < 0 || int(v) >= len(m.Vertices) {
+			return nil, utils.ErrMeshInvalidIndex
+		}
+		f := i / 3
+		next := f*3 + (i+1)%3
+		h.HalfEdges[i] = HalfEdge{Origin: v, Twin: -1, Next: next, Face: f}
+		if h.vertexEdge[v] == -1 {
+			h.vertexEdge[v] = i
+		}
+		key := [2]int32{v, m.Tris[next]}
+		if _, ok := h.directed[key]; ok {
+			// Same direction twice, keep it out of the twins
+			count[newEdgeKey(key[0], key[1])] += 2
+			continue
+		}
+		h.directed[key] = i
+		count[newEdgeKey(key[0], key[1])]++
+	}
+	for key, i := range h.directed {
+		if count[newEdgeKey(key[0], key[1])] != 2 {
+			continue
+		}
+		if j, ok := h.directed[[2]int32{key[1], key[0]}]; ok {
+			h.HalfEdges[i].Twin = j
+		}
+	}
+	// Prefer boundary half-edges as the vertex entry point so that walks can start from them
+	for i, e := range h.HalfEdges {
+		if e.Twin == -1 && h.HalfEdges[h.vertexEdge[e.Origin]].Twin != -1 {
+			h.vertexEdge[e.Origin] = i
+		}
+	}
+	return h, nil
+}
+
+// FaceCount returns the number of triangles
+func (h *HalfEdgeMesh) FaceCount() int {
+	return len(h.HalfEdges) / 3
+}
+
+// EdgeCount returns the number of undirected edges
+func (h *HalfEdgeMesh) EdgeCount() int {
+	n := 0
+	for i, e := range h.HalfEdges {
+		// Count each twin pair once
+		if e.Twin == -1 || e.Twin > i {
+			n++
+		}
+	}
+	return n
+}
+
+// Dest returns the vertex the half-edge points to
+func (h *HalfEdgeMesh) Dest(e int) int32 {
+	return h.HalfEdges[h.HalfEdges[e].Next].Origin
+}
+
+// Prev returns the previous half-edge around the same triangle
+func (h *HalfEdgeMesh) Prev(e int) int {
+	return h.HalfEdges[h.HalfEdges[e].Next].Next
+}
+
+// FindHalfEdge returns the half-edge going from a to b, or -1
+func (h *HalfEdgeMesh) FindHalfEdge(a, b int32) int {
+	if e, ok := h.directed[[2]int32{a, b}]; ok {
+		return e
+	}
+	return -1
+}
+
+// IsBoundaryEdge returns whether the half-edge has no twin
+func (h *HalfEdgeMesh) IsBoundaryEdge(e int) bool {
+	return h.HalfEdges[e].Twin == -1
+}
+
+// IsBoundaryVertex returns whether the vertex lies on a boundary, isolated vertices are not
+func (h *HalfEdgeMesh) IsBoundaryVertex(v int32) bool {
+	for _, e := range h.Outgoing(v) {
+		if h.IsBoundaryEdge(e) || h.IsBoundaryEdge(h.Prev(e)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed returns whether every edge is shared by exactly two triangles
+func (h *HalfEdgeMesh) IsClosed() bool {
+	for i := range h.HalfEdges {
+		if h.IsBoundaryEdge(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Outgoing returns the half-edges starting from v, in winding order around the vertex
+// The walk stops at boundaries, so a non-manifold vertex only reports one of its fans
+func (h *HalfEdgeMesh) Outgoing(v int32) []int {
+	start := h.vertexEdge[v]
+	if start == -1 {
+		return nil
+	}
+	var out []int
+	for e := start; ; {
+		out = append(out, e)
+		e = h.HalfEdges[h.Prev(e)].Twin
+		if e == -1 || e == start {
+			break
+		}
+	}
+	return out
+}
+
+// VertexOneRing returns the neighbours of v, in winding order around the vertex
+func (h *HalfEdgeMesh) VertexOneRing(v int32) []int32 {
+	out := h.Outgoing(v)
+	ring := make([]int32, 0, len(out)+1)
+	for _, e := range out {
+		ring = append(ring, h.Dest(e))
+	}
+	if len(out) > 0 {
+		// On boundaries the last neighbour is only reachable through the incoming edge
+		if last := h.Prev(out[len(out)-1]); h.IsBoundaryEdge(last) {
+			ring = append(ring, h.HalfEdges[last].Origin)
+		}
+	}
+	return ring
+}
+
+// VertexFaces returns the triangles around v, in winding order
+func (h *HalfEdgeMesh) VertexFaces(v int32) []int {
+	var faces []int
+	for _, e := range h.Outgoing(v) {
+		faces = append(faces, h.HalfEdges[e].Face)
+	}
+	return faces
+}
+
+// EdgeFaces returns the triangles bordering the edge between a and b, whatever its direction
+func (h *HalfEdgeMesh) EdgeFaces(a, b int32) []int {
+	var faces []int
+	if e := h.FindHalfEdge(a, b); e != -1 {
+		faces = append(faces, h.HalfEdges[e].Face)
+	}
+	if e := h.FindHalfEdge(b, a); e != -1 {
+		faces = append(faces, h.HalfEdges[e].Face)
+	}
+	return faces
+}
+
+// FaceNeighbours returns the triangles sharing an edge with f
+func (h *HalfEdgeMesh) FaceNeighbours(f int) []int {
+	var faces []int
+	for k := 0; k < 3; k++ {
+		if t := h.HalfEdges[f*3+k].Twin; t != -1 {
+			faces = append(faces, h.HalfEdges[t].Face)
+		}
+	}
+	return faces
+}
+
+// BoundaryLoops returns the closed chains of boundary vertices, following the triangle winding
+func (h *HalfEdgeMesh) BoundaryLoops() [][]int32 {
+	visited := make([]bool, len(h.HalfEdges))
+	var loops [][]int32
+	for i := range h.HalfEdges {
+		if visited[i] || !h.IsBoundaryEdge(i) {
+			continue
+		}
+		var loop []int32
+		for e := i; e != -1 && !visited[e]; {
+			visited[e] = true
+			loop = append(loop, h.HalfEdges[e].Origin)
+			e = h.nextBoundary(e)
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
+
+// nextBoundary returns the boundary half-edge starting where e ends, or -1
+func (h *HalfEdgeMesh) nextBoundary(e int) int {
+	// Rotate around the destination vertex until the boundary is reached again
+	n := h.HalfEdges[e].Next
+	for i := 0; i < len(h.HalfEdges); i++ {
+		if h.IsBoundaryEdge(n) {
+			return n
+		}
+		n = h.HalfEdges[h.HalfEdges[n].Twin].Next
+	}
+	return -1
+}
+
+// EulerCharacteristic returns V - E + F, 2 for a closed mesh homeomorphic to a sphere
+func (h *HalfEdgeMesh) EulerCharacteristic() int {
+	return len(h.Vertices) - h.EdgeCount() + h.FaceCount()
+}
+
+// ToMesh converts back to a Mesh sharing the vertices, normals and uvs
+func (h *HalfEdgeMesh) ToMesh() *Mesh {
+	tris := make([]int32, len(h.HalfEdges))
+	for i, e := range h.HalfEdges {
+		tris[i] = e.Origin
+	}
+	return &Mesh{Vertices: h.Vertices, Tris: tris, Normals: h.Normals, Uvs: h.Uvs}
+}
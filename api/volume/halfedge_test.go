@@ -0,0 +1,80 @@
+package volume
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func sorted(s []int32) []int32 {
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s
+}
+
+func TestNewHalfEdgeMesh(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	h, err := NewHalfEdgeMesh(m)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 12, h.FaceCount())
+	utils.Equals(t, 18, h.EdgeCount())
+	utils.Equals(t, 2, h.EulerCharacteristic())
+	utils.Equals(t, true, h.IsClosed())
+	utils.Equals(t, 0, len(h.BoundaryLoops()))
+	for i, e := range h.HalfEdges {
+		utils.Equals(t, i, h.HalfEdges[e.Twin].Twin)
+		utils.Equals(t, e.Origin, h.Dest(e.Twin))
+	}
+	utils.Equals(t, m.Tris, h.ToMesh().Tris)
+
+	_, err = NewHalfEdgeMesh(&Mesh{Vertices: m.Vertices, Tris: []int32{0, 1}})
+	utils.Equals(t, utils.ErrMeshIncompleteTriangle, err)
+	_, err = NewHalfEdgeMesh(&Mesh{Vertices: m.Vertices, Tris: []int32{0, 1, 8}})
+	utils.Equals(t, utils.ErrMeshInvalidIndex, err)
+}
+
+func TestHalfEdgeMesh_VertexOneRing(t *testing.T) {
+	h, _ := NewHalfEdgeMesh(NewMeshSquareCuboid(1, true))
+	// Vertex 0 is a corner, linked to its 3 cube neighbours and 3 face diagonals
+	utils.Equals(t, []int32{1, 2, 3, 4, 6, 7}, sorted(h.VertexOneRing(0)))
+	utils.Equals(t, 6, len(h.VertexFaces(0)))
+	utils.Equals(t, false, h.IsBoundaryVertex(0))
+	utils.Equals(t, 2, len(h.EdgeFaces(0, 2)))
+	utils.Equals(t, 2, len(h.EdgeFaces(2, 0)))
+	utils.Equals(t, 0, len(h.EdgeFaces(0, 5)))
+	utils.Equals(t, 3, len(h.FaceNeighbours(0)))
+}
+
+func TestHalfEdgeMesh_Boundary(t *testing.T) {
+	// A square made of 4 triangles around a center vertex
+	m := &Mesh{
+		Vertices: []*vector3.Vector3{
+			vector3.NewVector3(0, 0, 0),
+			vector3.NewVector3(1, 0, 0),
+			vector3.NewVector3(1, 1, 0),
+			vector3.NewVector3(0, 1, 0),
+			vector3.NewVector3(0.5, 0.5, 0),
+		},
+		Tris: []int32{0, 1, 4, 1, 2, 4, 2, 3, 4, 3, 0, 4},
+	}
+	h, _ := NewHalfEdgeMesh(m)
+	utils.Equals(t, false, h.IsClosed())
+	utils.Equals(t, 1, h.EulerCharacteristic())
+	utils.Equals(t, false, h.IsBoundaryVertex(4))
+	utils.Equals(t, true, h.IsBoundaryVertex(0))
+	utils.Equals(t, []int32{0, 1, 2, 3}, sorted(h.VertexOneRing(4)))
+	// Boundary vertices get both of their boundary neighbours
+	utils.Equals(t, []int32{1, 3, 4}, sorted(h.VertexOneRing(0)))
+	utils.Equals(t, 2, len(h.VertexFaces(0)))
+	utils.Equals(t, 1, len(h.EdgeFaces(0, 1)))
+
+	loops := h.BoundaryLoops()
+	utils.Equals(t, 1, len(loops))
+	utils.Equals(t, []int32{0, 1, 2, 3}, loops[0])
+
+	// Removing the front face of the cube leaves a single hole
+	h, _ = NewHalfEdgeMesh(openCuboid())
+	utils.Equals(t, 1, len(h.BoundaryLoops()))
+	utils.Equals(t, 1, h.EulerCharacteristic())
+}
@@ -0,0 +1,263 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// hullFace is a triangle of the hull being built, with the points still outside of it
+type hullFace struct {
+	v       [3]int
+	normal  vector3.Vector3
+	offset  float64
+	outside []int
+	dead    bool
+}
+
+func (f *hullFace) distance(p vector3.Vector3) float64 {
+	return f.normal.Dot(p) - f.offset
+}
+
+// quickhull builds the convex hull of points with the Quickhull algorithm
+type quickhull struct {
+	points  []vector3.Vector3
+	faces   []*hullFace
+	edges   map[[2]int]int
+	epsilon float64
+}
+
+func (q *quickhull) addFace(a, b, c int) int {
+	pa, pb, pc := q.points[a], q.points[b], q.points[c]
+	n := pb.Minus(pa).Cross(pc.Minus(pa)).Normalize()
+	f := &hullFace{v: [3]int{a, b, c}, normal: n, offset: n.Dot(pa)}
+	i := len(q.faces)
+	q.faces = append(q.faces, f)
+	q.edges[[2]int{a, b}] = i
+	q.edges[[2]int{b, c}] = i
+	q.edges[[2]int{c, a}] = i
+	return i
+}
+
+// assign moves every point to the new face it is the furthest in front of, the others are inside the hull
+func (q *quickhull) assign(points []int, faces []int) {
+	for _, p := range points {
+		best, dist := -1, q.epsilon
+		for _, f := range faces {
+			if d := q.faces[f].distance(q.points[p]); d > dist {
+				best, dist = f, d
+			}
+		}
+		if best != -1 {
+			q.faces[best].outside = append(q.faces[best].outside, p)
+		}
+	}
+}
+
+// simplex finds 4 points spanning a tetrahedron, or fails if all the points are coplanar
+func (q *quickhull) simplex() ([4]int, bool) {
+	var s [4]int
+	// Extreme points on each axis, keep the pair furthest apart
+	var extremes []int
+	for axis := 0; axis < 3; axis++ {
+		lo, hi := 0, 0
+		for i, p := range q.points {
+			if component(p, axis) < component(q.points[lo], axis) {
+				lo = i
+			}
+			if component(p, axis) > component(q.points[hi], axis) {
+				hi = i
+			}
+		}
+		extremes = append(extremes, lo, hi)
+	}
+	dist := -1.
+	for i := 0; i < len(extremes); i += 2 {
+		if d := q.points[extremes[i]].Distance(q.points[extremes[i+1]]); d > dist {
+			s[0], s[1], dist = extremes[i], extremes[i+1], d
+		}
+	}
+	if dist <= q.epsilon {
+		return s, false
+	}
+	// Furthest from the line
+	a, b := q.points[s[0]], q.points[s[1]]
+	dir := b.Minus(a).Normalize()
+	dist = -1
+	for i, p := range q.points {
+		d := p.Minus(a).Cross(dir).Norm2()
+		if d > dist {
+			s[2], dist = i, d
+		}
+	}
+	if dist <= q.epsilon {
+		return s, false
+	}
+	// Furthest from the plane
+	c := q.points[s[2]]
+	n := b.Minus(a).Cross(c.Minus(a)).Normalize()
+	dist = -1
+	for i, p := range q.points {
+		if d := math.Abs(n.Dot(p.Minus(a))); d > dist {
+			s[3], dist = i, d
+		}
+	}
+	if dist <= q.epsilon {
+		return s, false
+	}
+	return s, true
+}
+
+func component(v vector3.Vector3, axis int) float64 {
+	switch axis {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	}
+	return v.Z
+}
+
+// build runs the algorithm, returning false if the points are degenerate or the hull breaks up on them
+func (q *quickhull) build() bool {
+	s, ok := q.simplex()
+	if !ok {
+		return false
+	}
+	a, b, c, d := s[0], s[1], s[2], s[3]
+	// Orient the base so that the apex is behind it
+	pa := q.points[a]
+	n := q.points[b].Minus(pa).Cross(q.points[c].Minus(pa))
+	if n.Dot(q.points[d].Minus(pa)) > 0 {
+		b, c = c, b
+	}
+	faces := []int{q.addFace(a, b, c), q.addFace(a, d, b), q.addFace(b, d, c), q.addFace(c, d, a)}
+	var rest []int
+	for i := range q.points {
+		if i != a && i != b && i != c && i != d {
+			rest = append(rest, i)
+		}
+	}
+	q.assign(rest, faces)
+
+	for i := 0; i < len(q.faces); i++ {
+		f := q.faces[i]
+		if f.dead || len(f.outside) == 0 {
+			continue
+		}
+		// Furthest point in front of the face
+		eye, dist := -1, -1.
+		for _, p := range f.outside {
+			if d := f.distance(q.points[p]); d > dist {
+				eye, dist = p, d
+			}
+		}
+		p := q.points[eye]
+
+		// Faces seen from the eye point, and the horizon edges around them
+		visible := []int{i}
+		seen := map[int]bool{i: true}
+		var horizon [][2]int
+		for k := 0; k < len(visible); k++ {
+			v := q.faces[visible[k]].v
+			for e := 0; e < 3; e++ {
+				from, to := v[e], v[(e+1)%3]
+				n, ok := q.edges[[2]int{to, from}]
+				if !ok {
+					// The hull lost its twin edge to rounding, walking on would go astray
+					return false
+				}
+				if seen[n] {
+					continue
+				}
+				if q.faces[n].distance(p) > q.epsilon {
+					seen[n] = true
+					visible = append(visible, n)
+				} else {
+					horizon = append(horizon, [2]int{from, to})
+				}
+			}
+		}
+		// A horizon edge may have been recorded before its neighbour turned out visible
+		var orphans []int
+		for _, v := range visible {
+			q.faces[v].dead = true
+			orphans = append(orphans, q.faces[v].outside...)
+			q.faces[v].outside = nil
+		}
+		var created []int
+		for _, e := range horizon {
+			n, ok := q.edges[[2]int{e[1], e[0]}]
+			if !ok {
+				return false
+			}
+			if seen[n] {
+				continue
+			}
+			created = append(created, q.addFace(e[0], e[1], eye))
+		}
+		var remaining []int
+		for _, o := range orphans {
+			if o != eye {
+				remaining = append(remaining, o)
+			}
+		}
+		q.assign(remaining, created)
+	}
+	return true
+}
+
+// NewMeshConvexHull returns the convex hull of the points as a closed mesh wound outward,
+// with per vertex normals. Duplicate points and points lying on the hull faces are dropped
+// It fails with ErrHullNonFinite if a point has a NaN or infinite coordinate
+func NewMeshConvexHull(points []vector3.Vector3) (*Mesh, error) {
+	var extent float64
+	for _, p := range points {
+		if !isFinite(&p) {
+			return nil, utils.ErrHullNonFinite
+		}
+		extent = math.Max(extent, math.Abs(p.X)+math.Abs(p.Y)+math.Abs(p.Z))
+	}
+	q := &quickhull{edges: make(map[[2]int]int), epsilon: 1e-10 * math.Max(extent, 1)}
+	// Drop exact duplicates up front
+	seen := make(map[[3]float64]bool)
+	for _, p := range points {
+		k := [3]float64{p.X, p.Y, p.Z}
+		if !seen[k] {
+			seen[k] = true
+			q.points = append(q.points, p)
+		}
+	}
+	if len(q.points) < 4 || !q.build() {
+		return nil, utils.ErrHullDegenerate
+	}
+	out := &Mesh{}
+	index := make(map[int]int32)
+	for _, f := range q.faces {
+		if f.dead {
+			continue
+		}
+		for _, v := range f.v {
+			i, ok := index[v]
+			if !ok {
+				i = int32(len(out.Vertices))
+				index[v] = i
+				p := q.points[v]
+				out.Vertices = append(out.Vertices, vector3.NewVector3(p.X, p.Y, p.Z))
+			}
+			out.Tris = append(out.Tris, i)
+		}
+	}
+	out.RecalculateNormals()
+	return out, nil
+}
+
+// ConvexHull returns the convex hull of the mesh vertices
+func (m *Mesh) ConvexHull() (*Mesh, error) {
+	points := make([]vector3.Vector3, len(m.Vertices))
+	for i, v := range m.Vertices {
+		points[i] = *v
+	}
+	return NewMeshConvexHull(points)
+}
@@ -0,0 +1,99 @@
+package volume
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// assertHullContains checks that every point is behind every face of the hull
+func assertHullContains(t *testing.T, hull *Mesh, points []vector3.Vector3) {
+	for i := 0; i < hull.triangleCount(); i++ {
+		a, b, c := hull.triangle(i)
+		n := b.Minus(a).Cross(c.Minus(a)).Normalize()
+		for _, p := range points {
+			utils.Equals(t, true, n.Dot(p.Minus(a)) < 1e-9)
+		}
+	}
+}
+
+func TestNewMeshConvexHull(t *testing.T) {
+	var points []vector3.Vector3
+	for _, v := range NewMeshSquareCuboid(1, true).Vertices {
+		// Duplicates
+		points = append(points, *v, *v)
+	}
+	rand.Seed(1337)
+	for i := 0; i < 100; i++ {
+		points = append(points, *vector3.NewVector3(rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5))
+	}
+	// Coplanar with the faces
+	points = append(points, *vector3.NewVector3(0, 0, 0.5), *vector3.NewVector3(0.5, 0.1, 0.2), *vector3.NewVector3(0, 0.5, 0.5))
+	hull, err := NewMeshConvexHull(points)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 8, len(hull.Vertices))
+	utils.Equals(t, 12, len(hull.Tris)/3)
+	utils.Equals(t, true, hull.Validate(1e-9).IsValid())
+	utils.Equals(t, true, almostEqual(1, hull.GetVolume()))
+	utils.Equals(t, 8, len(hull.Normals))
+	for i, v := range hull.Vertices {
+		// Outward normals point away from the center
+		utils.Equals(t, true, hull.Normals[i].Dot(*v) > 0)
+	}
+	assertHullContains(t, hull, points)
+}
+
+func TestMesh_ConvexHull(t *testing.T) {
+	sphere := testSphere(1, 12, 24)
+	hull, err := sphere.ConvexHull()
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, hull.Validate(1e-9).IsValid())
+	utils.Equals(t, len(sphere.Vertices), len(hull.Vertices))
+	utils.Equals(t, true, math.Abs(hull.GetVolume()-sphere.GetVolume()) < 1e-9)
+
+	// A concave mesh, the hull of an L shape fills the notch
+	l := NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(2, 1, 1)).
+		Union(translated(NewMeshSquareCuboid(1, true), *vector3.NewVector3(-0.5, 1, 0)))
+	hull, err = l.ConvexHull()
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, almostEqual(3.5, hull.GetVolume()))
+	var points []vector3.Vector3
+	for _, v := range l.Vertices {
+		points = append(points, *v)
+	}
+	assertHullContains(t, hull, points)
+}
+
+func TestNewMeshConvexHullRandom(t *testing.T) {
+	rand.Seed(42)
+	var points []vector3.Vector3
+	for i := 0; i < 2000; i++ {
+		p := vector3.NewVector3(rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64()).Normalize()
+		points = append(points, p.Times(rand.Float64()*2))
+	}
+	hull, err := NewMeshConvexHull(points)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, hull.Validate(0).IsValid())
+	h, _ := NewHalfEdgeMesh(hull)
+	utils.Equals(t, 2, h.EulerCharacteristic())
+	assertHullContains(t, hull, points)
+}
+
+func TestNewMeshConvexHullDegenerate(t *testing.T) {
+	_, err := NewMeshConvexHull(nil)
+	utils.Equals(t, utils.ErrHullDegenerate, err)
+	// All in the z = 0 plane
+	_, err = testGrid(3).ConvexHull()
+	utils.Equals(t, utils.ErrHullDegenerate, err)
+
+	tetrahedron := []vector3.Vector3{
+		*vector3.NewVector3Zero(), *vector3.NewVector3(1, 0, 0), *vector3.NewVector3(0, 1, 0), *vector3.NewVector3(0, 0, 1),
+	}
+	for _, p := range []vector3.Vector3{*vector3.NewVector3(math.NaN(), 0, 0), *vector3.NewVector3(0, math.Inf(-1), 0)} {
+		_, err = NewMeshConvexHull(append(tetrahedron, p))
+		utils.Equals(t, utils.ErrHullNonFinite, err)
+	}
+}
@@ -0,0 +1,508 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// ScalarField returns the value of a field at a point, the isosurface separates the points below the iso value
+// (inside) from the others (outside), like a signed distance
+type ScalarField func(p vector3.Vector3) float64
+
+// IsosurfaceOptions controls the extraction of an isosurface
+type IsosurfaceOptions struct {
+	// Iso is the value of the field on the surface
+	Iso float64
+	// DualContouring places one vertex per cell at the minimizer of the tangent planes of the surface,
+	// which keeps the sharp edges and corners marching cubes bevels
+	DualContouring bool
+}
+
+// ScalarGrid is a field sampled on a regular grid of Dims samples spanning Bounds, Values are stored x first
+type ScalarGrid struct {
+	Bounds Box
+	Dims   [3]int
+	Values []float64
+	// field is the sampled function if any, used for exact normals
+	field ScalarField
+}
+
+// NewScalarGrid samples the field on a grid of dims samples spanning the box, at least 2 along each axis
+func NewScalarGrid(f ScalarField, b Box, dims [3]int) *ScalarGrid {
+	for i := range dims {
+		dims[i] = maxInt(dims[i], 2)
+	}
+	g := &ScalarGrid{Bounds: b, Dims: dims, Values: make([]float64, dims[0]*dims[1]*dims[2]), field: f}
+	for k := 0; k < dims[2]; k++ {
+		for j := 0; j < dims[1]; j++ {
+			for i := 0; i < dims[0]; i++ {
+				g.Values[g.index(i, j, k)] = f(g.Position(i, j, k))
+			}
+		}
+	}
+	return g
+}
+
+func (g *ScalarGrid) index(i, j, k int) int {
+	return i + g.Dims[0]*(j+g.Dims[1]*k)
+}
+
+// step returns the distance between two samples along each axis
+func (g *ScalarGrid) step() vector3.Vector3 {
+	s := g.Bounds.GetSize()
+	return *vector3.NewVector3(s.X/float64(g.Dims[0]-1), s.Y/float64(g.Dims[1]-1), s.Z/float64(g.Dims[2]-1))
+}
+
+// Position returns the position of a sample
+func (g *ScalarGrid) Position(i, j, k int) vector3.Vector3 {
+	s := g.step()
+	return *vector3.NewVector3(
+		g.Bounds.Min.X+float64(i)*s.X,
+		g.Bounds.Min.Y+float64(j)*s.Y,
+		g.Bounds.Min.Z+float64(k)*s.Z,
+	)
+}
+
+// At returns the value of a sample
+func (g *ScalarGrid) At(i, j, k int) float64 {
+	return g.Values[g.index(i, j, k)]
+}
+
+// locate returns the cell holding a point clamped to the grid and the offsets of the point in it
+// A NaN coordinate is taken as the first sample along its axis
+func (g *ScalarGrid) locate(p vector3.Vector3) (cell [3]int, t [3]float64) {
+	s := g.step()
+	for axis := 0; axis < 3; axis++ {
+		u := (component(p, axis) - component(*g.Bounds.Min, axis)) / component(s, axis)
+		if math.IsNaN(u) {
+			u = 0
+		}
+		u = math.Min(math.Max(u, 0), float64(g.Dims[axis]-1))
+		cell[axis] = minInt(int(u), g.Dims[axis]-2)
+		t[axis] = u - float64(cell[axis])
+	}
+	return cell, t
+}
+
+// Sample returns the trilinear interpolation of the samples at a point, clamped to the grid
+func (g *ScalarGrid) Sample(p vector3.Vector3) float64 {
+	cell, t := g.locate(p)
+	var v float64
+	for c := 0; c < 8; c++ {
+		w := 1.
+		for axis := 0; axis < 3; axis++ {
+			if c>>uint(axis)&1 == 1 {
+				w *= t[axis]
+			} else {
+				w *= 1 - t[axis]
+			}
+		}
+		if w != 0 {
+			v += w * g.At(cell[0]+c&1, cell[1]+c>>1&1, cell[2]+c>>2&1)
+		}
+	}
+	return v
+}
+
+// Gradient returns the gradient of the trilinear interpolation of the samples at a point, clamped to the grid
+func (g *ScalarGrid) Gradient(p vector3.Vector3) vector3.Vector3 {
+	s := g.step()
+	cell, t := g.locate(p)
+	var d [3]float64
+	for c := 0; c < 8; c++ {
+		v := g.At(cell[0]+c&1, cell[1]+c>>1&1, cell[2]+c>>2&1)
+		for axis := 0; axis < 3; axis++ {
+			// Derivative of the weight along axis, the product of the weights along the other ones
+			w := 1 / component(s, axis)
+			if c>>uint(axis)&1 == 0 {
+				w = -w
+			}
+			for other := 0; other < 3; other++ {
+				if other == axis {
+					continue
+				}
+				if c>>uint(other)&1 == 1 {
+					w *= t[other]
+				} else {
+					w *= 1 - t[other]
+				}
+			}
+			d[axis] += w * v
+		}
+	}
+	return *vector3.NewVector3(d[0], d[1], d[2])
+}
+
+// gradient returns the normalized gradient of the field by central differences, or of the samples
+func (g *ScalarGrid) gradient(p vector3.Vector3) vector3.Vector3 {
+	f := g.field
+	if f == nil {
+		return g.Gradient(p).Normalize()
+	}
+	s := g.step()
+	h := math.Min(s.X, math.Min(s.Y, s.Z)) * 1e-3
+	n := *vector3.NewVector3(
+		f(*vector3.NewVector3(p.X+h, p.Y, p.Z))-f(*vector3.NewVector3(p.X-h, p.Y, p.Z)),
+		f(*vector3.NewVector3(p.X, p.Y+h, p.Z))-f(*vector3.NewVector3(p.X, p.Y-h, p.Z)),
+		f(*vector3.NewVector3(p.X, p.Y, p.Z+h))-f(*vector3.NewVector3(p.X, p.Y, p.Z-h)),
+	)
+	return n.Normalize()
+}
+
+// Corners of a cell are numbered by their offsets, bit 0 along x, bit 1 along y and bit 2 along z
+var cubeEdges [12][2]int
+
+// marchingCases lists the triangles of each of the 256 inside/outside configurations of a cell, as edge indices
+var marchingCases [256][][3]int
+
+func init() {
+	n := 0
+	for c := 0; c < 8; c++ {
+		for bit := 1; bit < 8; bit <<= 1 {
+			if c&bit == 0 {
+				cubeEdges[n] = [2]int{c, c | bit}
+				n++
+			}
+		}
+	}
+	for mask := range marchingCases {
+		marchingCases[mask] = marchingCase(mask)
+	}
+}
+
+// cubeEdge returns the index of the edge between two adjacent corners
+func cubeEdge(a, b int) int {
+	if a > b {
+		a, b = b, a
+	}
+	for i, e := range cubeEdges {
+		if e == [2]int{a, b} {
+			return i
+		}
+	}
+	return -1
+}
+
+// marchingCase triangulates a configuration, mask having the bits of the inside corners set
+// Rather than a hand written table, the crossed edges of each face are joined into segments, the segments
+// into loops and the loops are fanned. Ambiguous faces always separate their inside corners so that both
+// cells sharing a face agree and the surface is closed
+func marchingCase(mask int) [][3]int {
+	inside := func(c int) bool { return mask>>uint(c)&1 == 1 }
+	links := make(map[int][]int)
+	for axis := 0; axis < 3; axis++ {
+		for side := 0; side < 2; side++ {
+			u, v := 1<<uint((axis+1)%3), 1<<uint((axis+2)%3)
+			base := side << uint(axis)
+			face := [4]int{base, base | u, base | u | v, base | v}
+			var crossed []int
+			for i := 0; i < 4; i++ {
+				if inside(face[i]) != inside(face[(i+1)%4]) {
+					crossed = append(crossed, cubeEdge(face[i], face[(i+1)%4]))
+				}
+			}
+			var segments [][2]int
+			switch len(crossed) {
+			case 2:
+				segments = append(segments, [2]int{crossed[0], crossed[1]})
+			case 4:
+				for i := 0; i < 4; i++ {
+					if inside(face[i]) {
+						segments = append(segments, [2]int{cubeEdge(face[(i+3)%4], face[i]), cubeEdge(face[i], face[(i+1)%4])})
+					}
+				}
+			}
+			for _, s := range segments {
+				links[s[0]] = append(links[s[0]], s[1])
+				links[s[1]] = append(links[s[1]], s[0])
+			}
+		}
+	}
+
+	corner := func(c int) vector3.Vector3 {
+		return *vector3.NewVector3(float64(c&1), float64(c>>1&1), float64(c>>2&1))
+	}
+	var tris [][3]int
+	visited := make(map[int]bool)
+	for e := 0; e < 12; e++ {
+		if len(links[e]) == 0 || visited[e] {
+			continue
+		}
+		loop := []int{e}
+		visited[e] = true
+		for prev, cur := -1, e; ; {
+			next := links[cur][0]
+			if next == prev {
+				next = links[cur][1]
+			}
+			if next == e {
+				break
+			}
+			loop = append(loop, next)
+			visited[next] = true
+			prev, cur = cur, next
+		}
+		// Orient the loop so that its normal goes from the inside corners to the outside ones
+		var normal, outward vector3.Vector3
+		for i, a := range loop {
+			b := loop[(i+1)%len(loop)]
+			pa := corner(cubeEdges[a][0]).Plus(corner(cubeEdges[a][1]))
+			pb := corner(cubeEdges[b][0]).Plus(corner(cubeEdges[b][1]))
+			normal = normal.Plus(*pa.Cross(pb))
+			in, out := corner(cubeEdges[a][0]), corner(cubeEdges[a][1])
+			if !inside(cubeEdges[a][0]) {
+				in, out = out, in
+			}
+			outward = outward.Plus(out.Minus(in))
+		}
+		if normal.Dot(outward) < 0 {
+			for i, j := 0, len(loop)-1; i < j; i, j = i+1, j-1 {
+				loop[i], loop[j] = loop[j], loop[i]
+			}
+		}
+		for i := 1; i+1 < len(loop); i++ {
+			tris = append(tris, [3]int{loop[0], loop[i], loop[i+1]})
+		}
+	}
+	return tris
+}
+
+// cellMask returns the configuration of the cell whose lowest corner is the sample (i, j, k)
+func (g *ScalarGrid) cellMask(i, j, k int, iso float64) int {
+	mask := 0
+	for c := 0; c < 8; c++ {
+		if g.At(i+c&1, j+c>>1&1, k+c>>2&1) < iso {
+			mask |= 1 << uint(c)
+		}
+	}
+	return mask
+}
+
+// crossing returns where the surface crosses the edge of a cell
+func (g *ScalarGrid) crossing(i, j, k, edge int, iso float64) vector3.Vector3 {
+	a, b := cubeEdges[edge][0], cubeEdges[edge][1]
+	va, vb := g.At(i+a&1, j+a>>1&1, k+a>>2&1), g.At(i+b&1, j+b>>1&1, k+b>>2&1)
+	pa, pb := g.Position(i+a&1, j+a>>1&1, k+a>>2&1), g.Position(i+b&1, j+b>>1&1, k+b>>2&1)
+	t := 0.5
+	if va != vb {
+		t = (iso - va) / (vb - va)
+	}
+	return *pa.Lerp(&pb, t)
+}
+
+// Isosurface extracts the surface where the field equals the iso value, wound and with normals facing the
+// increasing values. The surface is closed unless it reaches the sides of the grid
+func (g *ScalarGrid) Isosurface(o IsosurfaceOptions) *Mesh {
+	if o.DualContouring {
+		return g.dualContour(o.Iso)
+	}
+	m := &Mesh{}
+	// Vertices are shared through the lowest sample and the axis of their grid edge
+	shared := make(map[[2]int]int32)
+	for k := 0; k+1 < g.Dims[2]; k++ {
+		for j := 0; j+1 < g.Dims[1]; j++ {
+			for i := 0; i+1 < g.Dims[0]; i++ {
+				tris := marchingCases[g.cellMask(i, j, k, o.Iso)]
+				for _, t := range tris {
+					for _, e := range t {
+						a, b := cubeEdges[e][0], cubeEdges[e][1]
+						key := [2]int{g.index(i+a&1, j+a>>1&1, k+a>>2&1), b ^ a}
+						v, ok := shared[key]
+						if !ok {
+							v = int32(len(m.Vertices))
+							shared[key] = v
+							p := g.crossing(i, j, k, e, o.Iso)
+							n := g.gradient(p)
+							m.Vertices = append(m.Vertices, &p)
+							m.Normals = append(m.Normals, &n)
+						}
+						m.Tris = append(m.Tris, v)
+					}
+				}
+			}
+		}
+	}
+	return m
+}
+
+// dualContour places a vertex in every cell crossed by the surface and joins the 4 cells around every
+// crossed grid edge with a quad
+func (g *ScalarGrid) dualContour(iso float64) *Mesh {
+	m := &Mesh{}
+	cells := make(map[int]int32)
+	for k := 0; k+1 < g.Dims[2]; k++ {
+		for j := 0; j+1 < g.Dims[1]; j++ {
+			for i := 0; i+1 < g.Dims[0]; i++ {
+				mask := g.cellMask(i, j, k, iso)
+				if mask == 0 || mask == 255 {
+					continue
+				}
+				p := g.cellVertex(i, j, k, mask, iso)
+				n := g.gradient(p)
+				cells[g.index(i, j, k)] = int32(len(m.Vertices))
+				m.Vertices = append(m.Vertices, &p)
+				m.Normals = append(m.Normals, &n)
+			}
+		}
+	}
+	for k := 0; k < g.Dims[2]; k++ {
+		for j := 0; j < g.Dims[1]; j++ {
+			for i := 0; i < g.Dims[0]; i++ {
+				s := [3]int{i, j, k}
+				for axis := 0; axis < 3; axis++ {
+					t := s
+					t[axis]++
+					if t[axis] >= g.Dims[axis] {
+						continue
+					}
+					in := g.At(i, j, k) < iso
+					if in == (g.At(t[0], t[1], t[2]) < iso) {
+						continue
+					}
+					// The 4 cells around the edge, counter clockwise around the axis
+					u, v := (axis+1)%3, (axis+2)%3
+					var quad [4]int32
+					complete := true
+					for q, d := range [4][2]int{{-1, -1}, {0, -1}, {0, 0}, {-1, 0}} {
+						c := s
+						c[u] += d[0]
+						c[v] += d[1]
+						if c[u] < 0 || c[v] < 0 || c[u]+1 >= g.Dims[u] || c[v]+1 >= g.Dims[v] {
+							complete = false
+							break
+						}
+						vertex, found := cells[g.index(c[0], c[1], c[2])]
+						if !found {
+							complete = false
+							break
+						}
+						quad[q] = vertex
+					}
+					if !complete {
+						continue
+					}
+					if !in {
+						quad[1], quad[3] = quad[3], quad[1]
+					}
+					m.Tris = append(m.Tris, quad[0], quad[1], quad[2], quad[0], quad[2], quad[3])
+				}
+			}
+		}
+	}
+	return m
+}
+
+// cellVertex minimizes the quadratic error to the tangent planes at the crossings of the cell edges,
+// falling back on their mean along the directions they don't constrain
+func (g *ScalarGrid) cellVertex(i, j, k, mask int, iso float64) vector3.Vector3 {
+	var points, normals []vector3.Vector3
+	var mean vector3.Vector3
+	for e, edge := range cubeEdges {
+		if (mask>>uint(edge[0])&1 == 1) == (mask>>uint(edge[1])&1 == 1) {
+			continue
+		}
+		p := g.crossing(i, j, k, e, iso)
+		points = append(points, p)
+		normals = append(normals, g.gradient(p))
+		mean = mean.Plus(p)
+	}
+	mean = mean.Times(1 / float64(len(points)))
+
+	// Solve AtA x = Atb relative to the mean with a truncated pseudo inverse
+	var ata [3][3]float64
+	var atb [3]float64
+	for n, p := range points {
+		normal := [3]float64{normals[n].X, normals[n].Y, normals[n].Z}
+		d := normals[n].Dot(p.Minus(mean))
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				ata[r][c] += normal[r] * normal[c]
+			}
+			atb[r] += normal[r] * d
+		}
+	}
+	values, vectors := symmetricEigen(ata)
+	largest := math.Max(values[0], math.Max(values[1], values[2]))
+	var x [3]float64
+	for e := 0; e < 3; e++ {
+		if values[e] <= 0.1*largest || values[e] <= 1e-12 {
+			continue
+		}
+		var proj float64
+		for r := 0; r < 3; r++ {
+			proj += vectors[r][e] * atb[r]
+		}
+		for r := 0; r < 3; r++ {
+			x[r] += vectors[r][e] * proj / values[e]
+		}
+	}
+	p := mean.Plus(*vector3.NewVector3(x[0], x[1], x[2]))
+	// Keep the vertex in its cell
+	lo, hi := g.Position(i, j, k), g.Position(i+1, j+1, k+1)
+	return vector3.Min(vector3.Max(p, lo), hi)
+}
+
+// symmetricEigen diagonalizes a symmetric matrix with Jacobi rotations, the eigenvectors are the columns
+func symmetricEigen(a [3][3]float64) ([3]float64, [3][3]float64) {
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for r := 0; r < 3; r++ {
+					arp, arq := a[r][p], a[r][q]
+					a[r][p], a[r][q] = c*arp-s*arq, s*arp+c*arq
+				}
+				for r := 0; r < 3; r++ {
+					apr, aqr := a[p][r], a[q][r]
+					a[p][r], a[q][r] = c*apr-s*aqr, s*apr+c*aqr
+				}
+				for r := 0; r < 3; r++ {
+					vrp, vrq := v[r][p], v[r][q]
+					v[r][p], v[r][q] = c*vrp-s*vrq, s*vrp+c*vrq
+				}
+			}
+		}
+	}
+	return [3]float64{a[0][0], a[1][1], a[2][2]}, v
+}
+
+// NewMeshIsosurface samples the field on a grid of dims samples spanning the box and extracts its isosurface
+func NewMeshIsosurface(f ScalarField, b Box, dims [3]int, o IsosurfaceOptions) *Mesh {
+	return NewScalarGrid(f, b, dims).Isosurface(o)
+}
+
+// ScalarGrid returns the occupancy of the voxels sampled at their centers, 0 when filled and 1 when empty,
+// with a margin of empty samples so that the isosurface at 0.5 is closed
+func (g *VoxelGrid) ScalarGrid() *ScalarGrid {
+	dims := [3]int{g.Dims[0] + 2, g.Dims[1] + 2, g.Dims[2] + 2}
+	min := g.Origin.Minus(*vector3.NewVector3(g.Size/2, g.Size/2, g.Size/2))
+	s := &ScalarGrid{
+		Bounds: *NewBoxMinMax(min.X, min.Y, min.Z,
+			min.X+float64(dims[0]-1)*g.Size, min.Y+float64(dims[1]-1)*g.Size, min.Z+float64(dims[2]-1)*g.Size),
+		Dims:   dims,
+		Values: make([]float64, dims[0]*dims[1]*dims[2]),
+	}
+	for i := range s.Values {
+		s.Values[i] = 1
+	}
+	for c := range g.cells {
+		i, j, k := vector3.MortonDecode(c)
+		s.Values[s.index(int(i)+1, int(j)+1, int(k)+1)] = 0
+	}
+	return s
+}
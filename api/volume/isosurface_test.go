@@ -0,0 +1,84 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func sphereField(p vector3.Vector3) float64 {
+	return p.Norm2() - 1
+}
+
+func cubeField(p vector3.Vector3) float64 {
+	return math.Max(math.Abs(p.X), math.Max(math.Abs(p.Y), math.Abs(p.Z))) - 0.5
+}
+
+func TestNewMeshIsosurface(t *testing.T) {
+	b := *NewBoxMinMax(-1.5, -1.5, -1.5, 1.5, 1.5, 1.5)
+	m := NewMeshIsosurface(sphereField, b, [3]int{25, 25, 25}, IsosurfaceOptions{})
+	utils.Equals(t, true, m.Validate(1e-9).IsValid())
+	utils.Equals(t, true, math.Abs(m.GetVolume()-4*math.Pi/3) < 0.03*4*math.Pi/3)
+	for i, v := range m.Vertices {
+		utils.Equals(t, true, math.Abs(v.Norm2()-1) < 0.02)
+		// Normals face outward
+		utils.Equals(t, true, m.Normals[i].Dot(*v) > 0.99)
+	}
+
+	// Iso value
+	m = NewMeshIsosurface(sphereField, b, [3]int{25, 25, 25}, IsosurfaceOptions{Iso: -0.5})
+	utils.Equals(t, true, math.Abs(m.GetVolume()-math.Pi/6) < 0.05*math.Pi/6)
+
+	// Nothing to extract
+	utils.Equals(t, 0, len(NewMeshIsosurface(sphereField, *NewBoxMinMax(2, 2, 2, 3, 3, 3), [3]int{4, 4, 4}, IsosurfaceOptions{}).Tris))
+}
+
+func TestNewMeshIsosurfaceDualContouring(t *testing.T) {
+	b := *NewBoxMinMax(-1, -1, -1, 1, 1, 1)
+	// Marching cubes bevels the edges of the cube, dual contouring keeps them
+	mc := NewMeshIsosurface(cubeField, b, [3]int{16, 16, 16}, IsosurfaceOptions{})
+	utils.Equals(t, true, mc.GetVolume() < 1-1e-3)
+	dc := NewMeshIsosurface(cubeField, b, [3]int{16, 16, 16}, IsosurfaceOptions{DualContouring: true})
+	utils.Equals(t, true, dc.Validate(1e-9).IsValid())
+	utils.Equals(t, true, math.Abs(dc.GetVolume()-1) < 1e-6)
+	corners := 0
+	for _, v := range dc.Vertices {
+		if math.Abs(math.Abs(v.X)-0.5) < 1e-6 && math.Abs(math.Abs(v.Y)-0.5) < 1e-6 && math.Abs(math.Abs(v.Z)-0.5) < 1e-6 {
+			corners++
+		}
+	}
+	utils.Equals(t, 8, corners)
+
+	s := NewMeshIsosurface(sphereField, *NewBoxMinMax(-1.5, -1.5, -1.5, 1.5, 1.5, 1.5), [3]int{20, 20, 20}, IsosurfaceOptions{DualContouring: true})
+	utils.Equals(t, true, s.Validate(1e-9).IsValid())
+	utils.Equals(t, len(s.Vertices), len(s.Normals))
+}
+
+func TestScalarGrid_Sample(t *testing.T) {
+	linear := func(p vector3.Vector3) float64 { return 2*p.X - p.Y + 3*p.Z }
+	g := NewScalarGrid(linear, *NewBoxMinMax(0, 0, 0, 1, 2, 3), [3]int{3, 4, 5})
+	utils.Equals(t, 0., g.At(0, 0, 0))
+	utils.Equals(t, 2.-2+9, g.At(2, 3, 4))
+	for _, p := range []vector3.Vector3{*vector3.NewVector3(0.3, 1.7, 2.2), *vector3.NewVector3(1, 2, 3), *vector3.NewVector3(0.5, 0, 1.5)} {
+		utils.Equals(t, true, math.Abs(linear(p)-g.Sample(p)) < 1e-12)
+	}
+	// Clamped
+	utils.Equals(t, true, math.Abs(g.Sample(*vector3.NewVector3(-1, 0, 0))) < 1e-12)
+	utils.Equals(t, true, g.Gradient(*vector3.NewVector3(0.3, 1.7, 2.2)).Minus(*vector3.NewVector3(2, -1, 3)).Norm2() < 1e-12)
+	// NaN is taken as the first sample, infinities as the edges
+	utils.Equals(t, true, math.Abs(g.Sample(*vector3.NewVector3(math.NaN(), 1, 1.5))-3.5) < 1e-12)
+	utils.Equals(t, true, math.Abs(g.Sample(*vector3.NewVector3(math.Inf(1), math.Inf(-1), 0))-2) < 1e-12)
+	utils.Equals(t, true, g.Gradient(*vector3.NewVector3(0.3, math.NaN(), math.Inf(-1))).Minus(*vector3.NewVector3(2, -1, 3)).Norm2() < 1e-12)
+}
+
+func TestVoxelGrid_ScalarGrid(t *testing.T) {
+	g := NewMeshSquareCuboid(1, true).Voxelize(4, VoxelSolid)
+	g.Set(0, 0, 0, false)
+	m := g.ScalarGrid().Isosurface(IsosurfaceOptions{Iso: 0.5})
+	utils.Equals(t, true, m.Validate(1e-9).IsValid())
+	utils.Equals(t, true, m.GetVolume() > 0.5 && m.GetVolume() < 1)
+	dc := g.ScalarGrid().Isosurface(IsosurfaceOptions{Iso: 0.5, DualContouring: true})
+	utils.Equals(t, true, dc.Validate(1e-9).IsValid())
+}
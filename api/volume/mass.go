@@ -0,0 +1,124 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// MassProperties holds the integral properties of a closed mesh of uniform density
+type MassProperties struct {
+	// Volume enclosed by the mesh, negative if the triangles are wound inward
+	Volume float64
+	// Area is the total surface area of the triangles
+	Area float64
+	// Mass is Volume times the density
+	Mass float64
+	// CenterOfMass of the solid, which is the centroid for a uniform density
+	CenterOfMass vector3.Vector3
+	// Inertia is the inertia tensor relative to the center of mass
+	Inertia [3][3]float64
+}
+
+// subexpressions computes the polynomial terms shared by the integrals of one axis of a triangle
+// See David Eberly, "Polyhedral Mass Properties (Revisited)"
+func subexpressions(w0, w1, w2 float64) (f1, f2, f3, g0, g1, g2 float64) {
+	temp0 := w0 + w1
+	f1 = temp0 + w2
+	temp1 := w0 * w0
+	temp2 := temp1 + w1*temp0
+	f2 = temp2 + w2*f1
+	f3 = w0*temp1 + w1*temp2 + w2*f2
+	g0 = f2 + w0*(f1+w0)
+	g1 = f2 + w1*(f1+w1)
+	g2 = f2 + w2*(f1+w2)
+	return
+}
+
+// GetMassProperties computes volume, area, center of mass and inertia tensor of a closed mesh
+// using the divergence theorem, the triangles must be wound counter-clockwise seen from outside
+func (m *Mesh) GetMassProperties(density float64) MassProperties {
+	// Integrals of 1, x, y, z, x², y², z², xy, yz, zx over the enclosed volume
+	var intg [10]float64
+	area := 0.
+	for i := 0; i < m.triangleCount(); i++ {
+		p0, p1, p2 := m.triangle(i)
+		e1 := p1.Minus(p0)
+		e2 := p2.Minus(p0)
+		d := e1.Cross(e2)
+		area += math.Sqrt(d.Dot(*d)) / 2
+
+		f1x, f2x, f3x, g0x, g1x, g2x := subexpressions(p0.X, p1.X, p2.X)
+		_, f2y, f3y, g0y, g1y, g2y := subexpressions(p0.Y, p1.Y, p2.Y)
+		_, f2z, f3z, g0z, g1z, g2z := subexpressions(p0.Z, p1.Z, p2.Z)
+
+		intg[0] += d.X * f1x
+		intg[1] += d.X * f2x
+		intg[2] += d.Y * f2y
+		intg[3] += d.Z * f2z
+		intg[4] += d.X * f3x
+		intg[5] += d.Y * f3y
+		intg[6] += d.Z * f3z
+		intg[7] += d.X * (p0.Y*g0x + p1.Y*g1x + p2.Y*g2x)
+		intg[8] += d.Y * (p0.Z*g0y + p1.Z*g1y + p2.Z*g2y)
+		intg[9] += d.Z * (p0.X*g0z + p1.X*g1z + p2.X*g2z)
+	}
+	intg[0] /= 6
+	intg[1] /= 24
+	intg[2] /= 24
+	intg[3] /= 24
+	intg[4] /= 60
+	intg[5] /= 60
+	intg[6] /= 60
+	intg[7] /= 120
+	intg[8] /= 120
+	intg[9] /= 120
+
+	p := MassProperties{Volume: intg[0], Area: area}
+	if intg[0] == 0 {
+		return p
+	}
+	c := vector3.NewVector3(intg[1]/intg[0], intg[2]/intg[0], intg[3]/intg[0])
+	p.CenterOfMass = *c
+	p.Mass = intg[0] * density
+
+	// Second moments about the origin, shifted to the center of mass
+	xx := (intg[5]+intg[6])*density - p.Mass*(c.Y*c.Y+c.Z*c.Z)
+	yy := (intg[4]+intg[6])*density - p.Mass*(c.Z*c.Z+c.X*c.X)
+	zz := (intg[4]+intg[5])*density - p.Mass*(c.X*c.X+c.Y*c.Y)
+	xy := -(intg[7]*density - p.Mass*c.X*c.Y)
+	yz := -(intg[8]*density - p.Mass*c.Y*c.Z)
+	zx := -(intg[9]*density - p.Mass*c.Z*c.X)
+	p.Inertia = [3][3]float64{
+		{xx, xy, zx},
+		{xy, yy, yz},
+		{zx, yz, zz},
+	}
+	return p
+}
+
+// GetVolume returns the volume enclosed by a closed mesh
+func (m *Mesh) GetVolume() float64 {
+	v := 0.
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := m.triangle(i)
+		v += a.Dot(*b.Cross(c))
+	}
+	return v / 6
+}
+
+// GetSurfaceArea returns the sum of the areas of the mesh triangles
+func (m *Mesh) GetSurfaceArea() float64 {
+	area := 0.
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := m.triangle(i)
+		n := b.Minus(a).Cross(c.Minus(a))
+		area += math.Sqrt(n.Dot(*n)) / 2
+	}
+	return area
+}
+
+// GetCenterOfMass returns the centroid of the solid enclosed by a closed mesh
+func (m *Mesh) GetCenterOfMass() vector3.Vector3 {
+	return m.GetMassProperties(1).CenterOfMass
+}
@@ -0,0 +1,72 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMesh_GetMassProperties(t *testing.T) {
+	m := NewMeshSquareCuboid(2, true)
+	p := m.GetMassProperties(3)
+	utils.Equals(t, true, almostEqual(8, p.Volume))
+	utils.Equals(t, true, almostEqual(24, p.Area))
+	utils.Equals(t, true, almostEqual(24, p.Mass))
+	utils.Equals(t, true, p.CenterOfMass.Equal(*vector3.NewVector3Zero()))
+	// Solid cuboid: I = m(b² + c²) / 12 on the diagonal, no products of inertia
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := 0.
+			if i == j {
+				want = 24. * (4 + 4) / 12
+			}
+			utils.Equals(t, true, almostEqual(want, p.Inertia[i][j]))
+		}
+	}
+
+	// Same cuboid translated, inertia is relative to the center of mass
+	m = NewMeshSquareCuboid(2, false)
+	p = m.GetMassProperties(3)
+	utils.Equals(t, true, almostEqual(1, p.CenterOfMass.X))
+	utils.Equals(t, true, almostEqual(1, p.CenterOfMass.Y))
+	utils.Equals(t, true, almostEqual(1, p.CenterOfMass.Z))
+	utils.Equals(t, true, almostEqual(16, p.Inertia[1][1]))
+	utils.Equals(t, true, almostEqual(0, p.Inertia[0][2]))
+}
+
+func TestMesh_GetMassPropertiesTetrahedron(t *testing.T) {
+	m := &Mesh{
+		Vertices: []*vector3.Vector3{
+			vector3.NewVector3(0, 0, 0),
+			vector3.NewVector3(1, 0, 0),
+			vector3.NewVector3(0, 1, 0),
+			vector3.NewVector3(0, 0, 1),
+		},
+		Tris: []int32{0, 2, 1, 0, 1, 3, 0, 3, 2, 1, 2, 3},
+	}
+	p := m.GetMassProperties(1)
+	utils.Equals(t, true, almostEqual(1./6, p.Volume))
+	utils.Equals(t, true, almostEqual(1.5+math.Sqrt(3)/2, p.Area))
+	utils.Equals(t, true, almostEqual(0.25, p.CenterOfMass.Y))
+	// Known values for the unit corner tetrahedron
+	utils.Equals(t, true, almostEqual(1./80, p.Inertia[0][0]))
+	utils.Equals(t, true, almostEqual(1./480, p.Inertia[0][1]))
+
+	utils.Equals(t, true, almostEqual(p.Volume, m.GetVolume()))
+	utils.Equals(t, true, almostEqual(p.Area, m.GetSurfaceArea()))
+	utils.Equals(t, true, m.GetCenterOfMass().Equal(p.CenterOfMass))
+}
+
+func TestMesh_GetVolumeInsideOut(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	for i := 0; i < len(m.Tris); i += 3 {
+		m.Tris[i+1], m.Tris[i+2] = m.Tris[i+2], m.Tris[i+1]
+	}
+	utils.Equals(t, true, almostEqual(-1, m.GetVolume()))
+}
@@ -80,8 +80,27 @@ func (m *Mesh) Fit(other Volume) bool {
 	return false
 }
 
-// Intersects create a new mesh averaged on 2 meshes
+// Intersects returns whether the mesh overlaps a mesh, a sphere or a capsule: their surfaces cross or, the
+// meshes being closed, one holds the other. A Capsule having no axis, it is the ball of diameter Width
+// around Center. Other volumes are false, boxes go through IntersectsBox
+// Meshes are tested by building both hierarchies, MeshBVH.Intersects reuses kept ones
 func (m *Mesh) Intersects(other Volume) bool {
+	switch o := other.(type) {
+	case *Mesh:
+		return NewMeshBVH(m).Intersects(NewMeshBVH(o))
+	case *Sphere:
+		center := vector3.NewVector3Zero()
+		if o.Center != nil {
+			center = o.Center
+		}
+		return m.IntersectsCapsule(*center, *center, o.Radius)
+	case *Capsule:
+		center := vector3.NewVector3Zero()
+		if o.Center != nil {
+			center = o.Center
+		}
+		return m.IntersectsCapsule(*center, *center, o.Width/2)
+	}
 	return false
 }
 
@@ -115,3 +134,31 @@ func (m *Mesh) Clone() *Mesh {
 		Uvs:      u,
 	}
 }
+
+// triangleCount returns the number of complete triangles in the mesh
+func (m *Mesh) triangleCount() int {
+	return len(m.Tris) / 3
+}
+
+// triangle returns the three vertices of the i-th triangle of the mesh
+func (m *Mesh) triangle(i int) (vector3.Vector3, vector3.Vector3, vector3.Vector3) {
+	return *m.Vertices[m.Tris[i*3]], *m.Vertices[m.Tris[i*3+1]], *m.Vertices[m.Tris[i*3+2]]
+}
+
+// RecalculateNormals sets one normal per vertex, the area weighted average of its triangle normals
+// In-place
+func (m *Mesh) RecalculateNormals() {
+	sums := make([]vector3.Vector3, len(m.Vertices))
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := m.triangle(i)
+		n := b.Minus(a).Cross(c.Minus(a))
+		for _, v := range m.Tris[i*3 : i*3+3] {
+			sums[v].Add(n)
+		}
+	}
+	m.Normals = make([]*vector3.Vector3, len(sums))
+	for i := range sums {
+		n := sums[i].Normalize()
+		m.Normals[i] = &n
+	}
+}
@@ -0,0 +1,193 @@
+package volume
+
+import (
+	"math"
+	"sort"
+)
+
+// point2 is a point of a polygon in the plane
+type point2 [2]float64
+
+func cross2(o, a, b point2) float64 {
+	return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+}
+
+// signedArea returns the area of the loop, positive if counter clockwise
+func signedArea(points []point2, loop []int) float64 {
+	var area float64
+	for i, a := range loop {
+		b := loop[(i+1)%len(loop)]
+		area += points[a][0]*points[b][1] - points[b][0]*points[a][1]
+	}
+	return area / 2
+}
+
+// insideLoop returns whether p is inside the loop, by crossing parity
+func insideLoop(points []point2, loop []int, p point2) bool {
+	inside := false
+	for i, a := range loop {
+		pa, pb := points[a], points[loop[(i+1)%len(loop)]]
+		if (pa[1] > p[1]) != (pb[1] > p[1]) && p[0] < pa[0]+(p[1]-pa[1])*(pb[0]-pa[0])/(pb[1]-pa[1]) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// triangulateLoops triangulates polygons given as loops of indices in points, counter clockwise around
+// their area and clockwise around their holes, returning counter clockwise triangles
+func triangulateLoops(points []point2, loops [][]int) [][3]int {
+	var outers, holes [][]int
+	for _, l := range loops {
+		if len(l) < 3 {
+			continue
+		}
+		if signedArea(points, l) > 0 {
+			outers = append(outers, l)
+		} else {
+			holes = append(holes, l)
+		}
+	}
+	// Each hole belongs to the smallest outer loop around it
+	owned := make([][][]int, len(outers))
+	for _, h := range holes {
+		best, area := -1, math.Inf(1)
+		for i, o := range outers {
+			if a := signedArea(points, o); a < area && insideLoop(points, o, points[h[0]]) {
+				best, area = i, a
+			}
+		}
+		if best != -1 {
+			owned[best] = append(owned[best], h)
+		}
+	}
+	var tris [][3]int
+	for i, o := range outers {
+		tris = append(tris, earClip(points, bridgeHoles(points, o, owned[i]))...)
+	}
+	return tris
+}
+
+// bridgeHoles merges the holes into the outer loop through cuts from their rightmost vertex to a visible
+// vertex of the outer loop (Eberly, Triangulation by Ear Clipping)
+func bridgeHoles(points []point2, outer []int, holes [][]int) []int {
+	rightmost := func(loop []int) int {
+		r := 0
+		for i, v := range loop {
+			if points[v][0] > points[loop[r]][0] {
+				r = i
+			}
+		}
+		return r
+	}
+	sort.Slice(holes, func(i, j int) bool {
+		return points[holes[i][rightmost(holes[i])]][0] > points[holes[j][rightmost(holes[j])]][0]
+	})
+	poly := append([]int(nil), outer...)
+	for _, h := range holes {
+		hm := rightmost(h)
+		m := points[h[hm]]
+		// Closest edge crossed by the ray going right from m
+		bridge, closest := -1, math.Inf(1)
+		for i, a := range poly {
+			pa, pb := points[a], points[poly[(i+1)%len(poly)]]
+			if (pa[1] > m[1]) == (pb[1] > m[1]) {
+				continue
+			}
+			x := pa[0] + (m[1]-pa[1])*(pb[0]-pa[0])/(pb[1]-pa[1])
+			if x < m[0] || x >= closest {
+				continue
+			}
+			closest = x
+			// The endpoint of the edge the furthest right is a candidate
+			bridge = i
+			if pb[0] > pa[0] {
+				bridge = (i + 1) % len(poly)
+			}
+		}
+		if bridge == -1 {
+			continue
+		}
+		// A reflex vertex inside the triangle between m, the crossing and the candidate hides it,
+		// the one with the smallest angle to the ray is visible
+		hit := point2{closest, m[1]}
+		candidate := points[poly[bridge]]
+		visible, best := bridge, math.Inf(1)
+		for i, v := range poly {
+			p := points[v]
+			prev, next := points[poly[(i+len(poly)-1)%len(poly)]], points[poly[(i+1)%len(poly)]]
+			if i == bridge || cross2(prev, p, next) >= 0 {
+				continue
+			}
+			c1, c2, c3 := cross2(m, hit, p), cross2(hit, candidate, p), cross2(candidate, m, p)
+			if !((c1 >= 0 && c2 >= 0 && c3 >= 0) || (c1 <= 0 && c2 <= 0 && c3 <= 0)) {
+				continue
+			}
+			if angle := math.Abs(math.Atan2(p[1]-m[1], p[0]-m[0])); angle < best {
+				visible, best = i, angle
+			}
+		}
+		bridge = visible
+		// outer ... bridge, hole from m around back to m, bridge ...
+		merged := append([]int(nil), poly[:bridge+1]...)
+		for k := 0; k <= len(h); k++ {
+			merged = append(merged, h[(hm+k)%len(h)])
+		}
+		merged = append(merged, poly[bridge:]...)
+		poly = merged
+	}
+	return poly
+}
+
+// earClip triangulates a simple counter clockwise polygon, possibly with the bridges of its holes
+// Every vertex ends up in a triangle, even when it lies on a straight part of the boundary
+func earClip(points []point2, poly []int) [][3]int {
+	var tris [][3]int
+	poly = append([]int(nil), poly...)
+	// Every pass clips a vertex, or stops on a polygon left without ear by rounding
+	for len(poly) > 3 {
+		clipped := false
+		for i := 0; i < len(poly); i++ {
+			n := len(poly)
+			a, b, c := poly[(i+n-1)%n], poly[i], poly[(i+1)%n]
+			pa, pb, pc := points[a], points[b], points[c]
+			if pb == pa || pb == pc {
+				// Repeated vertex, dropped without a triangle
+				poly = append(poly[:i], poly[i+1:]...)
+				clipped = true
+				break
+			}
+			// Collinear vertices are kept for a later triangle so that no edge of the mesh is split
+			if cross2(pa, pb, pc) <= 0 {
+				continue
+			}
+			ear := true
+			for _, v := range poly {
+				if v == a || v == b || v == c {
+					continue
+				}
+				p := points[v]
+				if p == pa || p == pb || p == pc {
+					continue
+				}
+				if cross2(pa, pb, p) >= 0 && cross2(pb, pc, p) >= 0 && cross2(pc, pa, p) >= 0 {
+					ear = false
+					break
+				}
+			}
+			if ear {
+				tris = append(tris, [3]int{a, b, c})
+				poly = append(poly[:i], poly[i+1:]...)
+				clipped = true
+				break
+			}
+		}
+		if !clipped {
+			break
+		}
+	}
+	if len(poly) == 3 && cross2(points[poly[0]], points[poly[1]], points[poly[2]]) > 0 {
+		tris = append(tris, [3]int{poly[0], poly[1], poly[2]})
+	}
+	return tris
+}
@@ -0,0 +1,490 @@
+package volume
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// SimplifyOptions controls Mesh.Simplify, at least one of TargetTriangles or MaxError should be set
+type SimplifyOptions struct {
+	// TargetTriangles stops the decimation once the mesh has at most this many triangles, 0 to ignore
+	TargetTriangles int
+	// MaxError forbids the collapses whose quadric error, a sum of squared distances to the
+	// original planes, is above this bound, 0 to ignore
+	MaxError float64
+	// PreserveBoundary forbids moving the vertices of open borders,
+	// otherwise they are only held back by penalty planes
+	PreserveBoundary bool
+	// PreserveUvSeams forbids moving the vertices split in several uvs at the same position, and
+	// removing the last triangle of any of their uvs
+	PreserveUvSeams bool
+}
+
+// boundaryWeight is the weight of the planes keeping open borders in place
+const boundaryWeight = 1000
+
+// quadric is a symmetric 4x4 matrix stored as its upper triangle:
+// a² ab ac ad b² bc bd c² cd d²
+type quadric [10]float64
+
+// planeQuadric returns the quadric measuring the squared distance to the plane ax + by + cz + d = 0
+func planeQuadric(a, b, c, d float64) quadric {
+	return quadric{a * a, a * b, a * c, a * d, b * b, b * c, b * d, c * c, c * d, d * d}
+}
+
+func (q quadric) plus(o quadric) quadric {
+	for i := range q {
+		q[i] += o[i]
+	}
+	return q
+}
+
+func (q quadric) times(w float64) quadric {
+	for i := range q {
+		q[i] *= w
+	}
+	return q
+}
+
+// evaluate returns the error of the quadric at v
+func (q quadric) evaluate(v vector3.Vector3) float64 {
+	x, y, z := v.X, v.Y, v.Z
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z +
+		q[9]
+}
+
+// optimal returns the position minimizing the quadric, if the system is well conditioned
+func (q quadric) optimal() (vector3.Vector3, bool) {
+	a := [3][3]float64{
+		{q[0], q[1], q[2]},
+		{q[1], q[4], q[5]},
+		{q[2], q[5], q[7]},
+	}
+	b := [3]float64{-q[3], -q[6], -q[8]}
+	det := a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+	scale := math.Abs(q[0]) + math.Abs(q[4]) + math.Abs(q[7])
+	if scale == 0 || math.Abs(det) < 1e-9*scale*scale*scale {
+		return vector3.Vector3{}, false
+	}
+	// Cramer's rule
+	var r [3]float64
+	for i := 0; i < 3; i++ {
+		m := a
+		for j := 0; j < 3; j++ {
+			m[j][i] = b[j]
+		}
+		r[i] = (m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+			m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+			m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])) / det
+	}
+	return *vector3.NewVector3(r[0], r[1], r[2]), true
+}
+
+// collapse is a candidate contraction of the vertex remove into the vertex keep, moved to target
+type collapse struct {
+	cost           float64
+	keep, remove   int32
+	target         vector3.Vector3
+	vKeep, vRemove int
+}
+
+type collapseHeap []collapse
+
+func (h collapseHeap) Len() int            { return len(h) }
+func (h collapseHeap) Less(i, j int) bool  { return h[i].cost < h[j].cost }
+func (h collapseHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *collapseHeap) Push(x interface{}) { *h = append(*h, x.(collapse)) }
+func (h *collapseHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// simplifier runs the edge collapses on vertices welded by position, while every triangle
+// corner remembers which original vertex it uses so that normals and uvs are carried over
+type simplifier struct {
+	src       *Mesh
+	pos       []vector3.Vector3
+	quadrics  []quadric
+	locked    []bool
+	seams     []bool
+	removed   []bool
+	version   []int
+	faces     [][]int
+	originals [][]int32
+	// uvSides maps the original vertices of the seams to the first one of the same uv
+	uvSides map[int32]int32
+	// tris holds welded vertices, corners the original ones
+	tris    []int32
+	corners []int32
+	dead    []bool
+	alive   int
+	queue   collapseHeap
+}
+
+func newSimplifier(m *Mesh, o SimplifyOptions) *simplifier {
+	weld := m.weldMap(0)
+	id := make([]int32, len(m.Vertices))
+	s := &simplifier{src: m}
+	for i, j := range weld {
+		if int(j) == i {
+			id[i] = int32(len(s.pos))
+			s.pos = append(s.pos, *m.Vertices[i])
+			s.originals = append(s.originals, nil)
+		} else {
+			id[i] = id[j]
+		}
+		s.originals[id[i]] = append(s.originals[id[i]], int32(i))
+	}
+	n := len(s.pos)
+	s.quadrics = make([]quadric, n)
+	s.locked = make([]bool, n)
+	s.removed = make([]bool, n)
+	s.version = make([]int, n)
+	s.faces = make([][]int, n)
+	s.corners = m.Tris
+	for _, v := range m.Tris {
+		s.tris = append(s.tris, id[v])
+	}
+	s.dead = make([]bool, len(s.tris)/3)
+	s.alive = len(s.dead)
+
+	for f := range s.dead {
+		normal, d := s.plane(f)
+		q := planeQuadric(normal.X, normal.Y, normal.Z, d)
+		for k := 0; k < 3; k++ {
+			v := s.tris[f*3+k]
+			s.quadrics[v] = s.quadrics[v].plus(q)
+			s.faces[v] = append(s.faces[v], f)
+		}
+	}
+
+	edges := newEdgeTable(s.tris, nil)
+	for _, key := range edges.keys {
+		uses := edges.uses[key]
+		switch {
+		case len(uses) > 2:
+			s.locked[key.a], s.locked[key.b] = true, true
+		case len(uses) == 1:
+			if o.PreserveBoundary {
+				s.locked[key.a], s.locked[key.b] = true, true
+				continue
+			}
+			// Plane through the border, perpendicular to its triangle
+			normal, _ := s.plane(uses[0].face)
+			a, b := s.pos[key.a], s.pos[key.b]
+			side := b.Minus(a).Cross(normal).Normalize()
+			q := planeQuadric(side.X, side.Y, side.Z, -side.Dot(a)).times(boundaryWeight)
+			s.quadrics[key.a] = s.quadrics[key.a].plus(q)
+			s.quadrics[key.b] = s.quadrics[key.b].plus(q)
+		}
+	}
+	if o.PreserveUvSeams && len(m.Uvs) == len(m.Vertices) {
+		s.seams = make([]bool, n)
+		s.uvSides = make(map[int32]int32)
+		for v, group := range s.originals {
+			for _, i := range group[1:] {
+				if !m.Uvs[i].Equal(*m.Uvs[group[0]]) {
+					s.locked[v], s.seams[v] = true, true
+				}
+			}
+			if !s.seams[v] {
+				continue
+			}
+			for _, i := range group {
+				for _, j := range group {
+					if m.Uvs[i].Equal(*m.Uvs[j]) {
+						s.uvSides[i] = j
+						break
+					}
+				}
+			}
+		}
+	}
+	for _, key := range edges.keys {
+		s.push(key.a, key.b)
+	}
+	return s
+}
+
+// plane returns the unit normal and offset of the plane of the face f
+func (s *simplifier) plane(f int) (vector3.Vector3, float64) {
+	a, b, c := s.pos[s.tris[f*3]], s.pos[s.tris[f*3+1]], s.pos[s.tris[f*3+2]]
+	n := b.Minus(a).Cross(c.Minus(a)).Normalize()
+	return n, -n.Dot(a)
+}
+
+// push queues the cheapest contraction of the edge between u and v
+func (s *simplifier) push(u, v int32) {
+	if s.locked[u] && s.locked[v] {
+		return
+	}
+	if s.locked[u] {
+		u, v = v, u
+	}
+	q := s.quadrics[u].plus(s.quadrics[v])
+	var candidates []vector3.Vector3
+	if s.locked[v] {
+		candidates = []vector3.Vector3{s.pos[v]}
+	} else if p, ok := q.optimal(); ok {
+		candidates = []vector3.Vector3{p}
+	} else {
+		candidates = []vector3.Vector3{s.pos[u], s.pos[v], *s.pos[u].Lerp(&s.pos[v], 0.5)}
+	}
+	best := collapse{cost: math.Inf(1), keep: v, remove: u, vKeep: s.version[v], vRemove: s.version[u]}
+	for _, p := range candidates {
+		if c := q.evaluate(p); c < best.cost {
+			best.cost, best.target = c, p
+		}
+	}
+	heap.Push(&s.queue, best)
+}
+
+// liveFaces returns the faces still using v
+func (s *simplifier) liveFaces(v int32) []int {
+	var out []int
+	for _, f := range s.faces[v] {
+		if !s.dead[f] && (s.tris[f*3] == v || s.tris[f*3+1] == v || s.tris[f*3+2] == v) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// neighbours returns the vertices sharing a live face with v
+func (s *simplifier) neighbours(v int32) map[int32]bool {
+	n := make(map[int32]bool)
+	for _, f := range s.liveFaces(v) {
+		for k := 0; k < 3; k++ {
+			if w := s.tris[f*3+k]; w != v {
+				n[w] = true
+			}
+		}
+	}
+	return n
+}
+
+// uvSidesLeft reports whether the seam vertex v still has a triangle on every side of its seam once the
+// faces gone are removed
+func (s *simplifier) uvSidesLeft(v int32, gone []int) bool {
+	sides := make(map[int32]bool)
+	for _, f := range s.liveFaces(v) {
+		for k := 0; k < 3; k++ {
+			if s.tris[f*3+k] == v {
+				sides[s.uvSides[s.corners[f*3+k]]] = false
+			}
+		}
+	}
+	for _, f := range s.liveFaces(v) {
+		removed := false
+		for _, g := range gone {
+			removed = removed || f == g
+		}
+		for k := 0; !removed && k < 3; k++ {
+			if s.tris[f*3+k] == v {
+				sides[s.uvSides[s.corners[f*3+k]]] = true
+			}
+		}
+	}
+	for _, left := range sides {
+		if !left {
+			return false
+		}
+	}
+	return true
+}
+
+// valid checks that contracting c keeps the surface manifold, doesn't fold any triangle over and, with
+// PreserveUvSeams, doesn't remove the last triangle of a side of a seam
+func (s *simplifier) valid(c collapse) bool {
+	var shared []int
+	for _, f := range s.liveFaces(c.remove) {
+		t := s.tris[f*3 : f*3+3]
+		if t[0] == c.keep || t[1] == c.keep || t[2] == c.keep {
+			shared = append(shared, f)
+		}
+	}
+	if len(shared) == 0 {
+		return false
+	}
+	// The vertices of the shared faces lose them, c.remove is never on a seam as seams are locked
+	if s.seams != nil {
+		for _, f := range shared {
+			for _, v := range s.tris[f*3 : f*3+3] {
+				if s.seams[v] && !s.uvSidesLeft(v, shared) {
+					return false
+				}
+			}
+		}
+	}
+	// Link condition, the two one-rings may only meet at the vertices opposite to the edge
+	nk := s.neighbours(c.keep)
+	common := 0
+	for w := range s.neighbours(c.remove) {
+		if nk[w] {
+			common++
+		}
+	}
+	if common != len(shared) {
+		return false
+	}
+	for _, v := range []int32{c.keep, c.remove} {
+		for _, f := range s.liveFaces(v) {
+			t := s.tris[f*3 : f*3+3]
+			if (t[0] == c.keep || t[1] == c.keep || t[2] == c.keep) &&
+				(t[0] == c.remove || t[1] == c.remove || t[2] == c.remove) {
+				continue
+			}
+			before, _ := s.plane(f)
+			var p [3]vector3.Vector3
+			for k := 0; k < 3; k++ {
+				p[k] = s.pos[t[k]]
+				if t[k] == v {
+					p[k] = c.target
+				}
+			}
+			after := p[1].Minus(p[0]).Cross(p[2].Minus(p[0]))
+			if after.Dot(before) <= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// pick returns the original vertex of the welded vertex v whose uv is the closest to the one of o
+func (s *simplifier) pick(v int32, o int32) int32 {
+	group := s.originals[v]
+	if len(group) == 1 || len(s.src.Uvs) != len(s.src.Vertices) {
+		return group[0]
+	}
+	best, dist := group[0], math.Inf(1)
+	for _, i := range group {
+		if d := s.src.Uvs[i].Distance(*s.src.Uvs[o]); d < dist {
+			best, dist = i, d
+		}
+	}
+	return best
+}
+
+// apply contracts c.remove into c.keep
+func (s *simplifier) apply(c collapse) {
+	var faces []int
+	for _, f := range s.liveFaces(c.remove) {
+		t := s.tris[f*3 : f*3+3]
+		if t[0] == c.keep || t[1] == c.keep || t[2] == c.keep {
+			s.dead[f] = true
+			s.alive--
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			if t[k] == c.remove {
+				t[k] = c.keep
+				s.corners[f*3+k] = s.pick(c.keep, s.corners[f*3+k])
+			}
+		}
+		faces = append(faces, f)
+	}
+	s.faces[c.keep] = append(s.liveFaces(c.keep), faces...)
+	s.faces[c.remove] = nil
+	s.removed[c.remove] = true
+	s.pos[c.keep] = c.target
+	s.quadrics[c.keep] = s.quadrics[c.keep].plus(s.quadrics[c.remove])
+	s.version[c.keep]++
+	// Sorted so that collapses of equal cost are queued, hence applied, in the same order on every run
+	var ring []int32
+	for w := range s.neighbours(c.keep) {
+		ring = append(ring, w)
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i] < ring[j] })
+	for _, w := range ring {
+		s.push(c.keep, w)
+	}
+}
+
+// run collapses edges until the options are satisfied or no valid collapse remains
+func (s *simplifier) run(o SimplifyOptions) {
+	for s.queue.Len() > 0 {
+		if o.TargetTriangles > 0 && s.alive <= o.TargetTriangles {
+			return
+		}
+		c := heap.Pop(&s.queue).(collapse)
+		if s.removed[c.keep] || s.removed[c.remove] ||
+			s.version[c.keep] != c.vKeep || s.version[c.remove] != c.vRemove {
+			continue
+		}
+		if o.MaxError > 0 && c.cost > o.MaxError {
+			return
+		}
+		if s.valid(c) {
+			s.apply(c)
+		}
+	}
+}
+
+// mesh returns the remaining triangles, dropping the vertices no longer used
+func (s *simplifier) mesh() *Mesh {
+	m := s.src
+	out := &Mesh{Center: m.Center}
+	hasNormals := len(m.Normals) == len(m.Vertices)
+	hasUvs := len(m.Uvs) == len(m.Vertices)
+	index := make(map[int32]int32)
+	for f, dead := range s.dead {
+		if dead {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			o := s.corners[f*3+k]
+			i, ok := index[o]
+			if !ok {
+				i = int32(len(out.Vertices))
+				index[o] = i
+				p := s.pos[s.tris[f*3+k]]
+				out.Vertices = append(out.Vertices, vector3.NewVector3(p.X, p.Y, p.Z))
+				if hasNormals {
+					out.Normals = append(out.Normals, m.Normals[o].Clone())
+				}
+				if hasUvs {
+					out.Uvs = append(out.Uvs, m.Uvs[o].Clone())
+				}
+			}
+			out.Tris = append(out.Tris, i)
+		}
+	}
+	return out
+}
+
+// Simplify decimates the mesh with Garland-Heckbert quadric error metrics, collapsing the
+// cheapest edges first until the triangle target or the error bound is reached
+// Invalid and degenerate triangles are dropped beforehand
+// Not in-place
+func (m *Mesh) Simplify(o SimplifyOptions) *Mesh {
+	s := newSimplifier(m.RemoveDegenerateTriangles(0), o)
+	if o.TargetTriangles > 0 || o.MaxError > 0 {
+		s.run(o)
+	}
+	return s.mesh()
+}
+
+// SimplifyLODs returns levels meshes of decreasing detail, the first one being m
+// Every level targets ratio times the triangles of the previous one
+func (m *Mesh) SimplifyLODs(levels int, ratio float64, o SimplifyOptions) []*Mesh {
+	lods := []*Mesh{m}
+	for len(lods) < levels {
+		previous := lods[len(lods)-1]
+		o.TargetTriangles = int(float64(previous.triangleCount()) * ratio)
+		if o.TargetTriangles < 1 {
+			break
+		}
+		lods = append(lods, previous.Simplify(o))
+	}
+	return lods
+}
@@ -0,0 +1,122 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// testGrid returns a flat unit square in the xy plane made of n*n quads, with planar uvs
+func testGrid(n int) *Mesh {
+	m := &Mesh{}
+	for j := 0; j <= n; j++ {
+		for i := 0; i <= n; i++ {
+			x, y := float64(i)/float64(n), float64(j)/float64(n)
+			m.Vertices = append(m.Vertices, vector3.NewVector3(x, y, 0))
+			m.Uvs = append(m.Uvs, vector3.NewVector3(x, y, 0))
+		}
+	}
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			a := int32(j*(n+1) + i)
+			b, c, d := a+1, a+int32(n)+2, a+int32(n)+1
+			m.Tris = append(m.Tris, a, b, c, a, c, d)
+		}
+	}
+	return m
+}
+
+// testSphere returns a closed uv sphere of the given radius centered on the origin
+func testSphere(radius float64, rings, segments int) *Mesh {
+	m := &Mesh{}
+	m.Vertices = append(m.Vertices, vector3.NewVector3(0, 0, radius))
+	for r := 1; r < rings; r++ {
+		phi := math.Pi * float64(r) / float64(rings)
+		for s := 0; s < segments; s++ {
+			theta := 2 * math.Pi * float64(s) / float64(segments)
+			m.Vertices = append(m.Vertices, vector3.NewVector3(
+				radius*math.Sin(phi)*math.Cos(theta),
+				radius*math.Sin(phi)*math.Sin(theta),
+				radius*math.Cos(phi),
+			))
+		}
+	}
+	m.Vertices = append(m.Vertices, vector3.NewVector3(0, 0, -radius))
+	south := int32(len(m.Vertices) - 1)
+	at := func(r, s int) int32 { return int32(1 + (r-1)*segments + s%segments) }
+	for s := 0; s < segments; s++ {
+		m.Tris = append(m.Tris, 0, at(1, s), at(1, s+1))
+		m.Tris = append(m.Tris, south, at(rings-1, s+1), at(rings-1, s))
+		for r := 1; r < rings-1; r++ {
+			m.Tris = append(m.Tris, at(r, s), at(r+1, s), at(r+1, s+1))
+			m.Tris = append(m.Tris, at(r, s), at(r+1, s+1), at(r, s+1))
+		}
+	}
+	return m
+}
+
+func TestMesh_SimplifyFlat(t *testing.T) {
+	m := testGrid(8)
+	s := m.Simplify(SimplifyOptions{TargetTriangles: 2})
+	utils.Equals(t, 2, len(s.Tris)/3)
+	utils.Equals(t, true, almostEqual(1, s.GetSurfaceArea()))
+	utils.Equals(t, len(s.Vertices), len(s.Uvs))
+	// Not in-place
+	utils.Equals(t, 128, len(m.Tris)/3)
+
+	// A flat grid can be decimated without any error
+	s = m.Simplify(SimplifyOptions{MaxError: 1e-12})
+	utils.Equals(t, 2, len(s.Tris)/3)
+}
+
+func TestMesh_SimplifyPreserveBoundary(t *testing.T) {
+	m := testGrid(4)
+	s := m.Simplify(SimplifyOptions{TargetTriangles: 1, PreserveBoundary: true})
+	// The 16 border vertices are kept, only the 9 inner ones can go
+	utils.Equals(t, 16, len(s.Vertices))
+	utils.Equals(t, 14, len(s.Tris)/3)
+	utils.Equals(t, true, almostEqual(1, s.GetSurfaceArea()))
+}
+
+func TestMesh_SimplifyUvSeams(t *testing.T) {
+	// Two grids side by side, sharing the positions of the seam but not the uvs
+	m := testGrid(4)
+	o := testGrid(4)
+	offset := int32(len(m.Vertices))
+	for i, v := range o.Vertices {
+		m.Vertices = append(m.Vertices, vector3.NewVector3(v.X+1, v.Y, 0))
+		m.Uvs = append(m.Uvs, vector3.NewVector3(o.Uvs[i].X, o.Uvs[i].Y, 1))
+	}
+	for _, i := range o.Tris {
+		m.Tris = append(m.Tris, i+offset)
+	}
+	s := m.Simplify(SimplifyOptions{TargetTriangles: 1, PreserveUvSeams: true})
+	seam := 0
+	for _, v := range s.Vertices {
+		if v.X == 1 {
+			seam++
+		}
+	}
+	// The seam is kept on both sides with all of its 5 vertices
+	utils.Equals(t, 10, seam)
+}
+
+func TestMesh_SimplifyClosed(t *testing.T) {
+	m := testSphere(1, 16, 32)
+	before := m.GetVolume()
+	s := m.Simplify(SimplifyOptions{TargetTriangles: 200})
+	utils.Equals(t, true, len(s.Tris)/3 <= 200)
+	r := s.Validate(0)
+	utils.Equals(t, true, r.IsValid())
+	utils.Equals(t, true, math.Abs(s.GetVolume()-before) < 0.1*before)
+}
+
+func TestMesh_SimplifyLODs(t *testing.T) {
+	lods := testSphere(1, 16, 32).SimplifyLODs(4, 0.5, SimplifyOptions{})
+	utils.Equals(t, 4, len(lods))
+	for i := 1; i < len(lods); i++ {
+		utils.Equals(t, true, len(lods[i].Tris) < len(lods[i-1].Tris))
+	}
+}
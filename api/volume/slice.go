@@ -0,0 +1,277 @@
+package volume
+
+import (
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// slicer cuts the triangles of a mesh by a plane, the vertices on the plane counting as above it
+type slicer struct {
+	m            *Mesh
+	normal       vector3.Vector3
+	distance     []float64
+	weld         []int32
+	hasNormals   bool
+	hasUvs       bool
+	above, below *Mesh
+	// Vertices of each part by original vertex, and by original edge for the points on the plane
+	aboveIndex, belowIndex map[edgeKey]int32
+	// Segments of the cross section between welded plane points, oriented counter clockwise
+	// around the solid seen from the normal side
+	segments map[edgeKey]edgeKey
+	points   map[edgeKey]vector3.Vector3
+}
+
+func newSlicer(m *Mesh, point, normal vector3.Vector3) *slicer {
+	s := &slicer{
+		m:          m,
+		normal:     normal.Normalize(),
+		weld:       m.weldMap(0),
+		hasNormals: len(m.Normals) == len(m.Vertices),
+		hasUvs:     len(m.Uvs) == len(m.Vertices),
+		above:      &Mesh{Center: m.Center},
+		below:      &Mesh{Center: m.Center},
+		aboveIndex: make(map[edgeKey]int32),
+		belowIndex: make(map[edgeKey]int32),
+		segments:   make(map[edgeKey]edgeKey),
+		points:     make(map[edgeKey]vector3.Vector3),
+	}
+	s.distance = make([]float64, len(m.Vertices))
+	for i, v := range m.Vertices {
+		s.distance[i] = s.normal.Dot(v.Minus(point))
+	}
+	return s
+}
+
+func (s *slicer) isAbove(v int32) bool {
+	return s.distance[v] >= 0
+}
+
+// crossing returns where the edge from a to b meets the plane and the interpolation factor from a,
+// computed from the lowest index so that the point is the same whatever the direction of the edge
+func (s *slicer) crossing(a, b int32) (vector3.Vector3, float64) {
+	if a > b {
+		p, t := s.crossing(b, a)
+		return p, 1 - t
+	}
+	t := s.distance[a] / (s.distance[a] - s.distance[b])
+	return *s.m.Vertices[a].Lerp(s.m.Vertices[b], t), t
+}
+
+// vertex returns the index in the part of the original vertex a, or of the point where the edge from a
+// to b meets the plane if they are on different sides
+func (s *slicer) vertex(part *Mesh, index map[edgeKey]int32, a, b int32) int32 {
+	key := edgeKey{a, a}
+	if a != b {
+		key = newEdgeKey(a, b)
+	}
+	if i, ok := index[key]; ok {
+		return i
+	}
+	i := int32(len(part.Vertices))
+	index[key] = i
+	p, t := *s.m.Vertices[a], 0.
+	if key.a != key.b {
+		p, t = s.crossing(a, b)
+	}
+	part.Vertices = append(part.Vertices, vector3.NewVector3(p.X, p.Y, p.Z))
+	if s.hasNormals {
+		n := s.m.Normals[a].Lerp(s.m.Normals[b], t).Normalize()
+		part.Normals = append(part.Normals, &n)
+	}
+	if s.hasUvs {
+		part.Uvs = append(part.Uvs, s.m.Uvs[a].Lerp(s.m.Uvs[b], t))
+	}
+	return i
+}
+
+// planeKey returns the welded key of the point where the edge from a, above, to b, below, meets the plane
+func (s *slicer) planeKey(a, b int32) edgeKey {
+	if s.distance[a] == 0 {
+		return edgeKey{s.weld[a], s.weld[a]}
+	}
+	return newEdgeKey(s.weld[a], s.weld[b])
+}
+
+// part adds the polygon of the triangle on one side of the plane to the mesh of that side
+func (s *slicer) part(t [3]int32, above bool) {
+	part, index := s.below, s.belowIndex
+	if above {
+		part, index = s.above, s.aboveIndex
+	}
+	var poly []int32
+	for k := 0; k < 3; k++ {
+		a, b := t[k], t[(k+1)%3]
+		if s.isAbove(a) == above {
+			poly = append(poly, s.vertex(part, index, a, a))
+		}
+		if s.isAbove(a) != s.isAbove(b) {
+			// The edges of a vertex lying on the plane meet it at the vertex itself, copied in the part below
+			switch {
+			case s.distance[a] == 0:
+				poly = append(poly, s.vertex(part, index, a, a))
+			case s.distance[b] == 0:
+				poly = append(poly, s.vertex(part, index, b, b))
+			default:
+				poly = append(poly, s.vertex(part, index, a, b))
+			}
+		}
+	}
+	for k := 1; k+1 < len(poly); k++ {
+		a, b, c := poly[0], poly[k], poly[k+1]
+		if a == b || b == c || c == a {
+			continue
+		}
+		part.Tris = append(part.Tris, a, b, c)
+	}
+}
+
+// cut splits every triangle and records the segments of the cross section
+func (s *slicer) cut() {
+	for f := 0; f < s.m.triangleCount(); f++ {
+		if s.m.isOutOfRange(f) {
+			continue
+		}
+		t := [3]int32{s.m.Tris[f*3], s.m.Tris[f*3+1], s.m.Tris[f*3+2]}
+		above := 0
+		for _, v := range t {
+			if s.isAbove(v) {
+				above++
+			}
+		}
+		if above == 3 || above == 0 {
+			s.part(t, above == 3)
+			continue
+		}
+		s.part(t, true)
+		s.part(t, false)
+		var enter, exit edgeKey
+		for k := 0; k < 3; k++ {
+			a, b := t[k], t[(k+1)%3]
+			switch {
+			case s.isAbove(a) && !s.isAbove(b):
+				exit = s.planeKey(a, b)
+				s.points[exit], _ = s.crossing(a, b)
+			case !s.isAbove(a) && s.isAbove(b):
+				enter = s.planeKey(b, a)
+				s.points[enter], _ = s.crossing(b, a)
+			}
+		}
+		if enter != exit {
+			s.segments[exit] = enter
+		}
+	}
+}
+
+// loops chains the segments into closed loops, dropping the chains left open by holes in the mesh
+func (s *slicer) loops() [][]vector3.Vector3 {
+	// Start from the smallest keys for reproducible output
+	var starts []edgeKey
+	for k := range s.segments {
+		starts = append(starts, k)
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		if starts[i].a != starts[j].a {
+			return starts[i].a < starts[j].a
+		}
+		return starts[i].b < starts[j].b
+	})
+	visited := make(map[edgeKey]bool)
+	var loops [][]vector3.Vector3
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		var loop []vector3.Vector3
+		closed := false
+		for k := start; !visited[k]; {
+			visited[k] = true
+			loop = append(loop, s.points[k])
+			next, ok := s.segments[k]
+			if !ok {
+				break
+			}
+			if next == start {
+				closed = true
+				break
+			}
+			k = next
+		}
+		if closed && len(loop) >= 3 {
+			loops = append(loops, loop)
+		}
+	}
+	return loops
+}
+
+// planeBasis returns two unit vectors spanning the plane of normal n, such that u x v = n
+func planeBasis(n vector3.Vector3) (u, v vector3.Vector3) {
+	axis := *vector3.NewVector3(1, 0, 0)
+	if n.X*n.X > 0.5 {
+		axis = *vector3.NewVector3(0, 1, 0)
+	}
+	u = axis.Cross(n).Normalize()
+	v = n.Cross(u).Normalize()
+	return u, v
+}
+
+// cap closes both parts with the triangulation of the loops of the cross section
+func (s *slicer) cap(loops [][]vector3.Vector3) {
+	u, v := planeBasis(s.normal)
+	var flat []point2
+	var indices [][]int
+	var positions []vector3.Vector3
+	for _, l := range loops {
+		var loop []int
+		for _, p := range l {
+			loop = append(loop, len(flat))
+			flat = append(flat, point2{u.Dot(p), v.Dot(p)})
+			positions = append(positions, p)
+		}
+		indices = append(indices, loop)
+	}
+	tris := triangulateLoops(flat, indices)
+	for _, side := range []struct {
+		part   *Mesh
+		normal vector3.Vector3
+		flip   bool
+	}{{s.below, s.normal, false}, {s.above, s.normal.Times(-1), true}} {
+		base := int32(len(side.part.Vertices))
+		for i, p := range positions {
+			side.part.Vertices = append(side.part.Vertices, vector3.NewVector3(p.X, p.Y, p.Z))
+			if s.hasNormals {
+				side.part.Normals = append(side.part.Normals, vector3.NewVector3(side.normal.X, side.normal.Y, side.normal.Z))
+			}
+			if s.hasUvs {
+				side.part.Uvs = append(side.part.Uvs, vector3.NewVector3(flat[i][0], flat[i][1], 0))
+			}
+		}
+		for _, t := range tris {
+			if side.flip {
+				t[1], t[2] = t[2], t[1]
+			}
+			side.part.Tris = append(side.part.Tris, base+int32(t[0]), base+int32(t[1]), base+int32(t[2]))
+		}
+	}
+}
+
+// Slice cuts the mesh by the plane through point, returning the part on the side the normal points to and the
+// other one. The cross section of a closed mesh is capped so that both parts are closed, the caps having their
+// own vertices with the plane normal and planar uvs. Vertices lying on the plane belong to the part above
+// Not in-place
+func (m *Mesh) Slice(point, normal vector3.Vector3) (above, below *Mesh) {
+	s := newSlicer(m, point, normal)
+	s.cut()
+	s.cap(s.loops())
+	return s.above, s.below
+}
+
+// CrossSection returns the closed polylines along which the plane through point crosses the mesh, counter
+// clockwise seen from the normal side around the solid and clockwise around its holes. The first point
+// is not repeated at the end
+func (m *Mesh) CrossSection(point, normal vector3.Vector3) [][]vector3.Vector3 {
+	s := newSlicer(m, point, normal)
+	s.cut()
+	return s.loops()
+}
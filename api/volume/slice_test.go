@@ -0,0 +1,118 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// loopArea returns the area of a loop lying in a plane, positive if counter clockwise seen from normal
+func loopArea(loop []vector3.Vector3, normal vector3.Vector3) float64 {
+	var sum vector3.Vector3
+	for i, p := range loop {
+		sum = sum.Plus(*p.Cross(loop[(i+1)%len(loop)]))
+	}
+	return sum.Dot(normal) / 2
+}
+
+func TestMesh_Slice(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	above, below := m.Slice(*vector3.NewVector3(0, 0, 0.25), *vector3.NewVector3(0, 0, 1))
+	utils.Equals(t, true, almostEqual(0.25, above.GetVolume()))
+	utils.Equals(t, true, almostEqual(0.75, below.GetVolume()))
+	// The caps have their own vertices, welded the parts are closed
+	utils.Equals(t, true, above.WeldVertices(1e-9).Validate(1e-9).IsValid())
+	utils.Equals(t, true, below.WeldVertices(1e-9).Validate(1e-9).IsValid())
+	for _, v := range above.Vertices {
+		utils.Equals(t, true, v.Z >= 0.25-1e-12)
+	}
+	for _, v := range below.Vertices {
+		utils.Equals(t, true, v.Z <= 0.25+1e-12)
+	}
+	// Not in-place
+	utils.Equals(t, true, almostEqual(1, m.GetVolume()))
+
+	// Tilted plane through a sphere
+	s := testSphere(1, 12, 16)
+	above, below = s.Slice(*vector3.NewVector3(0.1, 0.2, 0), *vector3.NewVector3(1, 1, 1))
+	utils.Equals(t, true, almostEqual(s.GetVolume(), above.GetVolume()+below.GetVolume()))
+	utils.Equals(t, true, above.WeldVertices(1e-9).Validate(1e-9).IsValid())
+	utils.Equals(t, true, below.WeldVertices(1e-9).Validate(1e-9).IsValid())
+
+	// A plane missing the mesh leaves it whole on one side
+	above, below = m.Slice(*vector3.NewVector3(0, 0, 2), *vector3.NewVector3(0, 0, 1))
+	utils.Equals(t, 0, len(above.Tris))
+	utils.Equals(t, len(m.Tris), len(below.Tris))
+}
+
+func TestMesh_SliceThroughVertices(t *testing.T) {
+	// The diagonal plane goes through four vertices of the cube
+	m := NewMeshSquareCuboid(1, true)
+	above, below := m.Slice(*vector3.NewVector3Zero(), *vector3.NewVector3(1, -1, 0))
+	utils.Equals(t, true, almostEqual(0.5, above.GetVolume()))
+	utils.Equals(t, true, almostEqual(0.5, below.GetVolume()))
+	utils.Equals(t, true, above.WeldVertices(1e-9).Validate(1e-9).IsValid())
+	utils.Equals(t, true, below.WeldVertices(1e-9).Validate(1e-9).IsValid())
+}
+
+func TestMesh_SliceAttributes(t *testing.T) {
+	m := unwrappedCuboid()
+	m.RecalculateNormals()
+	above, below := m.Slice(*vector3.NewVector3Zero(), *vector3.NewVector3(1, 0, 0))
+	for _, part := range []*Mesh{above, below} {
+		utils.Equals(t, len(part.Vertices), len(part.Normals))
+		utils.Equals(t, len(part.Vertices), len(part.Uvs))
+		for _, n := range part.Normals {
+			utils.Equals(t, true, almostEqual(1, n.Norm2()))
+		}
+	}
+	// The caps face away from their part
+	utils.Equals(t, *vector3.NewVector3(-1, 0, 0), *above.Normals[len(above.Normals)-1])
+	utils.Equals(t, *vector3.NewVector3(1, 0, 0), *below.Normals[len(below.Normals)-1])
+}
+
+func TestMesh_CrossSection(t *testing.T) {
+	normal := *vector3.NewVector3(0, 0, 1)
+	loops := NewMeshSquareCuboid(1, true).CrossSection(*vector3.NewVector3Zero(), normal)
+	utils.Equals(t, 1, len(loops))
+	utils.Equals(t, true, almostEqual(1, loopArea(loops[0], normal)))
+	for _, p := range loops[0] {
+		utils.Equals(t, true, almostEqual(0, p.Z))
+	}
+
+	// A ring has its outer boundary counter clockwise and its hole clockwise
+	ring := NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(3, 3, 1)).
+		Difference(NewMeshRectangularCuboid(*vector3.NewVector3Zero(), *vector3.NewVector3(1, 1, 2)))
+	loops = ring.CrossSection(*vector3.NewVector3Zero(), normal)
+	utils.Equals(t, 2, len(loops))
+	areas := []float64{loopArea(loops[0], normal), loopArea(loops[1], normal)}
+	utils.Equals(t, true, almostEqual(8, areas[0]+areas[1]))
+	utils.Equals(t, true, almostEqual(9, math.Max(areas[0], areas[1])))
+
+	// Its caps are pierced
+	above, below := ring.Slice(*vector3.NewVector3Zero(), normal)
+	utils.Equals(t, true, almostEqual(4, above.GetVolume()))
+	utils.Equals(t, true, almostEqual(4, below.GetVolume()))
+	utils.Equals(t, true, above.WeldVertices(1e-9).Validate(1e-9).IsValid())
+
+	// Open meshes only have closed loops
+	utils.Equals(t, 0, len(openCuboid().CrossSection(*vector3.NewVector3(0, 0.5, 0), *vector3.NewVector3(0, 1, 0))))
+}
+
+func TestTriangulateLoops(t *testing.T) {
+	points := []point2{
+		{0, 0}, {4, 0}, {4, 4}, {0, 4},
+		{1, 1}, {1, 3}, {3, 3}, {3, 1},
+	}
+	tris := triangulateLoops(points, [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}})
+	area := 0.
+	for _, tri := range tris {
+		a := cross2(points[tri[0]], points[tri[1]], points[tri[2]]) / 2
+		utils.Equals(t, true, a > 0)
+		area += a
+	}
+	utils.Equals(t, true, almostEqual(12, area))
+	utils.Equals(t, 8, len(tris))
+}
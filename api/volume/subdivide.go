@@ -0,0 +1,409 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// SubdivisionOptions controls the sharp features kept by Mesh.SubdivideLoop and Mesh.SubdivideCatmullClark
+// Open borders are always sharp, vertices with more than two sharp edges are corners and don't move
+type SubdivisionOptions struct {
+	// Creases lists sharp edges as pairs of vertex indices
+	Creases [][2]int32
+	// CreaseAngle marks as sharp the edges whose faces make an angle above it, in radians, 0 to disable
+	CreaseAngle float64
+}
+
+// subdivMesh is a polygon mesh where positions are shared by position vertices while uvs
+// belong to the attribute vertices used by the face corners, so that uv seams stay split
+type subdivMesh struct {
+	pos []vector3.Vector3
+	// weld maps every attribute vertex to its position vertex
+	weld  []int32
+	uvs   []vector3.Vector3
+	faces [][]int32
+	// sharp holds the crease edges, between position vertices
+	sharp map[edgeKey]bool
+}
+
+// subdivEdge is an edge between position vertices with the faces around it
+type subdivEdge struct {
+	key   edgeKey
+	faces []int
+}
+
+func newSubdivMesh(m *Mesh, o SubdivisionOptions) *subdivMesh {
+	weld := m.weldMap(0)
+	s := &subdivMesh{weld: make([]int32, len(m.Vertices)), sharp: make(map[edgeKey]bool)}
+	for i, j := range weld {
+		if int(j) == i {
+			s.weld[i] = int32(len(s.pos))
+			s.pos = append(s.pos, *m.Vertices[i])
+		} else {
+			s.weld[i] = s.weld[j]
+		}
+	}
+	if len(m.Uvs) == len(m.Vertices) {
+		for _, uv := range m.Uvs {
+			s.uvs = append(s.uvs, *uv)
+		}
+	}
+	for i := 0; i < m.triangleCount(); i++ {
+		s.faces = append(s.faces, []int32{m.Tris[i*3], m.Tris[i*3+1], m.Tris[i*3+2]})
+	}
+	for _, c := range o.Creases {
+		if c[0] >= 0 && c[1] >= 0 && int(c[0]) < len(s.weld) && int(c[1]) < len(s.weld) {
+			s.sharp[newEdgeKey(s.weld[c[0]], s.weld[c[1]])] = true
+		}
+	}
+	if o.CreaseAngle > 0 {
+		for _, e := range s.edges() {
+			if len(e.faces) != 2 {
+				continue
+			}
+			a, b := s.normal(e.faces[0]), s.normal(e.faces[1])
+			if math.Acos(math.Max(-1, math.Min(1, a.Dot(b)))) > o.CreaseAngle {
+				s.sharp[e.key] = true
+			}
+		}
+	}
+	return s
+}
+
+// normal returns the unit normal of a face, averaged over its corners for non-planar polygons
+func (s *subdivMesh) normal(f int) vector3.Vector3 {
+	face := s.faces[f]
+	n := vector3.NewVector3Zero()
+	for i := range face {
+		a := s.pos[s.weld[face[i]]]
+		b := s.pos[s.weld[face[(i+1)%len(face)]]]
+		// Newell's method
+		n.X += (a.Y - b.Y) * (a.Z + b.Z)
+		n.Y += (a.Z - b.Z) * (a.X + b.X)
+		n.Z += (a.X - b.X) * (a.Y + b.Y)
+	}
+	return n.Normalize()
+}
+
+// edges returns the position edges in order of first appearance
+func (s *subdivMesh) edges() []*subdivEdge {
+	index := make(map[edgeKey]*subdivEdge)
+	var edges []*subdivEdge
+	for f, face := range s.faces {
+		for i := range face {
+			key := newEdgeKey(s.weld[face[i]], s.weld[face[(i+1)%len(face)]])
+			e, ok := index[key]
+			if !ok {
+				e = &subdivEdge{key: key}
+				index[key] = e
+				edges = append(edges, e)
+			}
+			e.faces = append(e.faces, f)
+		}
+	}
+	return edges
+}
+
+// isSharp returns whether the edge is a crease, open borders and non-manifold edges included
+func (s *subdivMesh) isSharp(e *subdivEdge) bool {
+	return len(e.faces) != 2 || s.sharp[e.key]
+}
+
+// vertexRule gathers what the vertex rules of both schemes need about a position vertex
+type vertexRule struct {
+	neighbours []int32
+	creases    []int32
+}
+
+func (s *subdivMesh) vertexRules(edges []*subdivEdge) []vertexRule {
+	rules := make([]vertexRule, len(s.pos))
+	for _, e := range edges {
+		rules[e.key.a].neighbours = append(rules[e.key.a].neighbours, e.key.b)
+		rules[e.key.b].neighbours = append(rules[e.key.b].neighbours, e.key.a)
+		if s.isSharp(e) {
+			rules[e.key.a].creases = append(rules[e.key.a].creases, e.key.b)
+			rules[e.key.b].creases = append(rules[e.key.b].creases, e.key.a)
+		}
+	}
+	return rules
+}
+
+// creaseVertex applies the sharp rules, returning false if the vertex is smooth
+func (s *subdivMesh) creaseVertex(v int32, r vertexRule) (vector3.Vector3, bool) {
+	switch {
+	case len(r.creases) == 2:
+		p := s.pos[v].Times(6).Plus(s.pos[r.creases[0]]).Plus(s.pos[r.creases[1]])
+		return p.Times(1. / 8), true
+	case len(r.creases) > 2:
+		return s.pos[v], true
+	}
+	return vector3.Vector3{}, false
+}
+
+// refine builds the next level from the new positions and, for every face, the position vertices
+// of its edge points (indexed like the edges starting at each corner) and of its face point (-1 if none)
+// Attribute vertices are created alongside, uvs being interpolated linearly
+func (s *subdivMesh) refine(pos []vector3.Vector3, edgePoint map[edgeKey]int32, facePoint []int32) *subdivMesh {
+	n := &subdivMesh{pos: pos, sharp: make(map[edgeKey]bool)}
+	n.weld = append(n.weld, s.weld...)
+	n.uvs = append(n.uvs, s.uvs...)
+	attrEdge := make(map[edgeKey]int32)
+	edgeAttr := func(a, b int32) int32 {
+		key := newEdgeKey(a, b)
+		if i, ok := attrEdge[key]; ok {
+			return i
+		}
+		i := int32(len(n.weld))
+		attrEdge[key] = i
+		n.weld = append(n.weld, edgePoint[newEdgeKey(s.weld[a], s.weld[b])])
+		if s.uvs != nil {
+			n.uvs = append(n.uvs, *s.uvs[a].Lerp(&s.uvs[b], 0.5))
+		}
+		return i
+	}
+	for f, face := range s.faces {
+		edges := make([]int32, len(face))
+		for i := range face {
+			edges[i] = edgeAttr(face[i], face[(i+1)%len(face)])
+		}
+		if facePoint[f] == -1 {
+			// Loop, one triangle per corner and one in the middle
+			for i := range face {
+				n.faces = append(n.faces, []int32{face[i], edges[i], edges[(i+len(face)-1)%len(face)]})
+			}
+			n.faces = append(n.faces, edges)
+			continue
+		}
+		// Catmull-Clark, one quad per corner
+		center := int32(len(n.weld))
+		n.weld = append(n.weld, facePoint[f])
+		if s.uvs != nil {
+			uv := vector3.NewVector3Zero()
+			for _, v := range face {
+				uv.Add(&s.uvs[v])
+			}
+			uv.Divide(float64(len(face)))
+			n.uvs = append(n.uvs, *uv)
+		}
+		for i := range face {
+			n.faces = append(n.faces, []int32{face[i], edges[i], center, edges[(i+len(face)-1)%len(face)]})
+		}
+	}
+	for key := range s.sharp {
+		if e, ok := edgePoint[key]; ok {
+			n.sharp[newEdgeKey(key.a, e)] = true
+			n.sharp[newEdgeKey(e, key.b)] = true
+		}
+	}
+	return n
+}
+
+// loop runs one step of Loop subdivision, the faces must be triangles
+func (s *subdivMesh) loop() *subdivMesh {
+	edges := s.edges()
+	rules := s.vertexRules(edges)
+	pos := make([]vector3.Vector3, len(s.pos), len(s.pos)+len(edges))
+	for v := range s.pos {
+		r := rules[v]
+		if p, ok := s.creaseVertex(int32(v), r); ok {
+			pos[v] = p
+			continue
+		}
+		k := float64(len(r.neighbours))
+		if k == 0 {
+			pos[v] = s.pos[v]
+			continue
+		}
+		c := 3./8 + math.Cos(2*math.Pi/k)/4
+		beta := (5./8 - c*c) / k
+		p := s.pos[v].Times(1 - k*beta)
+		for _, w := range r.neighbours {
+			q := s.pos[w].Times(beta)
+			p.Add(&q)
+		}
+		pos[v] = p
+	}
+	edgePoint := make(map[edgeKey]int32, len(edges))
+	for _, e := range edges {
+		a, b := s.pos[e.key.a], s.pos[e.key.b]
+		p := *a.Lerp(&b, 0.5)
+		if !s.isSharp(e) {
+			p = a.Plus(b).Times(3. / 8)
+			for _, f := range e.faces {
+				for _, v := range s.faces[f] {
+					if w := s.weld[v]; w != e.key.a && w != e.key.b {
+						q := s.pos[w].Times(1. / 8)
+						p.Add(&q)
+					}
+				}
+			}
+		}
+		edgePoint[e.key] = int32(len(pos))
+		pos = append(pos, p)
+	}
+	facePoint := make([]int32, len(s.faces))
+	for f := range facePoint {
+		facePoint[f] = -1
+	}
+	return s.refine(pos, edgePoint, facePoint)
+}
+
+// catmullClark runs one step of Catmull-Clark subdivision on polygons of any size
+func (s *subdivMesh) catmullClark() *subdivMesh {
+	edges := s.edges()
+	rules := s.vertexRules(edges)
+	pos := make([]vector3.Vector3, len(s.pos), len(s.pos)+len(edges)+len(s.faces))
+
+	facePoint := make([]int32, len(s.faces))
+	faceCenter := make([]vector3.Vector3, len(s.faces))
+	for f, face := range s.faces {
+		c := vector3.NewVector3Zero()
+		for _, v := range face {
+			c.Add(&s.pos[s.weld[v]])
+		}
+		c.Divide(float64(len(face)))
+		faceCenter[f] = *c
+	}
+
+	edgePoint := make(map[edgeKey]int32, len(edges))
+	// Average of the face points and of the edge midpoints around every vertex
+	faceSum := make([]vector3.Vector3, len(s.pos))
+	faceCount := make([]int, len(s.pos))
+	midSum := make([]vector3.Vector3, len(s.pos))
+	for f, face := range s.faces {
+		for _, v := range face {
+			faceSum[s.weld[v]].Add(&faceCenter[f])
+			faceCount[s.weld[v]]++
+		}
+	}
+	for _, e := range edges {
+		a, b := s.pos[e.key.a], s.pos[e.key.b]
+		mid := *a.Lerp(&b, 0.5)
+		midSum[e.key.a].Add(&mid)
+		midSum[e.key.b].Add(&mid)
+		p := mid
+		if !s.isSharp(e) {
+			p = a.Plus(b).Plus(faceCenter[e.faces[0]]).Plus(faceCenter[e.faces[1]]).Times(1. / 4)
+		}
+		edgePoint[e.key] = int32(len(pos))
+		pos = append(pos, p)
+	}
+
+	for v := range s.pos {
+		r := rules[v]
+		if p, ok := s.creaseVertex(int32(v), r); ok {
+			pos[v] = p
+			continue
+		}
+		k := float64(len(r.neighbours))
+		if k == 0 || faceCount[v] == 0 {
+			pos[v] = s.pos[v]
+			continue
+		}
+		f := faceSum[v].Times(1 / float64(faceCount[v]))
+		m := midSum[v].Times(1 / k)
+		// (F + 2R + (n - 3)P) / n
+		pos[v] = f.Plus(m.Times(2)).Plus(s.pos[v].Times(k - 3)).Times(1 / k)
+	}
+
+	for f := range s.faces {
+		facePoint[f] = int32(len(pos))
+		pos = append(pos, faceCenter[f])
+	}
+	return s.refine(pos, edgePoint, facePoint)
+}
+
+// mesh triangulates the faces into a Mesh, with normals if the source had some
+func (s *subdivMesh) mesh(src *Mesh) *Mesh {
+	out := &Mesh{Center: src.Center}
+	for _, w := range s.weld {
+		p := s.pos[w]
+		out.Vertices = append(out.Vertices, vector3.NewVector3(p.X, p.Y, p.Z))
+	}
+	for i := range s.uvs {
+		out.Uvs = append(out.Uvs, s.uvs[i].Clone())
+	}
+	for _, face := range s.faces {
+		for i := 1; i+1 < len(face); i++ {
+			out.Tris = append(out.Tris, face[0], face[i], face[i+1])
+		}
+	}
+	if len(src.Normals) > 0 {
+		out.RecalculateNormals()
+	}
+	return out
+}
+
+// pairQuads merges the triangles two by two across the edge that is the longest of both,
+// which recovers the quads of meshes made of split quads
+func (s *subdivMesh) pairQuads() {
+	longest := func(f int) edgeKey {
+		face := s.faces[f]
+		best, length := edgeKey{}, -1.
+		for i := range face {
+			a, b := s.weld[face[i]], s.weld[face[(i+1)%3]]
+			if d := s.pos[a].Distance(s.pos[b]); d > length {
+				best, length = newEdgeKey(a, b), d
+			}
+		}
+		return best
+	}
+	merged := make([]bool, len(s.faces))
+	var faces [][]int32
+	for _, e := range s.edges() {
+		if len(e.faces) != 2 || s.sharp[e.key] {
+			continue
+		}
+		f, g := e.faces[0], e.faces[1]
+		if merged[f] || merged[g] || longest(f) != e.key || longest(g) != e.key {
+			continue
+		}
+		// Rotate f so that the shared edge comes last, then insert the opposite corner of g
+		a := s.faces[f]
+		for i := 0; i < 3; i++ {
+			if newEdgeKey(s.weld[a[2]], s.weld[a[0]]) == e.key {
+				break
+			}
+			a = []int32{a[1], a[2], a[0]}
+		}
+		for _, v := range s.faces[g] {
+			if w := s.weld[v]; w != e.key.a && w != e.key.b {
+				faces = append(faces, []int32{a[0], a[1], a[2], v})
+			}
+		}
+		merged[f], merged[g] = true, true
+	}
+	for f, face := range s.faces {
+		if !merged[f] {
+			faces = append(faces, face)
+		}
+	}
+	s.faces = faces
+}
+
+// SubdivideLoop smooths a triangle mesh with Loop subdivision, every iteration splitting each triangle in 4
+// Uvs are interpolated linearly and normals recalculated if the mesh had some
+// Not in-place
+func (m *Mesh) SubdivideLoop(iterations int, o SubdivisionOptions) *Mesh {
+	src := m.RemoveDegenerateTriangles(0)
+	s := newSubdivMesh(src, o)
+	for i := 0; i < iterations; i++ {
+		s = s.loop()
+	}
+	return s.mesh(m)
+}
+
+// SubdivideCatmullClark smooths a quad dominant mesh with Catmull-Clark subdivision
+// Pairs of triangles forming a quad are merged back first, the result is triangulated
+// Uvs are interpolated linearly and normals recalculated if the mesh had some
+// Not in-place
+func (m *Mesh) SubdivideCatmullClark(iterations int, o SubdivisionOptions) *Mesh {
+	src := m.RemoveDegenerateTriangles(0)
+	s := newSubdivMesh(src, o)
+	s.pairQuads()
+	for i := 0; i < iterations; i++ {
+		s = s.catmullClark()
+	}
+	return s.mesh(m)
+}
@@ -0,0 +1,102 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// unwrappedCuboid returns a unit cube with 4 vertices per face, each face mapped to the whole uv square
+func unwrappedCuboid() *Mesh {
+	c := NewMeshSquareCuboid(1, true)
+	quads := [][4]int32{{0, 3, 2, 1}, {2, 3, 4, 5}, {1, 2, 5, 6}, {0, 7, 4, 3}, {5, 4, 7, 6}, {0, 1, 6, 7}}
+	corners := [4][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	m := &Mesh{}
+	for _, quad := range quads {
+		base := int32(len(m.Vertices))
+		for i, v := range quad {
+			m.Vertices = append(m.Vertices, c.Vertices[v].Clone())
+			m.Uvs = append(m.Uvs, vector3.NewVector3(corners[i][0], corners[i][1], 0))
+		}
+		m.Tris = append(m.Tris, base, base+1, base+2, base, base+2, base+3)
+	}
+	return m
+}
+
+func TestMesh_SubdivideLoop(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	s := m.SubdivideLoop(1, SubdivisionOptions{})
+	utils.Equals(t, 48, len(s.Tris)/3)
+	utils.Equals(t, 26, len(s.Vertices))
+	utils.Equals(t, true, s.Validate(1e-9).IsValid())
+	v := s.GetVolume()
+	utils.Equals(t, true, v > 0.2 && v < 1)
+	// Not in-place
+	utils.Equals(t, 36, len(m.Tris))
+
+	s = m.SubdivideLoop(3, SubdivisionOptions{})
+	utils.Equals(t, 12*64, len(s.Tris)/3)
+	utils.Equals(t, true, s.GetVolume() < v)
+}
+
+func TestMesh_SubdivideLoopCreases(t *testing.T) {
+	// The cube edges are sharp and the corners don't move, so the cube keeps its shape
+	s := NewMeshSquareCuboid(1, true).SubdivideLoop(2, SubdivisionOptions{CreaseAngle: math.Pi / 4})
+	utils.Equals(t, true, almostEqual(1, s.GetVolume()))
+	utils.Equals(t, true, almostEqual(6, s.GetSurfaceArea()))
+
+	// Only one crease, its end points are darts and move
+	s = NewMeshSquareCuboid(1, true).SubdivideLoop(1, SubdivisionOptions{Creases: [][2]int32{{0, 1}}})
+	utils.Equals(t, true, s.GetVolume() < 1)
+}
+
+func TestMesh_SubdivideLoopUvs(t *testing.T) {
+	m := unwrappedCuboid()
+	s := m.SubdivideLoop(1, SubdivisionOptions{})
+	utils.Equals(t, len(s.Vertices), len(s.Uvs))
+	// Seams stay split in uvs but the surface is still closed
+	r := s.WeldVertices(1e-9).Validate(1e-9)
+	utils.Equals(t, true, r.IsValid())
+	utils.Equals(t, true, almostEqual(NewMeshSquareCuboid(1, true).SubdivideLoop(1, SubdivisionOptions{}).GetVolume(), s.GetVolume()))
+	// Uvs of edge points are midpoints
+	for _, uv := range s.Uvs {
+		utils.Equals(t, true, uv.X == 0 || uv.X == 0.5 || uv.X == 1)
+	}
+}
+
+func TestMesh_SubdivideLoopBoundary(t *testing.T) {
+	s := testGrid(2).SubdivideLoop(2, SubdivisionOptions{})
+	for _, v := range s.Vertices {
+		utils.Equals(t, 0., v.Z)
+	}
+	utils.Equals(t, len(s.Vertices), len(s.Uvs))
+	utils.Equals(t, true, s.GetSurfaceArea() <= 1)
+}
+
+func TestMesh_SubdivideCatmullClark(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	m.RecalculateNormals()
+	s := m.SubdivideCatmullClark(1, SubdivisionOptions{})
+	// 6 quads became 24, each one split into 2 triangles
+	utils.Equals(t, 48, len(s.Tris)/3)
+	utils.Equals(t, 26, len(s.Vertices))
+	utils.Equals(t, 26, len(s.Normals))
+	utils.Equals(t, true, s.Validate(1e-9).IsValid())
+	v := s.GetVolume()
+	utils.Equals(t, true, v > 0.3 && v < 1)
+
+	s = m.SubdivideCatmullClark(2, SubdivisionOptions{CreaseAngle: math.Pi / 4})
+	utils.Equals(t, 6*16*2, len(s.Tris)/3)
+	utils.Equals(t, true, almostEqual(1, s.GetVolume()))
+}
+
+func TestMesh_SubdivideCatmullClarkUvs(t *testing.T) {
+	s := unwrappedCuboid().SubdivideCatmullClark(2, SubdivisionOptions{})
+	utils.Equals(t, len(s.Vertices), len(s.Uvs))
+	utils.Equals(t, true, s.WeldVertices(1e-9).Validate(1e-9).IsValid())
+	for _, uv := range s.Uvs {
+		utils.Equals(t, true, uv.X >= 0 && uv.X <= 1 && uv.Y >= 0 && uv.Y <= 1)
+	}
+}
@@ -0,0 +1,173 @@
+package volume
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// The volumes are written for the fmt package as their name followed by their fields in parentheses, like
+// "Sphere((0, 0, 0), 1)", the vectors and numbers formatted with the verb, and %#v gives Go syntax
+// The generated String methods keep the protobuf text form
+
+// writeVector formats a vector field, nil ones as <nil>
+func writeVector(f fmt.State, verb rune, v *vector3.Vector3) {
+	if v == nil {
+		io.WriteString(f, "<nil>")
+		return
+	}
+	v.Format(f, verb)
+}
+
+// Format writes the sphere as "Sphere(center, radius)"
+func (s Sphere) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "volume.Sphere{Center:%#v, Radius:%#v}", s.Center, s.Radius)
+		return
+	}
+	io.WriteString(f, "Sphere(")
+	writeVector(f, verb, s.Center)
+	io.WriteString(f, ", ")
+	utils.WriteFloat(f, verb, s.Radius)
+	io.WriteString(f, ")")
+}
+
+// Format writes the capsule as "Capsule(center, width)"
+func (c Capsule) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "volume.Capsule{Center:%#v, Width:%#v}", c.Center, c.Width)
+		return
+	}
+	io.WriteString(f, "Capsule(")
+	writeVector(f, verb, c.Center)
+	io.WriteString(f, ", ")
+	utils.WriteFloat(f, verb, c.Width)
+	io.WriteString(f, ")")
+}
+
+// Format writes the box as "Box(min, max)"
+func (b Box) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "volume.Box{Min:%#v, Max:%#v}", b.Min, b.Max)
+		return
+	}
+	io.WriteString(f, "Box(")
+	writeVector(f, verb, b.Min)
+	io.WriteString(f, ", ")
+	writeVector(f, verb, b.Max)
+	io.WriteString(f, ")")
+}
+
+// Format writes a summary of the mesh as "Mesh(8 vertices, 12 triangles)", which is not parsed back
+func (m Mesh) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "volume.Mesh{Center:%#v, Vertices:%#v, Tris:%#v, Normals:%#v, Uvs:%#v}",
+			m.Center, m.Vertices, m.Tris, m.Normals, m.Uvs)
+		return
+	}
+	fmt.Fprintf(f, "Mesh(%d vertices, %d triangles)", len(m.Vertices), len(m.Tris)/3)
+}
+
+// Format writes the wrapped volume, or "AnyVolume()" when none is set
+func (a AnyVolume) Format(f fmt.State, verb rune) {
+	v, ok := a.Unwrap().(fmt.Formatter)
+	if !ok {
+		io.WriteString(f, "AnyVolume()")
+		return
+	}
+	v.Format(f, verb)
+}
+
+// parseFields returns the fields of "name(a, b)", checking there are n
+func parseFields(s, name string, n int) ([]string, error) {
+	rest, ok := utils.TrimName(s, name)
+	if !ok {
+		return nil, utils.ErrTextMalformed
+	}
+	fields, err := utils.SplitTuple(rest)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != n {
+		return nil, utils.ErrTextMalformed
+	}
+	return fields, nil
+}
+
+// ParseSphere parses a sphere written as "Sphere((x, y, z), radius)"
+func ParseSphere(s string) (*Sphere, error) {
+	fields, err := parseFields(s, "Sphere", 2)
+	if err != nil {
+		return nil, err
+	}
+	center, err := vector3.ParseVector3(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	radius, err := utils.ParseFloat(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	return &Sphere{Center: center, Radius: radius}, nil
+}
+
+// ParseCapsule parses a capsule written as "Capsule((x, y, z), width)"
+func ParseCapsule(s string) (*Capsule, error) {
+	fields, err := parseFields(s, "Capsule", 2)
+	if err != nil {
+		return nil, err
+	}
+	center, err := vector3.ParseVector3(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	width, err := utils.ParseFloat(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	return &Capsule{Center: center, Width: width}, nil
+}
+
+// ParseBox parses a box written as "Box((minX, minY, minZ), (maxX, maxY, maxZ))"
+func ParseBox(s string) (*Box, error) {
+	fields, err := parseFields(s, "Box", 2)
+	if err != nil {
+		return nil, err
+	}
+	min, err := vector3.ParseVector3(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	max, err := vector3.ParseVector3(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	return &Box{Min: min, Max: max}, nil
+}
+
+// ParseAnyVolume parses a sphere, capsule or box written by their Format method
+func ParseAnyVolume(s string) (*AnyVolume, error) {
+	var v interface{}
+	var err error
+	switch {
+	case hasName(s, "Sphere"):
+		v, err = ParseSphere(s)
+	case hasName(s, "Capsule"):
+		v, err = ParseCapsule(s)
+	case hasName(s, "Box"):
+		v, err = ParseBox(s)
+	default:
+		return nil, utils.ErrTextMalformed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewAnyVolume(v)
+}
+
+func hasName(s, name string) bool {
+	_, ok := utils.TrimName(s, name)
+	return ok
+}
@@ -0,0 +1,57 @@
+package volume
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestVolume_Format(t *testing.T) {
+	sphere := &Sphere{Center: vector3.NewVector3(1, 2, 3), Radius: 0.5}
+	box := NewBoxMinMax(-1, 0, 0, 1, 2.5, 3)
+	utils.Equals(t, "Sphere((1, 2, 3), 0.5)", fmt.Sprint(sphere))
+	utils.Equals(t, "Capsule((0, 1, 0), 2)", fmt.Sprint(Capsule{Center: vector3.NewVector3(0, 1, 0), Width: 2}))
+	utils.Equals(t, "Box((-1, 0, 0), (1, 2.5, 3))", fmt.Sprint(box))
+	utils.Equals(t, "Box((-1.0, 0.0, 0.0), (1.0, 2.5, 3.0))", fmt.Sprintf("%.1f", box))
+	utils.Equals(t, "Box(<nil>, <nil>)", fmt.Sprint(&Box{}))
+	utils.Equals(t, "volume.Sphere{Center:vector3.Vector3{X:1, Y:2, Z:3}, Radius:0.5}", fmt.Sprintf("%#v", sphere))
+	utils.Equals(t, "Mesh(8 vertices, 12 triangles)", fmt.Sprint(NewMeshSquareCuboid(1, true)))
+
+	a, _ := NewAnyVolume(sphere)
+	utils.Equals(t, "Sphere((1, 2, 3), 0.5)", fmt.Sprint(a))
+	utils.Equals(t, "AnyVolume()", fmt.Sprint(&AnyVolume{}))
+	// Volumes print compactly inside other values too
+	utils.Equals(t, "[Box((-1, 0, 0), (1, 2.5, 3)) Sphere((1, 2, 3), 0.5)]", fmt.Sprint([]interface{}{box, sphere}))
+}
+
+func TestParseVolume(t *testing.T) {
+	sphere, err := ParseSphere("Sphere((1, 2, 3), 0.5)")
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, sphere.Center.Equal(*vector3.NewVector3(1, 2, 3)))
+	utils.Equals(t, 0.5, sphere.Radius)
+
+	capsule, err := ParseCapsule(" Capsule( (0, 1, 0) , 2 ) ")
+	utils.Equals(t, nil, err)
+	utils.Equals(t, 2., capsule.Width)
+
+	box := NewBoxMinMax(-1, 0, 0, 1.0/3, 2.5, 3)
+	got, err := ParseBox(fmt.Sprint(box))
+	utils.Equals(t, nil, err)
+	utils.Equals(t, *box.Min, *got.Min)
+	utils.Equals(t, *box.Max, *got.Max)
+
+	for _, s := range []string{"Sphere((1, 2, 3), 0.5)", "Capsule((0, 1, 0), 2)", "Box((-1, 0, 0), (1, 2.5, 3))"} {
+		a, err := ParseAnyVolume(s)
+		utils.Equals(t, nil, err)
+		utils.Equals(t, s, fmt.Sprint(a))
+	}
+
+	for _, s := range []string{"Sphere((1, 2, 3))", "Sphere(1, 2)", "Box((0, 0, 0), 1)", "Cube((0, 0, 0), 1)", "Capsule((0, 0, 0), x)", "Mesh(8 vertices, 12 triangles)"} {
+		_, err := ParseAnyVolume(s)
+		utils.Equals(t, utils.ErrTextMalformed, err)
+	}
+	_, err = ParseBox("Sphere((1, 2, 3), 0.5)")
+	utils.Equals(t, utils.ErrTextMalformed, err)
+}
@@ -0,0 +1,238 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// ClosestPointOnTriangle returns the point of the triangle abc closest to p, from its Voronoi regions
+// (Ericson, Real-Time Collision Detection 5.1.5)
+func ClosestPointOnTriangle(p, a, b, c vector3.Vector3) vector3.Vector3 {
+	ab, ac, ap := b.Minus(a), c.Minus(a), p.Minus(a)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+	bp := p.Minus(b)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return a.Plus(ab.Times(d1 / (d1 - d3)))
+	}
+	cp := p.Minus(c)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return a.Plus(ac.Times(d2 / (d2 - d6)))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return b.Plus(c.Minus(b).Times((d4 - d3) / ((d4 - d3) + (d5 - d6))))
+	}
+	denom := va + vb + vc
+	if denom == 0 {
+		// Degenerate triangle, all the regions above failed by rounding
+		return a
+	}
+	return a.Plus(ab.Times(vb / denom)).Plus(ac.Times(vc / denom))
+}
+
+// triangleEpsilon is the distance under which a point is considered on the plane of a triangle
+const triangleEpsilon = 1e-12
+
+// planeSide returns the signed distances of the points to the plane through o with normal n,
+// snapping the ones too close to it to 0
+func planeSide(n, o vector3.Vector3, points [3]vector3.Vector3) [3]float64 {
+	var d [3]float64
+	scale := math.Sqrt(n.Norm())
+	for i, p := range points {
+		d[i] = n.Dot(p.Minus(o))
+		if math.Abs(d[i]) <= triangleEpsilon*scale {
+			d[i] = 0
+		}
+	}
+	return d
+}
+
+// planeCrossing returns the points where the triangle meets a plane, from its signed distances to it
+func planeCrossing(t [3]vector3.Vector3, d [3]float64) []vector3.Vector3 {
+	var points []vector3.Vector3
+	for i := 0; i < 3; i++ {
+		j := (i + 1) % 3
+		if d[i] == 0 {
+			points = append(points, t[i])
+		}
+		if d[i]*d[j] < 0 {
+			points = append(points, t[i].Plus(t[j].Minus(t[i]).Times(d[i]/(d[i]-d[j]))))
+		}
+	}
+	return points
+}
+
+// IntersectTriangles returns the segment where the triangles a and b cross, in the fashion of Möller's
+// interval overlap test. Start and End are equal when the triangles touch at a point. Coplanar triangles
+// return the two farthest points of their overlap
+func IntersectTriangles(a0, a1, a2, b0, b1, b2 vector3.Vector3) (start, end vector3.Vector3, ok bool) {
+	a, b := [3]vector3.Vector3{a0, a1, a2}, [3]vector3.Vector3{b0, b1, b2}
+	nb := b1.Minus(b0).Cross(b2.Minus(b0))
+	da := planeSide(*nb, b0, a)
+	if da[0]*da[1] > 0 && da[0]*da[2] > 0 {
+		return start, end, false
+	}
+	na := a1.Minus(a0).Cross(a2.Minus(a0))
+	db := planeSide(*na, a0, b)
+	if db[0]*db[1] > 0 && db[0]*db[2] > 0 {
+		return start, end, false
+	}
+	if da == [3]float64{} || db == [3]float64{} {
+		return intersectCoplanar(a, b, *na)
+	}
+	pa, pb := planeCrossing(a, da), planeCrossing(b, db)
+	if len(pa) == 0 || len(pb) == 0 {
+		return start, end, false
+	}
+	// Both triangles cross the line of the planes, they intersect where their intervals on it overlap
+	dir := na.Cross(*nb)
+	interval := func(points []vector3.Vector3) (lo, hi vector3.Vector3, tlo, thi float64) {
+		tlo, thi = math.Inf(1), math.Inf(-1)
+		for _, p := range points {
+			if t := dir.Dot(p); t < tlo {
+				lo, tlo = p, t
+			}
+			if t := dir.Dot(p); t > thi {
+				hi, thi = p, t
+			}
+		}
+		return lo, hi, tlo, thi
+	}
+	loA, hiA, tloA, thiA := interval(pa)
+	loB, hiB, tloB, thiB := interval(pb)
+	if thiA < tloB || thiB < tloA {
+		return start, end, false
+	}
+	start, end = loA, hiA
+	if tloB > tloA {
+		start = loB
+	}
+	if thiB < thiA {
+		end = hiB
+	}
+	return start, end, true
+}
+
+// intersectCoplanar tests triangles lying in the same plane of normal n, in 2d on the plane the normal
+// is the most aligned with
+func intersectCoplanar(a, b [3]vector3.Vector3, n vector3.Vector3) (start, end vector3.Vector3, ok bool) {
+	u, v := 0, 1
+	if ax, ay, az := math.Abs(n.X), math.Abs(n.Y), math.Abs(n.Z); ax >= ay && ax >= az {
+		u, v = 1, 2
+	} else if ay >= az {
+		u, v = 0, 2
+	}
+	flat := func(p vector3.Vector3) [2]float64 { return [2]float64{component(p, u), component(p, v)} }
+	cross := func(o, p, q [2]float64) float64 { return (p[0]-o[0])*(q[1]-o[1]) - (p[1]-o[1])*(q[0]-o[0]) }
+	inside := func(p [2]float64, t [3]vector3.Vector3) bool {
+		t0, t1, t2 := flat(t[0]), flat(t[1]), flat(t[2])
+		c0, c1, c2 := cross(t0, t1, p), cross(t1, t2, p), cross(t2, t0, p)
+		return (c0 >= 0 && c1 >= 0 && c2 >= 0) || (c0 <= 0 && c1 <= 0 && c2 <= 0)
+	}
+	var points []vector3.Vector3
+	for i := 0; i < 3; i++ {
+		if inside(flat(a[i]), b) {
+			points = append(points, a[i])
+		}
+		if inside(flat(b[i]), a) {
+			points = append(points, b[i])
+		}
+		for j := 0; j < 3; j++ {
+			p, q := a[i], a[(i+1)%3]
+			r, s := b[j], b[(j+1)%3]
+			fp, fq, fr, fs := flat(p), flat(q), flat(r), flat(s)
+			denom := cross([2]float64{}, [2]float64{fq[0] - fp[0], fq[1] - fp[1]}, [2]float64{fs[0] - fr[0], fs[1] - fr[1]})
+			if denom == 0 {
+				continue
+			}
+			diff := [2]float64{fr[0] - fp[0], fr[1] - fp[1]}
+			t := cross([2]float64{}, diff, [2]float64{fs[0] - fr[0], fs[1] - fr[1]}) / denom
+			w := cross([2]float64{}, diff, [2]float64{fq[0] - fp[0], fq[1] - fp[1]}) / denom
+			if t >= 0 && t <= 1 && w >= 0 && w <= 1 {
+				points = append(points, p.Plus(q.Minus(p).Times(t)))
+			}
+		}
+	}
+	if len(points) == 0 {
+		return start, end, false
+	}
+	start, end = points[0], points[0]
+	far := -1.
+	for i := range points {
+		for j := i; j < len(points); j++ {
+			if d := points[i].Distance(points[j]); d > far {
+				start, end, far = points[i], points[j], d
+			}
+		}
+	}
+	return start, end, true
+}
+
+// TriangleIntersectsBox returns whether the triangle abc overlaps the box, touching included
+func TriangleIntersectsBox(a, b, c vector3.Vector3, box Box) bool {
+	return triangleBoxOverlap(box.GetCenter(), box.Max.Minus(*box.Min).Times(0.5), a, b, c)
+}
+
+// clamp01 returns x clamped to [0, 1]
+func clamp01(x float64) float64 {
+	return math.Min(math.Max(x, 0), 1)
+}
+
+// closestSegmentPoints returns the closest points of the segments p1q1 and p2q2
+// (Ericson, Real-Time Collision Detection 5.1.9)
+func closestSegmentPoints(p1, q1, p2, q2 vector3.Vector3) (vector3.Vector3, vector3.Vector3) {
+	const epsilon = 1e-12
+	d1, d2, r := q1.Minus(p1), q2.Minus(p2), p1.Minus(p2)
+	a, e, f := d1.Dot(d1), d2.Dot(d2), d2.Dot(r)
+	var s, t float64
+	switch {
+	case a <= epsilon && e <= epsilon:
+		return p1, p2
+	case a <= epsilon:
+		t = clamp01(f / e)
+	case e <= epsilon:
+		s = clamp01(-d1.Dot(r) / a)
+	default:
+		b, c := d1.Dot(d2), d1.Dot(r)
+		// Parallel segments have no single closest pair, any s works
+		if denom := a*e - b*b; denom != 0 {
+			s = clamp01((b*f - c*e) / denom)
+		}
+		t = (b*s + f) / e
+		if t < 0 {
+			t, s = 0, clamp01(-c/a)
+		} else if t > 1 {
+			t, s = 1, clamp01((b-c)/a)
+		}
+	}
+	return p1.Plus(d1.Times(s)), p2.Plus(d2.Times(t))
+}
+
+// SegmentTriangleDistance returns the distance between the segment pq and the triangle abc, 0 if the
+// segment goes through it
+func SegmentTriangleDistance(p, q, a, b, c vector3.Vector3) float64 {
+	if d, ok := rayTriangle(p, q.Minus(p), &bvhTriangle{a: a, b: b, c: c}); ok && d <= 1 {
+		return 0
+	}
+	// Otherwise the closest points are an end of the segment or on an edge of the triangle
+	best := math.Min(p.Distance(ClosestPointOnTriangle(p, a, b, c)), q.Distance(ClosestPointOnTriangle(q, a, b, c)))
+	for _, edge := range [3][2]vector3.Vector3{{a, b}, {b, c}, {c, a}} {
+		s, t := closestSegmentPoints(p, q, edge[0], edge[1])
+		best = math.Min(best, s.Distance(t))
+	}
+	return best
+}
@@ -0,0 +1,128 @@
+package volume
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestClosestPointOnTriangle(t *testing.T) {
+	a, b, c := *vector3.NewVector3(0, 0, 0), *vector3.NewVector3(2, 0, 0), *vector3.NewVector3(0, 2, 0)
+	tests := []struct {
+		p, want vector3.Vector3
+	}{
+		// Face
+		{*vector3.NewVector3(0.5, 0.5, 3), *vector3.NewVector3(0.5, 0.5, 0)},
+		// Vertices
+		{*vector3.NewVector3(-1, -1, 1), a},
+		{*vector3.NewVector3(3, -1, 0), b},
+		{*vector3.NewVector3(-0.5, 4, 0), c},
+		// Edges
+		{*vector3.NewVector3(1, -1, 1), *vector3.NewVector3(1, 0, 0)},
+		{*vector3.NewVector3(-1, 1, 0), *vector3.NewVector3(0, 1, 0)},
+		{*vector3.NewVector3(2, 2, -1), *vector3.NewVector3(1, 1, 0)},
+	}
+	for _, tt := range tests {
+		got := ClosestPointOnTriangle(tt.p, a, b, c)
+		utils.Equals(t, true, got.Distance(tt.want) < 1e-12)
+	}
+	// Degenerate triangle
+	got := ClosestPointOnTriangle(*vector3.NewVector3(1, 1, 0), a, b, b)
+	utils.Equals(t, true, got.Distance(*vector3.NewVector3(1, 0, 0)) < 1e-12)
+}
+
+func TestIntersectTriangles(t *testing.T) {
+	v := vector3.NewVector3
+	a0, a1, a2 := *v(0, 0, 0), *v(2, 0, 0), *v(0, 2, 0)
+
+	// Crossing through the middle
+	start, end, ok := IntersectTriangles(a0, a1, a2, *v(0.5, 0.5, -1), *v(0.5, 0.5, 1), *v(3, 0.5, 0))
+	utils.Equals(t, true, ok)
+	utils.Equals(t, true, start.Distance(*v(0.5, 0.5, 0)) < 1e-12 || end.Distance(*v(0.5, 0.5, 0)) < 1e-12)
+	utils.Equals(t, true, almostEqual(1, start.Distance(end)))
+
+	// Above the plane
+	_, _, ok = IntersectTriangles(a0, a1, a2, *v(0, 0, 1), *v(1, 0, 1), *v(0, 1, 2))
+	utils.Equals(t, false, ok)
+	// Crossing the plane but not the triangle
+	_, _, ok = IntersectTriangles(a0, a1, a2, *v(3, 3, -1), *v(3, 3, 1), *v(4, 3, 0))
+	utils.Equals(t, false, ok)
+
+	// Touching at a vertex
+	start, end, ok = IntersectTriangles(a0, a1, a2, *v(0.5, 0.5, 0), *v(0.5, 1, 1), *v(1, 0.5, 1))
+	utils.Equals(t, true, ok)
+	utils.Equals(t, true, start.Distance(*v(0.5, 0.5, 0)) < 1e-12 && end.Distance(start) < 1e-12)
+
+	// Coplanar, overlapping or not
+	start, end, ok = IntersectTriangles(a0, a1, a2, *v(1, 1, 0), *v(-1, 1, 0), *v(1, -1, 0))
+	utils.Equals(t, true, ok)
+	utils.Equals(t, true, start.Distance(end) > 0)
+	_, _, ok = IntersectTriangles(a0, a1, a2, *v(3, 3, 0), *v(4, 3, 0), *v(3, 4, 0))
+	utils.Equals(t, false, ok)
+	// One inside the other
+	_, _, ok = IntersectTriangles(a0, a1, a2, *v(0.1, 0.1, 0), *v(0.5, 0.1, 0), *v(0.1, 0.5, 0))
+	utils.Equals(t, true, ok)
+}
+
+func TestTriangleIntersectsBox(t *testing.T) {
+	v := vector3.NewVector3
+	box := *NewBoxMinMax(-1, -1, -1, 1, 1, 1)
+	// Crossing, inside, and around the box
+	utils.Equals(t, true, TriangleIntersectsBox(*v(0, 0, 0), *v(3, 0, 0), *v(0, 3, 0), box))
+	utils.Equals(t, true, TriangleIntersectsBox(*v(0, 0, 0), *v(0.1, 0, 0), *v(0, 0.1, 0), box))
+	utils.Equals(t, true, TriangleIntersectsBox(*v(-10, -10, 0), *v(10, -10, 0), *v(0, 10, 0), box))
+	// Touching a face
+	utils.Equals(t, true, TriangleIntersectsBox(*v(1, 0, 0), *v(2, 0, 0), *v(2, 1, 0), box))
+	// Separated by a box axis, the triangle plane, and an edge cross product only
+	utils.Equals(t, false, TriangleIntersectsBox(*v(1.1, 0, 0), *v(2, 0, 0), *v(2, 1, 0), box))
+	utils.Equals(t, false, TriangleIntersectsBox(*v(2.5, 0, -5), *v(0, 2.5, -5), *v(1.25, 1.25, 5), box))
+	utils.Equals(t, false, TriangleIntersectsBox(*v(0.8, 2, 0), *v(2, 0.8, 0), *v(2, 2, 0), box))
+
+	// Any sampled point of the triangle inside the box means an overlap
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		p := func() vector3.Vector3 { return *v(r.Float64()*6-3, r.Float64()*6-3, r.Float64()*6-3) }
+		a, b, c := p(), p(), p()
+		inside := false
+		for j := 0; j < 200 && !inside; j++ {
+			s, u := r.Float64(), r.Float64()
+			if s+u > 1 {
+				s, u = 1-s, 1-u
+			}
+			inside = box.Contains(a.Plus(b.Minus(a).Times(s)).Plus(c.Minus(a).Times(u)))
+		}
+		if inside {
+			utils.Equals(t, true, TriangleIntersectsBox(a, b, c, box))
+		}
+	}
+}
+
+func TestSegmentTriangleDistance(t *testing.T) {
+	v := vector3.NewVector3
+	a, b, c := *v(0, 0, 0), *v(2, 0, 0), *v(0, 2, 0)
+	// Through the triangle
+	utils.Equals(t, 0., SegmentTriangleDistance(*v(0.5, 0.5, -1), *v(0.5, 0.5, 1), a, b, c))
+	// Parallel above it
+	utils.Equals(t, true, almostEqual(1, SegmentTriangleDistance(*v(0.5, 0.5, 1), *v(1, 0.2, 1), a, b, c)))
+	// Closest to an edge, across it
+	utils.Equals(t, true, almostEqual(1, SegmentTriangleDistance(*v(1, -1, -1), *v(1, -1, 1), a, b, c)))
+	// Reduced to a point
+	utils.Equals(t, true, almostEqual(3, SegmentTriangleDistance(*v(0.5, 0.5, 3), *v(0.5, 0.5, 3), a, b, c)))
+
+	// Against the distances of points sampled along the segment
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 200; i++ {
+		p := func() vector3.Vector3 { return *v(r.Float64()*4-2, r.Float64()*4-2, r.Float64()*4-2) }
+		s0, s1 := p(), p()
+		d := SegmentTriangleDistance(s0, s1, a, b, c)
+		sampled := math.Inf(1)
+		for j := 0; j <= 1000; j++ {
+			q := *s0.Lerp(&s1, float64(j)/1000)
+			sampled = math.Min(sampled, q.Distance(ClosestPointOnTriangle(q, a, b, c)))
+		}
+		utils.Equals(t, true, d <= sampled+1e-9 && sampled-d < 0.01)
+	}
+}
@@ -0,0 +1,502 @@
+package volume
+
+import (
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// withCornerUvs returns a copy of the mesh textured with one uv per entry of Tris, the vertices used with
+// different uvs being split along the seams
+func (m *Mesh) withCornerUvs(uvs []vector3.Vector3) *Mesh {
+	type corner struct {
+		vertex int32
+		u, v   float64
+	}
+	out := &Mesh{Center: m.Center}
+	hasNormals := len(m.Normals) == len(m.Vertices)
+	index := make(map[corner]int32)
+	for f := 0; f < m.triangleCount(); f++ {
+		if m.isOutOfRange(f) {
+			continue
+		}
+		for k := f * 3; k < f*3+3; k++ {
+			c := corner{m.Tris[k], uvs[k].X, uvs[k].Y}
+			i, ok := index[c]
+			if !ok {
+				i = int32(len(out.Vertices))
+				index[c] = i
+				out.Vertices = append(out.Vertices, m.Vertices[c.vertex].Clone())
+				if hasNormals {
+					out.Normals = append(out.Normals, m.Normals[c.vertex].Clone())
+				}
+				out.Uvs = append(out.Uvs, vector3.NewVector3(c.u, c.v, 0))
+			}
+			out.Tris = append(out.Tris, i)
+		}
+	}
+	return out
+}
+
+// ProjectPlanar returns a copy of the mesh textured by projecting its vertices on the plane through point,
+// scale being the distance covered by the uv square. Seen from the normal side u goes right and v up
+// Not in-place
+func (m *Mesh) ProjectPlanar(point, normal vector3.Vector3, scale float64) *Mesh {
+	u, v := planeBasis(normal.Normalize())
+	uvs := make([]vector3.Vector3, len(m.Tris))
+	for k, i := range m.Tris {
+		if i < 0 || int(i) >= len(m.Vertices) {
+			continue
+		}
+		d := m.Vertices[i].Minus(point)
+		uvs[k] = *vector3.NewVector3(u.Dot(d)/scale, v.Dot(d)/scale, 0)
+	}
+	return m.withCornerUvs(uvs)
+}
+
+// boxAxes are the uv axes of the faces of a box, by axis and sign of the face normal, such that u x v
+// is the normal
+var boxAxes = [3][2][2]vector3.Vector3{
+	{{*vector3.NewVector3(0, 0, -1), *vector3.NewVector3(0, 1, 0)}, {*vector3.NewVector3(0, 0, 1), *vector3.NewVector3(0, 1, 0)}},
+	{{*vector3.NewVector3(1, 0, 0), *vector3.NewVector3(0, 0, -1)}, {*vector3.NewVector3(1, 0, 0), *vector3.NewVector3(0, 0, 1)}},
+	{{*vector3.NewVector3(1, 0, 0), *vector3.NewVector3(0, 1, 0)}, {*vector3.NewVector3(-1, 0, 0), *vector3.NewVector3(0, 1, 0)}},
+}
+
+// ProjectBox returns a copy of the mesh textured by triplanar projection: every triangle is projected on
+// the face of the box its normal points to the most, each face of the box covering the uv square
+// Not in-place
+func (m *Mesh) ProjectBox(b Box) *Mesh {
+	size := b.GetSize()
+	extent := [3]float64{size.X, size.Y, size.Z}
+	uvs := make([]vector3.Vector3, len(m.Tris))
+	for f := 0; f < m.triangleCount(); f++ {
+		if m.isOutOfRange(f) {
+			continue
+		}
+		a, bb, c := m.triangle(f)
+		n := bb.Minus(a).Cross(c.Minus(a))
+		axis := 0
+		for i := 1; i < 3; i++ {
+			if math.Abs(component(*n, i)) > math.Abs(component(*n, axis)) {
+				axis = i
+			}
+		}
+		side := 0
+		if component(*n, axis) < 0 {
+			side = 1
+		}
+		u, v := boxAxes[axis][side][0], boxAxes[axis][side][1]
+		for k := f * 3; k < f*3+3; k++ {
+			d := m.Vertices[m.Tris[k]].Minus(*b.Min)
+			uvs[k] = *vector3.NewVector3(boxCoordinate(u, d, extent), boxCoordinate(v, d, extent), 0)
+		}
+	}
+	return m.withCornerUvs(uvs)
+}
+
+// boxCoordinate returns the coordinate from 0 to 1 of d, relative to the box minimum, along the signed axis
+func boxCoordinate(axis, d vector3.Vector3, extent [3]float64) float64 {
+	for i := 0; i < 3; i++ {
+		a := component(axis, i)
+		if a == 0 {
+			continue
+		}
+		if extent[i] == 0 {
+			return 0
+		}
+		t := component(d, i) / extent[i]
+		if a < 0 {
+			t = 1 - t
+		}
+		return t
+	}
+	return 0
+}
+
+// wrapUvs fixes the u of the triangles crossing the seam of a projection wrapping around an axis, u going
+// from 0 to 1 around it, and of the corners lying on the axis where it is undefined
+func wrapUvs(uvs []vector3.Vector3, onAxis []bool) {
+	for f := 0; f+2 < len(uvs); f += 3 {
+		t := uvs[f : f+3]
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for k := range t {
+			if !onAxis[f+k] {
+				lo, hi = math.Min(lo, t[k].X), math.Max(hi, t[k].X)
+			}
+		}
+		if hi-lo > 0.5 {
+			for k := range t {
+				if !onAxis[f+k] && t[k].X < 0.5 {
+					t[k].X++
+				}
+			}
+		}
+		var sum float64
+		n := 0
+		for k := range t {
+			if !onAxis[f+k] {
+				sum += t[k].X
+				n++
+			}
+		}
+		for k := range t {
+			if onAxis[f+k] && n > 0 {
+				t[k].X = sum / float64(n)
+			}
+		}
+	}
+}
+
+// ProjectCylindrical returns a copy of the mesh textured by projection on the cylinder of the axis through
+// center: u goes once around the axis from 0 to 1 and v along it, height being the distance covered by the
+// uv square. The mesh is split along the seam
+// Not in-place
+func (m *Mesh) ProjectCylindrical(center, axis vector3.Vector3, height float64) *Mesh {
+	axis = axis.Normalize()
+	e1, e2 := planeBasis(axis)
+	uvs := make([]vector3.Vector3, len(m.Tris))
+	onAxis := make([]bool, len(m.Tris))
+	for k, i := range m.Tris {
+		if i < 0 || int(i) >= len(m.Vertices) {
+			continue
+		}
+		d := m.Vertices[i].Minus(center)
+		x, y := e1.Dot(d), e2.Dot(d)
+		onAxis[k] = x == 0 && y == 0
+		uvs[k] = *vector3.NewVector3(turn(x, y), axis.Dot(d)/height+0.5, 0)
+	}
+	wrapUvs(uvs, onAxis)
+	return m.withCornerUvs(uvs)
+}
+
+// ProjectSpherical returns a copy of the mesh textured by projection on the sphere around center: u goes
+// once around the axis from 0 to 1 and v from the pole opposite to the axis, at 0, to the other one, at 1.
+// The mesh is split along the seam
+// Not in-place
+func (m *Mesh) ProjectSpherical(center, axis vector3.Vector3) *Mesh {
+	axis = axis.Normalize()
+	e1, e2 := planeBasis(axis)
+	uvs := make([]vector3.Vector3, len(m.Tris))
+	onAxis := make([]bool, len(m.Tris))
+	for k, i := range m.Tris {
+		if i < 0 || int(i) >= len(m.Vertices) {
+			continue
+		}
+		d := m.Vertices[i].Minus(center)
+		x, y := e1.Dot(d), e2.Dot(d)
+		onAxis[k] = x == 0 && y == 0
+		v := 0.5
+		if r := d.Norm2(); r > 0 {
+			v = 1 - math.Acos(math.Max(-1, math.Min(1, axis.Dot(d)/r)))/math.Pi
+		}
+		uvs[k] = *vector3.NewVector3(turn(x, y), v, 0)
+	}
+	wrapUvs(uvs, onAxis)
+	return m.withCornerUvs(uvs)
+}
+
+// turn returns the angle of (x, y) as a fraction of a turn in [0, 1)
+func turn(x, y float64) float64 {
+	t := math.Atan2(y, x) / (2 * math.Pi)
+	if t < 0 {
+		t++
+	}
+	if t >= 1 {
+		t = 0
+	}
+	return t
+}
+
+// UnwrapOptions configures the automatic unwrapping of a mesh
+type UnwrapOptions struct {
+	// MaxAngle is the largest angle in radians between the normal of the first triangle of a chart and the
+	// others, π/3 when 0
+	MaxAngle float64
+	// Margin is the gap between the charts as a fraction of the uv square
+	Margin float64
+}
+
+// uvChart is a part of the mesh flattened in one piece
+type uvChart struct {
+	faces []int
+	// uvs of the welded vertices of the chart
+	uvs      map[int32]vector3.Vector3
+	min, max [2]float64
+	// offset moves the uvs of the chart to its place in the packing
+	offset [2]float64
+	normal vector3.Vector3
+	area   float64
+}
+
+// faceNormal returns the unit normal of the i-th triangle, zero if it is degenerate
+func (m *Mesh) faceNormal(i int) vector3.Vector3 {
+	a, b, c := m.triangle(i)
+	return b.Minus(a).Cross(c.Minus(a)).Normalize()
+}
+
+// faceArea returns the area of the i-th triangle
+func (m *Mesh) faceArea(i int) float64 {
+	a, b, c := m.triangle(i)
+	return b.Minus(a).Cross(c.Minus(a)).Norm2() / 2
+}
+
+// charts groups the triangles into connected charts whose normals stay within maxAngle of their first one
+func (m *Mesh) charts(tris []int32, maxAngle float64) []*uvChart {
+	edges := newEdgeTable(tris, func(f int) bool { return m.isOutOfRange(f) })
+	chartOf := make([]int, m.triangleCount())
+	for i := range chartOf {
+		chartOf[i] = -1
+	}
+	limit := math.Cos(maxAngle)
+	var charts []*uvChart
+	for seed := range chartOf {
+		if chartOf[seed] != -1 || m.isOutOfRange(seed) {
+			continue
+		}
+		c := &uvChart{normal: m.faceNormal(seed)}
+		chartOf[seed] = len(charts)
+		for queue := []int{seed}; len(queue) > 0; queue = queue[1:] {
+			f := queue[0]
+			c.faces = append(c.faces, f)
+			for k := 0; k < 3; k++ {
+				uses := edges.uses[newEdgeKey(tris[f*3+k], tris[f*3+(k+1)%3])]
+				if len(uses) != 2 {
+					continue
+				}
+				for _, u := range uses {
+					n := m.faceNormal(u.face)
+					// Degenerate triangles follow their neighbours
+					if chartOf[u.face] != -1 || (n.Norm() > 0 && n.Dot(c.normal) < limit) {
+						continue
+					}
+					chartOf[u.face] = len(charts)
+					queue = append(queue, u.face)
+				}
+			}
+		}
+		charts = append(charts, c)
+	}
+	return charts
+}
+
+// lscmEntry is a coefficient of the least squares conformal maps system
+type lscmEntry struct {
+	col int
+	val float64
+}
+
+// flatten maps the chart to the plane with least squares conformal maps (Lévy et al. 2002), starting from
+// its projection on the plane of its normal and pinning the two vertices the furthest apart along it
+func (c *uvChart) flatten(m *Mesh, tris []int32) {
+	local := make(map[int32]int)
+	var vertices []int32
+	for _, f := range c.faces {
+		for _, v := range tris[f*3 : f*3+3] {
+			if _, ok := local[v]; !ok {
+				local[v] = len(vertices)
+				vertices = append(vertices, v)
+			}
+		}
+	}
+	// Initial guess and pins from the projection
+	e1, e2 := planeBasis(c.normal)
+	if c.normal.Norm() == 0 {
+		e1, e2 = *vector3.NewVector3(1, 0, 0), *vector3.NewVector3(0, 1, 0)
+	}
+	z := make([]float64, 2*len(vertices))
+	lo, hi := [2]int{}, [2]int{}
+	for i, v := range vertices {
+		p := *m.Vertices[v]
+		z[2*i], z[2*i+1] = e1.Dot(p), e2.Dot(p)
+		for a := 0; a < 2; a++ {
+			if z[2*i+a] < z[2*lo[a]+a] {
+				lo[a] = i
+			}
+			if z[2*i+a] > z[2*hi[a]+a] {
+				hi[a] = i
+			}
+		}
+	}
+	axis := 0
+	if z[2*hi[1]+1]-z[2*lo[1]+1] > z[2*hi[0]]-z[2*lo[0]] {
+		axis = 1
+	}
+	pinned := make([]bool, len(z))
+	for _, i := range []int{lo[axis], hi[axis]} {
+		pinned[2*i], pinned[2*i+1] = true, true
+	}
+
+	// Two rows per triangle, the real and imaginary parts of its conformal energy weighted by its area
+	var rows [][6]lscmEntry
+	for _, f := range c.faces {
+		a, b, cc := m.triangle(f)
+		n := b.Minus(a).Cross(cc.Minus(a))
+		area := n.Norm2() / 2
+		if area == 0 {
+			continue
+		}
+		x := b.Minus(a).Normalize()
+		y := n.Normalize().Cross(x)
+		q := [3][2]float64{{0, 0}, {b.Minus(a).Norm2(), 0}, {x.Dot(cc.Minus(a)), y.Dot(cc.Minus(a))}}
+		w := 1 / (2 * math.Sqrt(area))
+		var re, im [6]lscmEntry
+		for j := 0; j < 3; j++ {
+			p, r := q[(j+1)%3], q[(j+2)%3]
+			ex, ey := (r[0]-p[0])*w, (r[1]-p[1])*w
+			k := local[tris[f*3+j]]
+			re[2*j], re[2*j+1] = lscmEntry{2 * k, ex}, lscmEntry{2*k + 1, -ey}
+			im[2*j], im[2*j+1] = lscmEntry{2 * k, ey}, lscmEntry{2*k + 1, ex}
+		}
+		rows = append(rows, re, im)
+	}
+	// Gradient of the energy over the free coordinates
+	gradient := func(x []float64) []float64 {
+		g := make([]float64, len(x))
+		for _, row := range rows {
+			var s float64
+			for _, e := range row {
+				s += e.val * x[e.col]
+			}
+			for _, e := range row {
+				g[e.col] += e.val * s
+			}
+		}
+		for i := range g {
+			if pinned[i] {
+				g[i] = 0
+			}
+		}
+		return g
+	}
+	dot := func(a, b []float64) float64 {
+		var s float64
+		for i := range a {
+			s += a[i] * b[i]
+		}
+		return s
+	}
+	// Conjugate gradient on the normal equations
+	r := gradient(z)
+	for i := range r {
+		r[i] = -r[i]
+	}
+	p := append([]float64(nil), r...)
+	rr := dot(r, r)
+	for it, rr0 := 0, rr; it < 4*len(z) && rr > 1e-24*rr0; it++ {
+		ap := gradient(p)
+		pap := dot(p, ap)
+		if pap <= 0 {
+			break
+		}
+		alpha := rr / pap
+		for i := range z {
+			z[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+		}
+		next := dot(r, r)
+		for i := range p {
+			p[i] = r[i] + next/rr*p[i]
+		}
+		rr = next
+	}
+
+	// Scaled back to the area of the chart in space
+	var uvArea float64
+	for _, f := range c.faces {
+		t := [3]int{local[tris[f*3]], local[tris[f*3+1]], local[tris[f*3+2]]}
+		uvArea += cross2(point2{z[2*t[0]], z[2*t[0]+1]}, point2{z[2*t[1]], z[2*t[1]+1]}, point2{z[2*t[2]], z[2*t[2]+1]}) / 2
+		c.area += m.faceArea(f)
+	}
+	scale := 1.
+	if uvArea > 0 && c.area > 0 {
+		scale = math.Sqrt(c.area / uvArea)
+	}
+	c.uvs = make(map[int32]vector3.Vector3, len(vertices))
+	c.min = [2]float64{math.Inf(1), math.Inf(1)}
+	c.max = [2]float64{math.Inf(-1), math.Inf(-1)}
+	for i, v := range vertices {
+		uv := [2]float64{z[2*i] * scale, z[2*i+1] * scale}
+		c.uvs[v] = *vector3.NewVector3(uv[0], uv[1], 0)
+		for a := 0; a < 2; a++ {
+			c.min[a], c.max[a] = math.Min(c.min[a], uv[a]), math.Max(c.max[a], uv[a])
+		}
+	}
+}
+
+// packCharts places the bounding rectangles of the charts on shelves, the tallest first, and returns the
+// side of the square holding them
+func packCharts(charts []*uvChart, margin float64) float64 {
+	order := make([]int, len(charts))
+	var area, widest float64
+	for i, c := range charts {
+		order[i] = i
+		area += (c.max[0] - c.min[0]) * (c.max[1] - c.min[1])
+		widest = math.Max(widest, c.max[0]-c.min[0])
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := charts[order[i]], charts[order[j]]
+		return a.max[1]-a.min[1] > b.max[1]-b.min[1]
+	})
+	side := math.Sqrt(area)
+	// The gap depends on the side it is a fraction of, a few passes settle it
+	for pass := 0; pass < 3; pass++ {
+		gap := margin * side
+		width := math.Max(math.Sqrt(area)+gap*math.Sqrt(float64(len(charts))), widest+gap)
+		x, y, shelf := gap/2, gap/2, 0.
+		var right float64
+		for _, i := range order {
+			c := charts[i]
+			w, h := c.max[0]-c.min[0], c.max[1]-c.min[1]
+			if x > gap/2 && x+w+gap/2 > width {
+				x, y, shelf = gap/2, y+shelf+gap, 0
+			}
+			c.offset = [2]float64{x - c.min[0], y - c.min[1]}
+			x += w + gap
+			shelf = math.Max(shelf, h)
+			right = math.Max(right, x-gap/2)
+		}
+		side = math.Max(right, y+shelf) + gap/2
+		if margin == 0 || side == 0 {
+			break
+		}
+	}
+	return side
+}
+
+// Unwrap returns a copy of the mesh textured automatically: it is cut into charts of similar normals, each
+// flattened with least squares conformal maps keeping its angles and area, packed into the uv square
+// The mesh is split along the borders of the charts
+// Not in-place
+func (m *Mesh) Unwrap(o UnwrapOptions) *Mesh {
+	if o.MaxAngle <= 0 {
+		o.MaxAngle = math.Pi / 3
+	}
+	// Charts are connected through the vertices at the same position, ignoring the existing seams
+	weld := m.weldMap(0)
+	tris := make([]int32, len(m.Tris))
+	for k, i := range m.Tris {
+		tris[k] = i
+		if i >= 0 && int(i) < len(weld) {
+			tris[k] = weld[i]
+		}
+	}
+	charts := m.charts(tris, o.MaxAngle)
+	for _, c := range charts {
+		c.flatten(m, tris)
+	}
+	side := packCharts(charts, o.Margin)
+	if side == 0 {
+		side = 1
+	}
+	uvs := make([]vector3.Vector3, len(m.Tris))
+	for _, c := range charts {
+		for _, f := range c.faces {
+			for k := f * 3; k < f*3+3; k++ {
+				uv := c.uvs[tris[k]]
+				uvs[k] = *vector3.NewVector3((uv.X+c.offset[0])/side, (uv.Y+c.offset[1])/side, 0)
+			}
+		}
+	}
+	return m.withCornerUvs(uvs)
+}
@@ -0,0 +1,132 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// angleAt returns the angle at a of the triangle abc
+func angleAt(a, b, c vector3.Vector3) float64 {
+	ab, ac := b.Minus(a), c.Minus(a)
+	return math.Atan2(ab.Cross(ac).Norm2(), ab.Dot(ac))
+}
+
+// uvArea returns the signed area of the i-th triangle in uv space
+func uvArea(m *Mesh, i int) float64 {
+	a, b, c := m.Uvs[m.Tris[i*3]], m.Uvs[m.Tris[i*3+1]], m.Uvs[m.Tris[i*3+2]]
+	return cross2(point2{a.X, a.Y}, point2{b.X, b.Y}, point2{c.X, c.Y}) / 2
+}
+
+func TestMesh_ProjectPlanar(t *testing.T) {
+	m := testGrid(4)
+	p := m.ProjectPlanar(*vector3.NewVector3Zero(), *vector3.NewVector3(0, 0, 1), 2)
+	utils.Equals(t, len(m.Vertices), len(p.Vertices))
+	utils.Equals(t, len(p.Vertices), len(p.Uvs))
+	u, v := planeBasis(*vector3.NewVector3(0, 0, 1))
+	for i, uv := range p.Uvs {
+		utils.Equals(t, true, almostEqual(u.Dot(*p.Vertices[i])/2, uv.X))
+		utils.Equals(t, true, almostEqual(v.Dot(*p.Vertices[i])/2, uv.Y))
+	}
+	// Not in-place
+	utils.Equals(t, 0.25, m.Uvs[1].X)
+}
+
+func TestMesh_ProjectBox(t *testing.T) {
+	m := NewMeshSquareCuboid(2, true)
+	p := m.ProjectBox(*NewBoxMinMax(-1, -1, -1, 1, 1, 1))
+	// Every face covers the uv square, the corners shared with the same uv stay welded
+	utils.Equals(t, 20, len(p.Vertices))
+	for _, uv := range p.Uvs {
+		utils.Equals(t, true, (uv.X == 0 || uv.X == 1) && (uv.Y == 0 || uv.Y == 1))
+	}
+	for i := 0; i < p.triangleCount(); i++ {
+		utils.Equals(t, true, almostEqual(0.5, uvArea(p, i)))
+	}
+	utils.Equals(t, true, almostEqual(m.GetVolume(), p.GetVolume()))
+}
+
+func TestMesh_ProjectCylindrical(t *testing.T) {
+	m := testSphere(1, 8, 12)
+	m.RecalculateNormals()
+	p := m.ProjectCylindrical(*vector3.NewVector3Zero(), *vector3.NewVector3(0, 0, 1), 2)
+	utils.Equals(t, len(p.Vertices), len(p.Normals))
+	// Only the seam and the poles are split
+	utils.Equals(t, true, len(p.Vertices) > len(m.Vertices) && len(p.Vertices) < len(m.Vertices)*2)
+	for i := 0; i < p.triangleCount(); i++ {
+		utils.Equals(t, true, uvArea(p, i) > 0)
+	}
+	for _, uv := range p.Uvs {
+		utils.Equals(t, true, uv.X >= 0 && uv.X <= 1+1e-12 && uv.Y >= 0 && uv.Y <= 1)
+	}
+}
+
+func TestMesh_ProjectSpherical(t *testing.T) {
+	m := testSphere(1, 8, 12)
+	p := m.ProjectSpherical(*vector3.NewVector3Zero(), *vector3.NewVector3(0, 0, 1))
+	for i, uv := range p.Uvs {
+		utils.Equals(t, true, uv.X >= 0 && uv.X <= 1+1e-12)
+		// v follows the latitude
+		utils.Equals(t, true, almostEqual(1-math.Acos(p.Vertices[i].Z)/math.Pi, uv.Y))
+	}
+	for i := 0; i < p.triangleCount(); i++ {
+		utils.Equals(t, true, uvArea(p, i) > 0)
+	}
+}
+
+func TestMesh_Unwrap(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	u := m.Unwrap(UnwrapOptions{Margin: 0.02})
+	// One chart per face
+	utils.Equals(t, 24, len(u.Vertices))
+	utils.Equals(t, true, almostEqual(1, u.GetVolume()))
+	total := 0.
+	for i := 0; i < u.triangleCount(); i++ {
+		a := uvArea(u, i)
+		utils.Equals(t, true, a > 0)
+		total += a
+	}
+	// The charts don't overlap
+	utils.Equals(t, true, total < 1)
+	for _, uv := range u.Uvs {
+		utils.Equals(t, true, uv.X >= 0 && uv.X <= 1 && uv.Y >= 0 && uv.Y <= 1)
+	}
+	// Faces keep their shape, with the same scale
+	side := math.Sqrt(2 * uvArea(u, 0))
+	for i := 0; i < u.triangleCount(); i++ {
+		a, b, c := u.Uvs[u.Tris[i*3]], u.Uvs[u.Tris[i*3+1]], u.Uvs[u.Tris[i*3+2]]
+		pa, pb, pc := u.triangle(i)
+		for _, e := range [][2]float64{{a.Distance(*b), pa.Distance(pb)}, {b.Distance(*c), pb.Distance(pc)}, {c.Distance(*a), pc.Distance(pa)}} {
+			utils.Equals(t, true, math.Abs(e[0]-e[1]*side) < 1e-6)
+		}
+	}
+}
+
+func TestMesh_UnwrapConformal(t *testing.T) {
+	// A bent strip is flattened in one chart, keeping its angles
+	m := testGrid(6)
+	for _, v := range m.Vertices {
+		v.Z = 0.2 * v.X * v.X
+	}
+	u := m.Unwrap(UnwrapOptions{})
+	utils.Equals(t, len(m.Vertices), len(u.Vertices))
+	for i := 0; i < u.triangleCount(); i++ {
+		utils.Equals(t, true, uvArea(u, i) > 0)
+		a, b, c := u.Uvs[u.Tris[i*3]], u.Uvs[u.Tris[i*3+1]], u.Uvs[u.Tris[i*3+2]]
+		pa, pb, pc := u.triangle(i)
+		utils.Equals(t, true, math.Abs(angleAt(*a, *b, *c)-angleAt(pa, pb, pc)) < 0.05)
+	}
+
+	// A sphere needs several charts, all in the uv square
+	sphere := testSphere(1, 10, 16)
+	s := sphere.Unwrap(UnwrapOptions{Margin: 0.01})
+	utils.Equals(t, true, len(s.Vertices) > len(sphere.Vertices))
+	for i := 0; i < s.triangleCount(); i++ {
+		utils.Equals(t, true, uvArea(s, i) > 0)
+	}
+	for _, uv := range s.Uvs {
+		utils.Equals(t, true, uv.X >= 0 && uv.X <= 1 && uv.Y >= 0 && uv.Y <= 1)
+	}
+}
@@ -0,0 +1,422 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// ValidationReport lists the defects found in a mesh, see Mesh.Validate
+type ValidationReport struct {
+	// TrailingIndices is the number of indices left over when len(Tris) is not a multiple of 3
+	TrailingIndices int
+	// OutOfRange lists the triangles referencing a vertex that doesn't exist
+	OutOfRange []int
+	// NaNVertices lists the vertices having a NaN or infinite coordinate
+	NaNVertices []int
+	// Degenerate lists the triangles with a repeated vertex or a near-zero area
+	Degenerate []int
+	// DuplicateVertices lists pairs of distinct vertices closer than the tolerance, the first one is kept on welding
+	DuplicateVertices [][2]int
+	// NonManifoldEdges lists the edges shared by more than two triangles
+	NonManifoldEdges [][2]int32
+	// InconsistentEdges lists the edges whose two triangles traverse them in the same direction
+	InconsistentEdges [][2]int32
+	// Holes lists the boundary loops as sequences of vertex indices
+	Holes [][]int32
+}
+
+// IsValid returns whether no defect has been found
+func (r ValidationReport) IsValid() bool {
+	return r.TrailingIndices == 0 &&
+		len(r.OutOfRange) == 0 &&
+		len(r.NaNVertices) == 0 &&
+		len(r.Degenerate) == 0 &&
+		len(r.DuplicateVertices) == 0 &&
+		len(r.NonManifoldEdges) == 0 &&
+		len(r.InconsistentEdges) == 0 &&
+		len(r.Holes) == 0
+}
+
+// edgeKey is an undirected edge, a is always the smallest index
+type edgeKey struct {
+	a, b int32
+}
+
+func newEdgeKey(a, b int32) edgeKey {
+	if a > b {
+		a, b = b, a
+	}
+	return edgeKey{a: a, b: b}
+}
+
+// edgeUse is a triangle bordering an edge, forward when it traverses the edge from a to b
+type edgeUse struct {
+	face    int
+	forward bool
+}
+
+// edgeTable maps the undirected edges of a triangle list to the triangles using them
+type edgeTable struct {
+	// keys in order of first appearance, for deterministic iteration
+	keys []edgeKey
+	uses map[edgeKey][]edgeUse
+}
+
+// newEdgeTable indexes the edges of tris, ignoring the triangles for which skip returns true
+func newEdgeTable(tris []int32, skip func(face int) bool) *edgeTable {
+	t := &edgeTable{uses: make(map[edgeKey][]edgeUse)}
+	for f := 0; f < len(tris)/3; f++ {
+		if skip != nil && skip(f) {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			a, b := tris[f*3+k], tris[f*3+(k+1)%3]
+			key := newEdgeKey(a, b)
+			if _, ok := t.uses[key]; !ok {
+				t.keys = append(t.keys, key)
+			}
+			t.uses[key] = append(t.uses[key], edgeUse{face: f, forward: a == key.a})
+		}
+	}
+	return t
+}
+
+// boundaryLoops chains the edges used by a single triangle into closed loops,
+// following the direction in which the triangles traverse them
+func (t *edgeTable) boundaryLoops() [][]int32 {
+	next := make(map[int32][]int32)
+	var starts []int32
+	for _, key := range t.keys {
+		uses := t.uses[key]
+		if len(uses) != 1 {
+			continue
+		}
+		from, to := key.a, key.b
+		if !uses[0].forward {
+			from, to = to, from
+		}
+		next[from] = append(next[from], to)
+		starts = append(starts, from)
+	}
+	var loops [][]int32
+	for _, start := range starts {
+		if len(next[start]) == 0 {
+			continue
+		}
+		loop := []int32{start}
+		v := start
+		for {
+			n := next[v]
+			if len(n) == 0 {
+				// Open chain, happens around non-manifold vertices
+				break
+			}
+			to := n[0]
+			next[v] = n[1:]
+			if to == start {
+				break
+			}
+			loop = append(loop, to)
+			v = to
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
+
+func isFinite(v *vector3.Vector3) bool {
+	return !math.IsNaN(v.X) && !math.IsNaN(v.Y) && !math.IsNaN(v.Z) &&
+		!math.IsInf(v.X, 0) && !math.IsInf(v.Y, 0) && !math.IsInf(v.Z, 0)
+}
+
+// weldMap maps every vertex to the first vertex closer than tolerance, or to itself
+// Non-finite vertices are never merged
+func (m *Mesh) weldMap(tolerance float64) []int32 {
+	type cell struct {
+		x, y, z int64
+	}
+	size := tolerance
+	if size <= 0 {
+		size = 1
+	}
+	cellOf := func(v *vector3.Vector3) cell {
+		return cell{int64(math.Floor(v.X / size)), int64(math.Floor(v.Y / size)), int64(math.Floor(v.Z / size))}
+	}
+	grid := make(map[cell][]int32)
+	remap := make([]int32, len(m.Vertices))
+	for i, v := range m.Vertices {
+		remap[i] = int32(i)
+		if !isFinite(v) {
+			continue
+		}
+		c := cellOf(v)
+		found := false
+		for dx := int64(-1); dx <= 1 && !found; dx++ {
+			for dy := int64(-1); dy <= 1 && !found; dy++ {
+				for dz := int64(-1); dz <= 1 && !found; dz++ {
+					for _, j := range grid[cell{c.x + dx, c.y + dy, c.z + dz}] {
+						if v.Distance(*m.Vertices[j]) <= tolerance {
+							remap[i] = j
+							found = true
+							break
+						}
+					}
+				}
+			}
+		}
+		if !found {
+			grid[c] = append(grid[c], int32(i))
+		}
+	}
+	return remap
+}
+
+// isDegenerate returns whether the i-th triangle repeats a vertex or has an area not above tolerance²
+func (m *Mesh) isDegenerate(i int, tolerance float64) bool {
+	t := m.Tris[i*3 : i*3+3]
+	if t[0] == t[1] || t[1] == t[2] || t[2] == t[0] {
+		return true
+	}
+	a, b, c := m.triangle(i)
+	n := b.Minus(a).Cross(c.Minus(a))
+	// Written negated so that NaN areas are degenerate too
+	return !(math.Sqrt(n.Dot(*n))/2 > tolerance*tolerance)
+}
+
+// isOutOfRange returns whether the i-th triangle references a vertex that doesn't exist
+func (m *Mesh) isOutOfRange(i int) bool {
+	for _, v := range m.Tris[i*3 : i*3+3] {
+		if v < 0 || int(v) >= len(m.Vertices) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate reports the defects of the mesh that usually break downstream algorithms
+// Vertices closer than tolerance are duplicates, triangles of area below tolerance² are degenerate
+func (m *Mesh) Validate(tolerance float64) ValidationReport {
+	var r ValidationReport
+	r.TrailingIndices = len(m.Tris) % 3
+	for i, v := range m.Vertices {
+		if !isFinite(v) {
+			r.NaNVertices = append(r.NaNVertices, i)
+		}
+	}
+	for i, j := range m.weldMap(tolerance) {
+		if int(j) != i {
+			r.DuplicateVertices = append(r.DuplicateVertices, [2]int{int(j), i})
+		}
+	}
+	ignored := make([]bool, m.triangleCount())
+	for i := range ignored {
+		if m.isOutOfRange(i) {
+			r.OutOfRange = append(r.OutOfRange, i)
+			ignored[i] = true
+		} else if m.isDegenerate(i, tolerance) {
+			r.Degenerate = append(r.Degenerate, i)
+			ignored[i] = true
+		}
+	}
+
+	edges := newEdgeTable(m.Tris, func(f int) bool { return ignored[f] })
+	for _, key := range edges.keys {
+		uses := edges.uses[key]
+		switch {
+		case len(uses) > 2:
+			r.NonManifoldEdges = append(r.NonManifoldEdges, [2]int32{key.a, key.b})
+		case len(uses) == 2 && uses[0].forward == uses[1].forward:
+			r.InconsistentEdges = append(r.InconsistentEdges, [2]int32{key.a, key.b})
+		}
+	}
+	r.Holes = edges.boundaryLoops()
+	return r
+}
+
+// WeldVertices returns a mesh where the vertices closer than tolerance are merged into one
+// Normals and uvs of the kept vertices are carried over
+// Not in-place
+func (m *Mesh) WeldVertices(tolerance float64) *Mesh {
+	remap := m.weldMap(tolerance)
+	index := make([]int32, len(m.Vertices))
+	out := &Mesh{Center: m.Center}
+	hasNormals := len(m.Normals) == len(m.Vertices)
+	hasUvs := len(m.Uvs) == len(m.Vertices)
+	for i, j := range remap {
+		if int(j) != i {
+			index[i] = index[j]
+			continue
+		}
+		index[i] = int32(len(out.Vertices))
+		out.Vertices = append(out.Vertices, m.Vertices[i].Clone())
+		if hasNormals {
+			out.Normals = append(out.Normals, m.Normals[i].Clone())
+		}
+		if hasUvs {
+			out.Uvs = append(out.Uvs, m.Uvs[i].Clone())
+		}
+	}
+	out.Tris = make([]int32, 0, len(m.Tris))
+	for _, v := range m.Tris {
+		if v >= 0 && int(v) < len(index) {
+			v = index[v]
+		}
+		out.Tris = append(out.Tris, v)
+	}
+	return out
+}
+
+// RemoveDegenerateTriangles returns a mesh without the triangles that repeat a vertex, have an area
+// not above tolerance², reference missing vertices or are incomplete
+// Not in-place
+func (m *Mesh) RemoveDegenerateTriangles(tolerance float64) *Mesh {
+	out := m.copyVertices()
+	for i := 0; i < m.triangleCount(); i++ {
+		if m.isOutOfRange(i) || m.isDegenerate(i, tolerance) {
+			continue
+		}
+		out.Tris = append(out.Tris, m.Tris[i*3:i*3+3]...)
+	}
+	return out
+}
+
+// UnifyWinding returns a mesh where adjacent triangles traverse their shared edges in opposite directions
+// Closed parts are then oriented so that their normals face outward
+// Not in-place
+func (m *Mesh) UnifyWinding() *Mesh {
+	out := m.copyVertices()
+	out.Tris = append(out.Tris, m.Tris[:m.triangleCount()*3]...)
+	skip := func(f int) bool { return out.isOutOfRange(f) }
+	edges := newEdgeTable(out.Tris, skip)
+	visited := make([]bool, out.triangleCount())
+	flip := func(f int) {
+		out.Tris[f*3+1], out.Tris[f*3+2] = out.Tris[f*3+2], out.Tris[f*3+1]
+	}
+	for seed := range visited {
+		if visited[seed] || skip(seed) {
+			continue
+		}
+		// Breadth-first walk over the triangles of one connected part
+		visited[seed] = true
+		component := []int{seed}
+		for q := 0; q < len(component); q++ {
+			f := component[q]
+			for k := 0; k < 3; k++ {
+				a, b := out.Tris[f*3+k], out.Tris[f*3+(k+1)%3]
+				uses := edges.uses[newEdgeKey(a, b)]
+				if len(uses) != 2 {
+					continue
+				}
+				n := uses[0].face
+				if n == f {
+					n = uses[1].face
+				}
+				if visited[n] {
+					continue
+				}
+				visited[n] = true
+				// The neighbour must traverse the edge from b to a
+				for l := 0; l < 3; l++ {
+					if out.Tris[n*3+l] == a && out.Tris[n*3+(l+1)%3] == b {
+						flip(n)
+						break
+					}
+				}
+				component = append(component, n)
+			}
+		}
+
+		closed := true
+		volume := 0.
+		for _, f := range component {
+			for k := 0; k < 3; k++ {
+				if len(edges.uses[newEdgeKey(out.Tris[f*3+k], out.Tris[f*3+(k+1)%3])]) != 2 {
+					closed = false
+				}
+			}
+			a, b, c := out.triangle(f)
+			volume += a.Dot(*b.Cross(c))
+		}
+		if closed && volume < 0 {
+			for _, f := range component {
+				flip(f)
+			}
+		}
+	}
+	return out
+}
+
+// FillHoles returns a mesh where the boundary loops of at most maxEdges edges are closed
+// by a fan of triangles around the loop centroid
+// Not in-place
+func (m *Mesh) FillHoles(maxEdges int) *Mesh {
+	out := m.copyVertices()
+	out.Tris = append(out.Tris, m.Tris[:m.triangleCount()*3]...)
+	hasNormals := len(m.Normals) == len(m.Vertices)
+	hasUvs := len(m.Uvs) == len(m.Vertices)
+	edges := newEdgeTable(out.Tris, func(f int) bool { return out.isOutOfRange(f) })
+	for _, loop := range edges.boundaryLoops() {
+		if len(loop) < 3 || len(loop) > maxEdges {
+			continue
+		}
+		if len(loop) == 3 {
+			out.Tris = append(out.Tris, loop[2], loop[1], loop[0])
+			continue
+		}
+		center := vector3.NewVector3Zero()
+		normal := vector3.NewVector3Zero()
+		uv := vector3.NewVector3Zero()
+		for _, v := range loop {
+			center.Add(out.Vertices[v])
+			if hasNormals {
+				normal.Add(out.Normals[v])
+			}
+			if hasUvs {
+				uv.Add(out.Uvs[v])
+			}
+		}
+		center.Divide(float64(len(loop)))
+		c := int32(len(out.Vertices))
+		out.Vertices = append(out.Vertices, center)
+		if hasNormals {
+			n := normal.Normalize()
+			out.Normals = append(out.Normals, &n)
+		}
+		if hasUvs {
+			uv.Divide(float64(len(loop)))
+			out.Uvs = append(out.Uvs, uv)
+		}
+		// Boundary edges go from loop[i] to loop[i+1], the patch goes the other way
+		for i := range loop {
+			out.Tris = append(out.Tris, loop[(i+1)%len(loop)], loop[i], c)
+		}
+	}
+	return out
+}
+
+// Repair returns a mesh with welded vertices, no degenerate triangles, unified winding
+// and its holes of at most maxHoleEdges edges filled
+// Not in-place
+func (m *Mesh) Repair(tolerance float64, maxHoleEdges int) *Mesh {
+	return m.WeldVertices(tolerance).
+		RemoveDegenerateTriangles(tolerance).
+		UnifyWinding().
+		FillHoles(maxHoleEdges).
+		UnifyWinding()
+}
+
+// copyVertices returns a mesh with a deep copy of the vertices, normals and uvs but no triangles
+func (m *Mesh) copyVertices() *Mesh {
+	out := &Mesh{Center: m.Center}
+	for _, v := range m.Vertices {
+		out.Vertices = append(out.Vertices, v.Clone())
+	}
+	for _, v := range m.Normals {
+		out.Normals = append(out.Normals, v.Clone())
+	}
+	for _, v := range m.Uvs {
+		out.Uvs = append(out.Uvs, v.Clone())
+	}
+	return out
+}
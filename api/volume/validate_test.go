@@ -0,0 +1,125 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+// openCuboid returns a unit cuboid with its front face removed
+func openCuboid() *Mesh {
+	m := NewMeshSquareCuboid(1, true)
+	m.Tris = m.Tris[6:]
+	return m
+}
+
+func TestMesh_ValidateCuboid(t *testing.T) {
+	r := NewMeshSquareCuboid(1, true).Validate(1e-6)
+	utils.Equals(t, true, r.IsValid())
+}
+
+func TestMesh_ValidateDefects(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	m.Vertices = append(m.Vertices,
+		vector3.NewVector3(0.5, 0.5, 0.5), // duplicate of 5
+		vector3.NewVector3(math.NaN(), 0, 0),
+	)
+	m.Tris = append(m.Tris,
+		0, 0, 1, // degenerate
+		0, 1, 42, // out of range
+		7, // trailing
+	)
+	r := m.Validate(1e-6)
+	utils.Equals(t, false, r.IsValid())
+	utils.Equals(t, 1, r.TrailingIndices)
+	utils.Equals(t, []int{13}, r.OutOfRange)
+	utils.Equals(t, []int{12}, r.Degenerate)
+	utils.Equals(t, []int{9}, r.NaNVertices)
+	utils.Equals(t, [][2]int{{5, 8}}, r.DuplicateVertices)
+	utils.Equals(t, 0, len(r.Holes))
+}
+
+func TestMesh_ValidateTopology(t *testing.T) {
+	r := openCuboid().Validate(1e-6)
+	utils.Equals(t, 1, len(r.Holes))
+	utils.Equals(t, 4, len(r.Holes[0]))
+	utils.Equals(t, 0, len(r.InconsistentEdges))
+
+	m := NewMeshSquareCuboid(1, true)
+	m.Tris[1], m.Tris[2] = m.Tris[2], m.Tris[1]
+	r = m.Validate(1e-6)
+	utils.Equals(t, 3, len(r.InconsistentEdges))
+
+	// A third triangle on the edge 0-2
+	m = NewMeshSquareCuboid(1, true)
+	m.Vertices = append(m.Vertices, vector3.NewVector3(0, 0, -2))
+	m.Tris = append(m.Tris, 0, 2, 8)
+	r = m.Validate(1e-6)
+	utils.Equals(t, [][2]int32{{0, 2}}, r.NonManifoldEdges)
+}
+
+func TestMesh_WeldVertices(t *testing.T) {
+	// Two triangles sharing an edge, stored as a triangle soup
+	m := &Mesh{
+		Vertices: []*vector3.Vector3{
+			vector3.NewVector3(0, 0, 0),
+			vector3.NewVector3(1, 0, 0),
+			vector3.NewVector3(0, 1, 0),
+			vector3.NewVector3(1, 0, 0.0001),
+			vector3.NewVector3(1, 1, 0),
+			vector3.NewVector3(0, 1, 0),
+		},
+		Tris: []int32{0, 1, 2, 3, 4, 5},
+	}
+	w := m.WeldVertices(0.001)
+	utils.Equals(t, 4, len(w.Vertices))
+	utils.Equals(t, []int32{0, 1, 2, 1, 3, 2}, w.Tris)
+	// Not in-place
+	utils.Equals(t, 6, len(m.Vertices))
+}
+
+func TestMesh_RemoveDegenerateTriangles(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	m.Tris = append(m.Tris, 0, 0, 1, 0, 1, 42, 3)
+	r := m.RemoveDegenerateTriangles(1e-6)
+	utils.Equals(t, cuboidTris(), r.Tris)
+}
+
+func TestMesh_UnifyWinding(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	// Flip a few triangles, and then the whole mesh
+	for _, f := range []int{0, 5, 7} {
+		m.Tris[f*3+1], m.Tris[f*3+2] = m.Tris[f*3+2], m.Tris[f*3+1]
+	}
+	u := m.UnifyWinding()
+	utils.Equals(t, 0, len(u.Validate(1e-6).InconsistentEdges))
+	utils.Equals(t, true, almostEqual(1, u.GetVolume()))
+
+	for f := 0; f < len(m.Tris)/3; f++ {
+		m.Tris[f*3+1], m.Tris[f*3+2] = m.Tris[f*3+2], m.Tris[f*3+1]
+	}
+	u = m.UnifyWinding()
+	utils.Equals(t, true, almostEqual(1, u.GetVolume()))
+}
+
+func TestMesh_FillHoles(t *testing.T) {
+	m := openCuboid()
+	utils.Equals(t, m, m.FillHoles(3))
+	f := m.FillHoles(4)
+	r := f.Validate(1e-6)
+	utils.Equals(t, true, r.IsValid())
+	utils.Equals(t, true, almostEqual(1, f.GetVolume()))
+}
+
+func TestMesh_Repair(t *testing.T) {
+	m := openCuboid()
+	// Split the top face from the rest and flip it
+	m.Vertices = append(m.Vertices, m.Vertices[2].Clone(), m.Vertices[3].Clone())
+	copy(m.Tris, []int32{8, 4, 9, 8, 5, 4})
+	m.Tris = append(m.Tris, 1, 1, 2)
+	r := m.Repair(1e-6, 8)
+	utils.Equals(t, true, r.Validate(1e-6).IsValid())
+	utils.Equals(t, true, almostEqual(1, r.GetVolume()))
+}
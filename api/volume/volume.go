@@ -1,5 +1,7 @@
 package volume
 
+import "github.com/louis030195/protometry/internal/utils"
+
 // Volume is a 3-d interface representing volumes like Boxes, Spheres, Capsules ...
 type Volume interface {
 	// Fit check if the given volume is entirely contained in the other one
@@ -11,3 +13,56 @@ type Volume interface {
 	// Mutate create a new volume with random mutations
 	Mutate(float64) Volume
 }
+
+// NewAnyVolume wraps a Sphere, Capsule, Box or Mesh, given by pointer or by value, to send volumes of
+// different types in one list. Other types return ErrVolumeUnknownType
+// The volume is not copied when given by pointer
+func NewAnyVolume(v interface{}) (*AnyVolume, error) {
+	switch v := v.(type) {
+	case *Sphere:
+		return &AnyVolume{Shape: &AnyVolume_Sphere{Sphere: v}}, nil
+	case Sphere:
+		return &AnyVolume{Shape: &AnyVolume_Sphere{Sphere: &v}}, nil
+	case *Capsule:
+		return &AnyVolume{Shape: &AnyVolume_Capsule{Capsule: v}}, nil
+	case Capsule:
+		return &AnyVolume{Shape: &AnyVolume_Capsule{Capsule: &v}}, nil
+	case *Box:
+		return &AnyVolume{Shape: &AnyVolume_Box{Box: v}}, nil
+	case Box:
+		return &AnyVolume{Shape: &AnyVolume_Box{Box: &v}}, nil
+	case *Mesh:
+		return &AnyVolume{Shape: &AnyVolume_Mesh{Mesh: v}}, nil
+	case Mesh:
+		return &AnyVolume{Shape: &AnyVolume_Mesh{Mesh: &v}}, nil
+	}
+	return nil, utils.ErrVolumeUnknownType
+}
+
+// NewAnyVolumes wraps each volume with NewAnyVolume, failing on the first of an unknown type
+func NewAnyVolumes(vs ...interface{}) ([]*AnyVolume, error) {
+	out := make([]*AnyVolume, len(vs))
+	for i, v := range vs {
+		a, err := NewAnyVolume(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = a
+	}
+	return out, nil
+}
+
+// Unwrap returns the wrapped volume, a *Sphere, *Capsule, *Box or *Mesh, nil if none is set
+func (a *AnyVolume) Unwrap() interface{} {
+	switch s := a.GetShape().(type) {
+	case *AnyVolume_Sphere:
+		return s.Sphere
+	case *AnyVolume_Capsule:
+		return s.Capsule
+	case *AnyVolume_Box:
+		return s.Box
+	case *AnyVolume_Mesh:
+		return s.Mesh
+	}
+	return nil
+}
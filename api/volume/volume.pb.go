@@ -234,11 +234,123 @@ func (m *Mesh) GetUvs() []*vector3.Vector3 {
 	return nil
 }
 
+// AnyVolume holds one volume of any type, for lists of volumes whose types are only known at runtime
+type AnyVolume struct {
+	// Types that are valid to be assigned to Shape:
+	//	*AnyVolume_Sphere
+	//	*AnyVolume_Capsule
+	//	*AnyVolume_Box
+	//	*AnyVolume_Mesh
+	Shape                isAnyVolume_Shape `protobuf_oneof:"shape"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AnyVolume) Reset()         { *m = AnyVolume{} }
+func (m *AnyVolume) String() string { return proto.CompactTextString(m) }
+func (*AnyVolume) ProtoMessage()    {}
+func (*AnyVolume) Descriptor() ([]byte, []int) {
+	return fileDescriptor_498b213ad3bcd5ad, []int{4}
+}
+
+func (m *AnyVolume) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnyVolume.Unmarshal(m, b)
+}
+func (m *AnyVolume) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnyVolume.Marshal(b, m, deterministic)
+}
+func (m *AnyVolume) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnyVolume.Merge(m, src)
+}
+func (m *AnyVolume) XXX_Size() int {
+	return xxx_messageInfo_AnyVolume.Size(m)
+}
+func (m *AnyVolume) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnyVolume.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnyVolume proto.InternalMessageInfo
+
+type isAnyVolume_Shape interface {
+	isAnyVolume_Shape()
+}
+
+type AnyVolume_Sphere struct {
+	Sphere *Sphere `protobuf:"bytes,1,opt,name=sphere,proto3,oneof"`
+}
+
+type AnyVolume_Capsule struct {
+	Capsule *Capsule `protobuf:"bytes,2,opt,name=capsule,proto3,oneof"`
+}
+
+type AnyVolume_Box struct {
+	Box *Box `protobuf:"bytes,3,opt,name=box,proto3,oneof"`
+}
+
+type AnyVolume_Mesh struct {
+	Mesh *Mesh `protobuf:"bytes,4,opt,name=mesh,proto3,oneof"`
+}
+
+func (*AnyVolume_Sphere) isAnyVolume_Shape() {}
+
+func (*AnyVolume_Capsule) isAnyVolume_Shape() {}
+
+func (*AnyVolume_Box) isAnyVolume_Shape() {}
+
+func (*AnyVolume_Mesh) isAnyVolume_Shape() {}
+
+func (m *AnyVolume) GetShape() isAnyVolume_Shape {
+	if m != nil {
+		return m.Shape
+	}
+	return nil
+}
+
+func (m *AnyVolume) GetSphere() *Sphere {
+	if x, ok := m.GetShape().(*AnyVolume_Sphere); ok {
+		return x.Sphere
+	}
+	return nil
+}
+
+func (m *AnyVolume) GetCapsule() *Capsule {
+	if x, ok := m.GetShape().(*AnyVolume_Capsule); ok {
+		return x.Capsule
+	}
+	return nil
+}
+
+func (m *AnyVolume) GetBox() *Box {
+	if x, ok := m.GetShape().(*AnyVolume_Box); ok {
+		return x.Box
+	}
+	return nil
+}
+
+func (m *AnyVolume) GetMesh() *Mesh {
+	if x, ok := m.GetShape().(*AnyVolume_Mesh); ok {
+		return x.Mesh
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AnyVolume) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AnyVolume_Sphere)(nil),
+		(*AnyVolume_Capsule)(nil),
+		(*AnyVolume_Box)(nil),
+		(*AnyVolume_Mesh)(nil),
+	}
+}
+
 func init() {
 	proto.RegisterType((*Sphere)(nil), "protometry.volume.Sphere")
 	proto.RegisterType((*Capsule)(nil), "protometry.volume.Capsule")
 	proto.RegisterType((*Box)(nil), "protometry.volume.Box")
 	proto.RegisterType((*Mesh)(nil), "protometry.volume.Mesh")
+	proto.RegisterType((*AnyVolume)(nil), "protometry.volume.AnyVolume")
 }
 
 func init() {
@@ -246,25 +358,30 @@ func init() {
 }
 
 var fileDescriptor_498b213ad3bcd5ad = []byte{
-	// 306 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xbd, 0x4e, 0xfb, 0x30,
-	0x14, 0xc5, 0x95, 0xa6, 0x75, 0xff, 0xba, 0x7f, 0x96, 0x5a, 0x80, 0x22, 0x58, 0xaa, 0x4c, 0x5d,
-	0x48, 0x0a, 0x51, 0x85, 0x10, 0x62, 0x29, 0x33, 0x52, 0x55, 0xa0, 0x03, 0x9b, 0xeb, 0x5a, 0xc4,
-	0x52, 0x5c, 0x47, 0xfe, 0x08, 0xe1, 0x95, 0x78, 0x38, 0x9e, 0x01, 0xc5, 0x4e, 0x29, 0x12, 0x43,
-	0x50, 0xa7, 0xe3, 0xeb, 0xfb, 0x3b, 0xc7, 0x1f, 0xba, 0x70, 0x54, 0xc9, 0xc2, 0x0a, 0x96, 0x94,
-	0x4a, 0x1a, 0x89, 0x47, 0x4e, 0x04, 0x33, 0xea, 0x3d, 0xf1, 0x8d, 0xb3, 0xdb, 0x57, 0x6e, 0x72,
-	0xbb, 0x4e, 0xa8, 0x14, 0x69, 0x21, 0x2d, 0xd7, 0xd3, 0x6c, 0x7a, 0x79, 0x33, 0x4b, 0xf7, 0x64,
-	0x4a, 0x4a, 0x9e, 0x56, 0x8c, 0x1a, 0xa9, 0xb2, 0x9d, 0xfa, 0xbc, 0xf8, 0x19, 0xd0, 0x63, 0x99,
-	0x33, 0xc5, 0x70, 0x06, 0x88, 0xb2, 0xad, 0x61, 0x2a, 0x0a, 0xc6, 0xc1, 0xe4, 0xff, 0xd5, 0x79,
-	0xf2, 0xf3, 0xa8, 0xd6, 0xb4, 0xf2, 0xba, 0x6c, 0x51, 0x7c, 0x0a, 0x48, 0x91, 0x0d, 0xb7, 0x3a,
-	0xea, 0x8d, 0x83, 0x49, 0xb0, 0x6c, 0xab, 0xf8, 0x09, 0x86, 0xf7, 0xa4, 0xd4, 0xb6, 0x38, 0x30,
-	0xf7, 0x18, 0x06, 0x6f, 0x7c, 0x63, 0xf2, 0x36, 0xd6, 0x17, 0x31, 0x85, 0x70, 0x2e, 0x6b, 0x7c,
-	0x01, 0xa1, 0xe0, 0xdb, 0xbf, 0xc4, 0x35, 0x9c, 0xc3, 0x49, 0xed, 0x92, 0x3a, 0x71, 0x52, 0xc7,
-	0x9f, 0x01, 0xf4, 0x1f, 0x98, 0xce, 0x0f, 0xbb, 0xf8, 0x35, 0xfc, 0xab, 0x98, 0x32, 0x9c, 0xb2,
-	0xe6, 0x4b, 0xc2, 0x2e, 0xdb, 0x37, 0x8c, 0x31, 0xf4, 0x8d, 0xe2, 0x3a, 0x0a, 0xc7, 0xe1, 0x64,
-	0xb0, 0x74, 0x6b, 0x3c, 0x83, 0xe1, 0x56, 0x2a, 0x41, 0x0a, 0x1d, 0xf5, 0xbb, 0xb3, 0x76, 0x6c,
-	0xf3, 0x60, 0x5b, 0xe9, 0x68, 0xd0, 0x6d, 0x69, 0xb8, 0xf9, 0x1d, 0x9c, 0x50, 0x29, 0x92, 0x5f,
-	0x83, 0x35, 0x47, 0x2b, 0xa7, 0x8b, 0xe0, 0x05, 0xf9, 0x9d, 0x8f, 0xde, 0x68, 0xb1, 0xa7, 0x7c,
-	0x77, 0x8d, 0x9c, 0x31, 0xfb, 0x0a, 0x00, 0x00, 0xff, 0xff, 0x48, 0xc6, 0x1b, 0xae, 0xa8, 0x02,
-	0x00, 0x00,
+	// 395 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xcf, 0xae, 0x93, 0x40,
+	0x18, 0xc5, 0xa1, 0xfc, 0xd3, 0x4f, 0x37, 0x77, 0xa2, 0xd7, 0xf1, 0xba, 0x69, 0x58, 0x35, 0x26,
+	0x17, 0xae, 0x92, 0xab, 0x31, 0xc6, 0x85, 0xb8, 0x61, 0x63, 0xd2, 0xa0, 0x76, 0xe1, 0x8e, 0xd2,
+	0x89, 0x4c, 0x02, 0x0c, 0x99, 0x19, 0x90, 0xbe, 0x92, 0xcf, 0xe4, 0x33, 0xf8, 0x0c, 0x86, 0x99,
+	0xa9, 0x35, 0x69, 0x93, 0x36, 0x5d, 0x7d, 0x0c, 0xf3, 0x3b, 0x07, 0xce, 0xcc, 0x81, 0xc7, 0x03,
+	0xab, 0xfb, 0x86, 0x44, 0x1d, 0x67, 0x92, 0xa1, 0x2b, 0x35, 0x1a, 0x22, 0xf9, 0x36, 0xd2, 0x1b,
+	0x37, 0xef, 0x7f, 0x50, 0x59, 0xf5, 0xeb, 0xa8, 0x64, 0x4d, 0x5c, 0xb3, 0x9e, 0x8a, 0xbb, 0xe4,
+	0xee, 0xd5, 0xbb, 0xfb, 0x78, 0x4f, 0xc6, 0x45, 0x47, 0xe3, 0x81, 0x94, 0x92, 0xf1, 0x64, 0x37,
+	0xb5, 0x5f, 0xf8, 0x0d, 0xfc, 0x2f, 0x5d, 0x45, 0x38, 0x41, 0x09, 0xf8, 0x25, 0x69, 0x25, 0xe1,
+	0xd8, 0x9e, 0xdb, 0x8b, 0x47, 0xaf, 0x5f, 0x44, 0xff, 0x7f, 0xca, 0x88, 0x56, 0x7a, 0xe6, 0x06,
+	0x45, 0xd7, 0xe0, 0xf3, 0x62, 0x43, 0x7b, 0x81, 0x67, 0x73, 0x7b, 0x61, 0xe7, 0x66, 0x15, 0x7e,
+	0x85, 0xe0, 0x53, 0xd1, 0x89, 0xbe, 0xbe, 0xd0, 0xf7, 0x09, 0x78, 0x3f, 0xe9, 0x46, 0x56, 0xc6,
+	0x56, 0x2f, 0xc2, 0x12, 0x9c, 0x94, 0x8d, 0xe8, 0x16, 0x9c, 0x86, 0xb6, 0xe7, 0xd8, 0x4d, 0x9c,
+	0xc2, 0x8b, 0x11, 0xcf, 0xce, 0xc1, 0x8b, 0x31, 0xfc, 0x63, 0x83, 0xfb, 0x99, 0x88, 0xea, 0xb2,
+	0x1f, 0x7f, 0x0b, 0x0f, 0x06, 0xc2, 0x25, 0x2d, 0xc9, 0x74, 0x24, 0xce, 0x29, 0xd9, 0x3f, 0x18,
+	0x21, 0x70, 0x25, 0xa7, 0x02, 0x3b, 0x73, 0x67, 0xe1, 0xe5, 0xea, 0x19, 0xdd, 0x43, 0xd0, 0x32,
+	0xde, 0x14, 0xb5, 0xc0, 0xee, 0x69, 0xaf, 0x1d, 0x3b, 0x05, 0xee, 0x07, 0x81, 0xbd, 0xd3, 0x92,
+	0x89, 0x0b, 0x7f, 0xdb, 0xf0, 0xf0, 0x63, 0xbb, 0x5d, 0xa9, 0x36, 0x4d, 0xa9, 0x85, 0x2a, 0x84,
+	0x49, 0xfd, 0x3c, 0x3a, 0x68, 0x5c, 0xa4, 0x1b, 0x93, 0x59, 0xb9, 0x41, 0xd1, 0x1b, 0x08, 0x4a,
+	0x7d, 0xdd, 0xe6, 0x98, 0x6f, 0x8e, 0xa8, 0x4c, 0x21, 0x32, 0x2b, 0xdf, 0xc1, 0xe8, 0x25, 0x38,
+	0x6b, 0x36, 0x62, 0x47, 0x69, 0xae, 0x8f, 0x68, 0x52, 0x36, 0x66, 0x56, 0x3e, 0x41, 0xe8, 0x16,
+	0xdc, 0x86, 0x88, 0x0a, 0xbb, 0x0a, 0x7e, 0x76, 0x04, 0x9e, 0x6e, 0x2d, 0xb3, 0x72, 0x85, 0xa5,
+	0x01, 0x78, 0xa2, 0x2a, 0x3a, 0x92, 0x7e, 0x80, 0xa7, 0x25, 0x6b, 0x0e, 0xf1, 0xd4, 0xd7, 0x89,
+	0x97, 0xf6, 0x77, 0x5f, 0xbf, 0xf9, 0x35, 0xbb, 0x5a, 0xee, 0x29, 0xbd, 0xbb, 0xf6, 0x95, 0x30,
+	0xf9, 0x3b, 0x00, 0x28, 0xe4, 0x6e, 0x52, 0x87, 0x03, 0x00, 0x00,
 }
@@ -0,0 +1,57 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestNewAnyVolume(t *testing.T) {
+	sphere := &Sphere{Center: vector3.NewVector3(1, 2, 3), Radius: 4}
+	box := NewBoxMinMax(0, 0, 0, 1, 1, 1)
+	a, err := NewAnyVolume(sphere)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, sphere, a.GetSphere())
+	utils.Equals(t, interface{}(sphere), a.Unwrap())
+
+	a, err = NewAnyVolume(*box)
+	utils.Equals(t, nil, err)
+	utils.Equals(t, true, a.GetBox().Max.Equal(*box.Max))
+	utils.Equals(t, (*Sphere)(nil), a.GetSphere())
+
+	_, err = NewAnyVolume(vector3.NewVector3Zero())
+	utils.Equals(t, utils.ErrVolumeUnknownType, err)
+	_, err = NewAnyVolume(nil)
+	utils.Equals(t, utils.ErrVolumeUnknownType, err)
+	utils.Equals(t, nil, (&AnyVolume{}).Unwrap())
+}
+
+func TestAnyVolume_Marshal(t *testing.T) {
+	capsule := &Capsule{Center: vector3.NewVector3(0, 1, 0), Width: 2}
+	mesh := &Mesh{Vertices: []*vector3.Vector3{vector3.NewVector3(0, 0, 0), vector3.NewVector3(1, 0, 0), vector3.NewVector3(0, 1, 0)}, Tris: []int32{0, 1, 2}}
+	volumes, err := NewAnyVolumes(&Sphere{Center: vector3.NewVector3Zero(), Radius: 1}, capsule, NewBoxOfSize(0, 0, 0, 2), mesh)
+	utils.Equals(t, nil, err)
+	_, err = NewAnyVolumes(capsule, 3)
+	utils.Equals(t, utils.ErrVolumeUnknownType, err)
+
+	// Heterogeneous volumes go through the wire and come back with their types
+	for _, v := range volumes {
+		data, err := proto.Marshal(v)
+		utils.Equals(t, nil, err)
+		var got AnyVolume
+		utils.Equals(t, nil, proto.Unmarshal(data, &got))
+		utils.Equals(t, true, proto.Equal(v, &got))
+	}
+	var got AnyVolume
+	data, _ := proto.Marshal(volumes[3])
+	utils.Equals(t, nil, proto.Unmarshal(data, &got))
+	m, ok := got.Unwrap().(*Mesh)
+	utils.Equals(t, true, ok)
+	utils.Equals(t, []int32{0, 1, 2}, m.Tris)
+	data, _ = proto.Marshal(volumes[1])
+	utils.Equals(t, nil, proto.Unmarshal(data, &got))
+	utils.Equals(t, 2., got.GetCapsule().Width)
+	utils.Equals(t, (*Mesh)(nil), got.GetMesh())
+}
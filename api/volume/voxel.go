@@ -0,0 +1,245 @@
+package volume
+
+import (
+	"math"
+	"sort"
+
+	"github.com/louis030195/protometry/api/vector3"
+)
+
+// VoxelMode selects which voxels of a mesh are filled by Mesh.Voxelize
+type VoxelMode int
+
+const (
+	// VoxelSurface fills the voxels crossed by the triangles only
+	VoxelSurface VoxelMode = iota
+	// VoxelSolid also fills the voxels whose center is inside the closed mesh
+	VoxelSolid
+)
+
+// voxelMaxDim is the number of voxels a Morton code can address along each axis
+const voxelMaxDim = 1 << 21
+
+// VoxelGrid is a sparse grid of cubic voxels of side Size starting at Origin, only filled voxels are stored,
+// keyed by the Morton code of their integer coordinates
+type VoxelGrid struct {
+	Origin vector3.Vector3
+	Size   float64
+	Dims   [3]int
+	cells  map[uint64]struct{}
+}
+
+// NewVoxelGrid returns an empty grid of voxels of side size covering the box
+func NewVoxelGrid(bounds Box, size float64) *VoxelGrid {
+	g := &VoxelGrid{Origin: *bounds.Min, Size: size, cells: make(map[uint64]struct{})}
+	extent := bounds.GetSize()
+	for axis := 0; axis < 3; axis++ {
+		n := int(math.Ceil(component(extent, axis)/size - 1e-9))
+		g.Dims[axis] = minInt(maxInt(n, 1), voxelMaxDim)
+	}
+	return g
+}
+
+// Bounds returns the box covered by the grid, it can exceed the box the grid was built from by less than a voxel
+func (g *VoxelGrid) Bounds() Box {
+	return *NewBoxMinMax(
+		g.Origin.X, g.Origin.Y, g.Origin.Z,
+		g.Origin.X+float64(g.Dims[0])*g.Size,
+		g.Origin.Y+float64(g.Dims[1])*g.Size,
+		g.Origin.Z+float64(g.Dims[2])*g.Size,
+	)
+}
+
+// InRange returns whether the integer coordinates are inside the grid
+func (g *VoxelGrid) InRange(i, j, k int) bool {
+	return i >= 0 && j >= 0 && k >= 0 && i < g.Dims[0] && j < g.Dims[1] && k < g.Dims[2]
+}
+
+// Len returns the number of filled voxels
+func (g *VoxelGrid) Len() int {
+	return len(g.cells)
+}
+
+// Get returns whether the voxel is filled
+func (g *VoxelGrid) Get(i, j, k int) bool {
+	if !g.InRange(i, j, k) {
+		return false
+	}
+	_, ok := g.cells[vector3.MortonEncode(uint32(i), uint32(j), uint32(k))]
+	return ok
+}
+
+// Set fills or empties the voxel, voxels out of the grid are ignored
+func (g *VoxelGrid) Set(i, j, k int, filled bool) {
+	if !g.InRange(i, j, k) {
+		return
+	}
+	code := vector3.MortonEncode(uint32(i), uint32(j), uint32(k))
+	if filled {
+		g.cells[code] = struct{}{}
+	} else {
+		delete(g.cells, code)
+	}
+}
+
+// Index returns the integer coordinates of the voxel containing the point, ok is false out of the grid
+func (g *VoxelGrid) Index(p vector3.Vector3) (i, j, k int, ok bool) {
+	i = int(math.Floor((p.X - g.Origin.X) / g.Size))
+	j = int(math.Floor((p.Y - g.Origin.Y) / g.Size))
+	k = int(math.Floor((p.Z - g.Origin.Z) / g.Size))
+	// Points on the max faces of the grid belong to the last voxels
+	if i == g.Dims[0] && p.X == g.Origin.X+float64(g.Dims[0])*g.Size {
+		i--
+	}
+	if j == g.Dims[1] && p.Y == g.Origin.Y+float64(g.Dims[1])*g.Size {
+		j--
+	}
+	if k == g.Dims[2] && p.Z == g.Origin.Z+float64(g.Dims[2])*g.Size {
+		k--
+	}
+	return i, j, k, g.InRange(i, j, k)
+}
+
+// Occupied returns whether the point is in a filled voxel
+func (g *VoxelGrid) Occupied(p vector3.Vector3) bool {
+	i, j, k, ok := g.Index(p)
+	return ok && g.Get(i, j, k)
+}
+
+// Cell returns the box of the voxel
+func (g *VoxelGrid) Cell(i, j, k int) Box {
+	x := g.Origin.X + float64(i)*g.Size
+	y := g.Origin.Y + float64(j)*g.Size
+	z := g.Origin.Z + float64(k)*g.Size
+	return *NewBoxMinMax(x, y, z, x+g.Size, y+g.Size, z+g.Size)
+}
+
+// Voxels returns the integer coordinates of the filled voxels in Morton order
+func (g *VoxelGrid) Voxels() [][3]int {
+	codes := make([]uint64, 0, len(g.cells))
+	for c := range g.cells {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(a, b int) bool { return codes[a] < codes[b] })
+	voxels := make([][3]int, len(codes))
+	for n, c := range codes {
+		i, j, k := vector3.MortonDecode(c)
+		voxels[n] = [3]int{int(i), int(j), int(k)}
+	}
+	return voxels
+}
+
+// Clear empties the filled voxels intersecting the box and returns how many were removed
+func (g *VoxelGrid) Clear(b Box) int {
+	lo, hi := g.indexRange(*b.Min, *b.Max)
+	removed := 0
+	// Ranges over more voxels than are filled visit the filled ones instead
+	span := 1.
+	for axis := 0; axis < 3; axis++ {
+		span *= math.Max(float64(hi[axis]-lo[axis]+1), 0)
+	}
+	if span > float64(len(g.cells)) {
+		for code := range g.cells {
+			i, j, k := vector3.MortonDecode(code)
+			if int(i) >= lo[0] && int(i) <= hi[0] && int(j) >= lo[1] && int(j) <= hi[1] && int(k) >= lo[2] && int(k) <= hi[2] {
+				delete(g.cells, code)
+				removed++
+			}
+		}
+		return removed
+	}
+	for i := lo[0]; i <= hi[0]; i++ {
+		for j := lo[1]; j <= hi[1]; j++ {
+			for k := lo[2]; k <= hi[2]; k++ {
+				if g.Get(i, j, k) {
+					g.Set(i, j, k, false)
+					removed++
+				}
+			}
+		}
+	}
+	return removed
+}
+
+// indexRange returns the voxels overlapping the box from min to max, clamped to the grid
+func (g *VoxelGrid) indexRange(min, max vector3.Vector3) (lo, hi [3]int) {
+	for axis := 0; axis < 3; axis++ {
+		o := component(g.Origin, axis)
+		lo[axis] = int(math.Floor((component(min, axis) - o) / g.Size))
+		hi[axis] = int(math.Floor((component(max, axis) - o) / g.Size))
+		// Faces lying on the max side of the grid belong to the last voxels
+		if lo[axis] == g.Dims[axis] && component(min, axis) == o+float64(g.Dims[axis])*g.Size {
+			lo[axis]--
+		}
+		lo[axis], hi[axis] = maxInt(lo[axis], 0), minInt(hi[axis], g.Dims[axis]-1)
+	}
+	return lo, hi
+}
+
+// triangleBoxOverlap tests a triangle against an axis aligned box with the separating axis theorem
+// (Akenine-Möller), touching counts as overlapping
+func triangleBoxOverlap(center, half, a, b, c vector3.Vector3) bool {
+	v := [3]vector3.Vector3{a.Minus(center), b.Minus(center), c.Minus(center)}
+	e := [3]vector3.Vector3{v[1].Minus(v[0]), v[2].Minus(v[1]), v[0].Minus(v[2])}
+	axes := []vector3.Vector3{
+		*vector3.NewVector3(1, 0, 0), *vector3.NewVector3(0, 1, 0), *vector3.NewVector3(0, 0, 1),
+		*e[0].Cross(e[1]),
+	}
+	for _, u := range axes[:3] {
+		for _, edge := range e {
+			axes = append(axes, *u.Cross(edge))
+		}
+	}
+	for _, axis := range axes {
+		p0, p1, p2 := axis.Dot(v[0]), axis.Dot(v[1]), axis.Dot(v[2])
+		r := half.X*math.Abs(axis.X) + half.Y*math.Abs(axis.Y) + half.Z*math.Abs(axis.Z)
+		if math.Min(p0, math.Min(p1, p2)) > r || math.Max(p0, math.Max(p1, p2)) < -r {
+			return false
+		}
+	}
+	return true
+}
+
+// Voxelize returns a grid of about resolution voxels along the longest side of the mesh bounding box,
+// filled with the voxels crossed by the surface and, in VoxelSolid mode, the ones inside the closed mesh
+func (m *Mesh) Voxelize(resolution int, mode VoxelMode) *VoxelGrid {
+	bounds := NewBoxMinMax(math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1))
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := m.triangle(i)
+		bounds.EncapsulatePoint(a).EncapsulatePoint(b).EncapsulatePoint(c)
+	}
+	if m.triangleCount() == 0 {
+		return NewVoxelGrid(*NewBoxMinMax(0, 0, 0, 0, 0, 0), 1)
+	}
+	if resolution <= 0 {
+		resolution = 1
+	}
+	size := bounds.GetSize()
+	side := math.Max(size.X, math.Max(size.Y, size.Z)) / float64(resolution)
+	if side == 0 {
+		side = 1
+	}
+	g := NewVoxelGrid(*bounds, side)
+
+	half := *vector3.NewVector3(side/2, side/2, side/2)
+	for t := 0; t < m.triangleCount(); t++ {
+		a, b, c := m.triangle(t)
+		lo, hi := g.indexRange(vector3.Min(a, vector3.Min(b, c)), vector3.Max(a, vector3.Max(b, c)))
+		for i := lo[0]; i <= hi[0]; i++ {
+			for j := lo[1]; j <= hi[1]; j++ {
+				for k := lo[2]; k <= hi[2]; k++ {
+					cell := g.Cell(i, j, k)
+					if triangleBoxOverlap(cell.GetCenter(), half, a, b, c) {
+						g.Set(i, j, k, true)
+					}
+				}
+			}
+		}
+	}
+	if mode == VoxelSolid {
+		for _, v := range solidVoxels(m, g.Origin, side, g.Dims) {
+			g.Set(v[0], v[1], v[2], true)
+		}
+	}
+	return g
+}
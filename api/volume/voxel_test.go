@@ -0,0 +1,77 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/internal/utils"
+)
+
+func TestMesh_Voxelize(t *testing.T) {
+	m := NewMeshSquareCuboid(1, true)
+	s := m.Voxelize(4, VoxelSurface)
+	utils.Equals(t, [3]int{4, 4, 4}, s.Dims)
+	utils.Equals(t, 0.25, s.Size)
+	// The shell of the cube, the inner 2x2x2 block is empty
+	utils.Equals(t, 64-8, s.Len())
+	utils.Equals(t, false, s.Get(1, 1, 1))
+	utils.Equals(t, true, s.Get(0, 2, 3))
+
+	v := m.Voxelize(4, VoxelSolid)
+	utils.Equals(t, 64, v.Len())
+	utils.Equals(t, true, v.Bounds().Equal(*NewBoxMinMax(-0.5, -0.5, -0.5, 0.5, 0.5, 0.5)))
+
+	// The voxels of a sphere cover it, with up to a voxel in excess on the surface
+	sphere := testSphere(1, 24, 48)
+	v = sphere.Voxelize(32, VoxelSolid)
+	volume := float64(v.Len()) * math.Pow(v.Size, 3)
+	utils.Equals(t, true, volume > sphere.GetVolume() && volume < 1.2*sphere.GetVolume())
+	utils.Equals(t, true, v.Occupied(*vector3.NewVector3Zero()))
+	utils.Equals(t, false, v.Occupied(*vector3.NewVector3(0.9, 0.9, 0.9)))
+	utils.Equals(t, false, sphere.Voxelize(32, VoxelSurface).Occupied(*vector3.NewVector3Zero()))
+
+	utils.Equals(t, 0, (&Mesh{}).Voxelize(8, VoxelSolid).Len())
+}
+
+func TestMesh_VoxelizeFlat(t *testing.T) {
+	g := testGrid(4).Voxelize(8, VoxelSurface)
+	utils.Equals(t, [3]int{8, 8, 1}, g.Dims)
+	utils.Equals(t, 64, g.Len())
+}
+
+func TestVoxelGrid(t *testing.T) {
+	g := NewVoxelGrid(*NewBoxMinMax(0, 0, 0, 4, 2, 1), 0.5)
+	utils.Equals(t, [3]int{8, 4, 2}, g.Dims)
+	g.Set(7, 3, 1, true)
+	g.Set(8, 0, 0, true)
+	g.Set(-1, 0, 0, true)
+	utils.Equals(t, 1, g.Len())
+	i, j, k, ok := g.Index(*vector3.NewVector3(4, 2, 1))
+	utils.Equals(t, [3]int{7, 3, 1}, [3]int{i, j, k})
+	utils.Equals(t, true, ok)
+	_, _, _, ok = g.Index(*vector3.NewVector3(4.1, 0, 0))
+	utils.Equals(t, false, ok)
+	utils.Equals(t, true, g.Occupied(*vector3.NewVector3(3.9, 1.9, 0.9)))
+	utils.Equals(t, true, g.Cell(7, 3, 1).Equal(*NewBoxMinMax(3.5, 1.5, 0.5, 4, 2, 1)))
+
+	for i := 0; i < 8; i++ {
+		g.Set(i, 0, 0, true)
+	}
+	// Morton order
+	utils.Equals(t, [][3]int{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}, {3, 0, 0}}, g.Voxels()[:4])
+	utils.Equals(t, 3, g.Clear(*NewBoxMinMax(0.6, 0, 0, 1.9, 0.1, 0.1)))
+	utils.Equals(t, 6, g.Len())
+	utils.Equals(t, false, g.Get(2, 0, 0))
+
+	// A sparse grid cleared over a range of a billion voxels visits the filled ones only
+	sparse := NewVoxelGrid(*NewBoxMinMax(0, 0, 0, 1024, 1024, 1024), 1)
+	for _, v := range [][3]int{{0, 0, 0}, {500, 20, 1000}, {1023, 1023, 1023}, {600, 20, 1000}} {
+		sparse.Set(v[0], v[1], v[2], true)
+	}
+	utils.Equals(t, 2, sparse.Clear(*NewBoxMinMax(0, 0, 0, 550, 1024, 1024)))
+	utils.Equals(t, [][3]int{{600, 20, 1000}, {1023, 1023, 1023}}, sparse.Voxels())
+	utils.Equals(t, 0, sparse.Clear(*NewBoxMinMax(2000, 0, 0, 3000, 1, 1)))
+	utils.Equals(t, 2, sparse.Clear(sparse.Bounds()))
+	utils.Equals(t, 0, sparse.Len())
+}
@@ -0,0 +1,176 @@
+package world
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the World service in memory, the entities indexed in a volume.BoxTree
+// It is safe for concurrent use, queries running in parallel
+type Server struct {
+	UnimplementedWorldServer
+	mu   sync.RWMutex
+	tree *volume.BoxTree
+}
+
+// NewServer returns a server of an empty world
+func NewServer() *Server {
+	return &Server{tree: volume.NewBoxTree()}
+}
+
+func invalid() error {
+	return status.Error(codes.InvalidArgument, utils.ErrWorldInvalidRequest.Error())
+}
+
+// validVector returns whether the vector is set and finite
+func validVector(v *vector3.Vector3) bool {
+	return v != nil && finite(v.X) && finite(v.Y) && finite(v.Z)
+}
+
+func finite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// validBox returns whether the box is set and not inverted
+func validBox(b *volume.Box) bool {
+	return b != nil && validVector(b.Min) && validVector(b.Max) &&
+		b.Min.X <= b.Max.X && b.Min.Y <= b.Max.Y && b.Min.Z <= b.Max.Z
+}
+
+func entity(id uint64, b volume.Box) *Entity {
+	return &Entity{Id: id, Bounds: &b}
+}
+
+// sortedEntities returns the entities in increasing id
+func sortedEntities(entities []*Entity) []*Entity {
+	sort.Slice(entities, func(i, j int) bool { return entities[i].Id < entities[j].Id })
+	return entities
+}
+
+// Insert adds an entity, failing with ALREADY_EXISTS if its id is taken
+func (s *Server) Insert(_ context.Context, r *InsertRequest) (*InsertResponse, error) {
+	if r.Entity == nil || !validBox(r.Entity.Bounds) {
+		return nil, invalid()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tree.Get(r.Entity.Id); ok {
+		return nil, status.Error(codes.AlreadyExists, utils.ErrWorldEntityExists.Error())
+	}
+	s.tree.Insert(r.Entity.Id, *r.Entity.Bounds)
+	return &InsertResponse{}, nil
+}
+
+// Move changes the bounds of an entity, failing with NOT_FOUND if it doesn't exist
+func (s *Server) Move(_ context.Context, r *MoveRequest) (*MoveResponse, error) {
+	if r.Entity == nil || !validBox(r.Entity.Bounds) {
+		return nil, invalid()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tree.Get(r.Entity.Id); !ok {
+		return nil, status.Error(codes.NotFound, utils.ErrWorldEntityNotFound.Error())
+	}
+	s.tree.Insert(r.Entity.Id, *r.Entity.Bounds)
+	return &MoveResponse{}, nil
+}
+
+// Remove removes an entity, failing with NOT_FOUND if it doesn't exist
+func (s *Server) Remove(_ context.Context, r *RemoveRequest) (*RemoveResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.tree.Remove(r.Id) {
+		return nil, status.Error(codes.NotFound, utils.ErrWorldEntityNotFound.Error())
+	}
+	return &RemoveResponse{}, nil
+}
+
+// QueryBox returns the entities whose bounds intersect the box, touching included
+func (s *Server) QueryBox(_ context.Context, r *QueryBoxRequest) (*QueryResponse, error) {
+	if !validBox(r.Box) {
+		return nil, invalid()
+	}
+	var entities []*Entity
+	s.mu.RLock()
+	s.tree.Query(*r.Box, func(id uint64, b volume.Box) bool {
+		entities = append(entities, entity(id, b))
+		return true
+	})
+	s.mu.RUnlock()
+	return &QueryResponse{Entities: sortedEntities(entities)}, nil
+}
+
+// QuerySphere returns the entities whose bounds intersect the sphere, touching included
+func (s *Server) QuerySphere(_ context.Context, r *QuerySphereRequest) (*QueryResponse, error) {
+	if r.Sphere == nil || !validVector(r.Sphere.Center) || !(r.Sphere.Radius >= 0) {
+		return nil, invalid()
+	}
+	var entities []*Entity
+	s.mu.RLock()
+	s.tree.QuerySphere(*r.Sphere.Center, r.Sphere.Radius, func(id uint64, b volume.Box) bool {
+		entities = append(entities, entity(id, b))
+		return true
+	})
+	s.mu.RUnlock()
+	return &QueryResponse{Entities: sortedEntities(entities)}, nil
+}
+
+// sortedHits returns the hits the closest first, then in increasing id
+func sortedHits(hits []*Hit) []*Hit {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Distance != hits[j].Distance {
+			return hits[i].Distance < hits[j].Distance
+		}
+		return hits[i].Entity.Id < hits[j].Entity.Id
+	})
+	return hits
+}
+
+// Raycast returns the entities the ray crosses within the max distance, with the distance where it
+// enters them, 0 for the ones holding the origin. The direction is normalized so distances are lengths
+func (s *Server) Raycast(_ context.Context, r *RaycastRequest) (*HitsResponse, error) {
+	if !validVector(r.Origin) || !validVector(r.Direction) || !(r.MaxDistance >= 0) {
+		return nil, invalid()
+	}
+	// A direction too short or too long for its squared length would normalize to zero or NaN
+	if n := r.Direction.Norm(); n == 0 || math.IsInf(n, 1) {
+		return nil, invalid()
+	}
+	maxDistance := r.MaxDistance
+	if maxDistance == 0 {
+		maxDistance = math.Inf(1)
+	}
+	var hits []*Hit
+	s.mu.RLock()
+	s.tree.Raycast(*r.Origin, r.Direction.Normalize(), maxDistance, func(id uint64, b volume.Box, distance float64) bool {
+		hits = append(hits, &Hit{Entity: entity(id, b), Distance: distance})
+		return true
+	})
+	s.mu.RUnlock()
+	return &HitsResponse{Hits: sortedHits(hits)}, nil
+}
+
+// Nearest returns the k entities whose bounds are the closest to the point, with their distance, 0 for the
+// ones holding it
+func (s *Server) Nearest(_ context.Context, r *NearestRequest) (*HitsResponse, error) {
+	if !validVector(r.Point) {
+		return nil, invalid()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids, distances := s.tree.Nearest(*r.Point, int(r.K))
+	hits := make([]*Hit, len(ids))
+	for i, id := range ids {
+		b, _ := s.tree.Get(id)
+		hits[i] = &Hit{Entity: entity(id, b), Distance: distances[i]}
+	}
+	return &HitsResponse{Hits: hits}, nil
+}
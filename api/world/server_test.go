@@ -0,0 +1,167 @@
+package world
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/louis030195/protometry/api/vector3"
+	"github.com/louis030195/protometry/api/volume"
+	"github.com/louis030195/protometry/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves a new world in memory, returning a client of it and a function stopping both
+func dial(t *testing.T) (WorldClient, func()) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	RegisterWorldServer(server, NewServer())
+	go server.Serve(listener)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	utils.Equals(t, nil, err)
+	return NewWorldClient(conn), func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func cube(id uint64, x, y, z float64) *Entity {
+	return &Entity{Id: id, Bounds: volume.NewBoxMinMax(x, y, z, x+1, y+1, z+1)}
+}
+
+func ids(entities []*Entity) []uint64 {
+	out := make([]uint64, len(entities))
+	for i, e := range entities {
+		out[i] = e.Id
+	}
+	return out
+}
+
+func hitIds(hits []*Hit) []uint64 {
+	out := make([]uint64, len(hits))
+	for i, h := range hits {
+		out[i] = h.Entity.Id
+	}
+	return out
+}
+
+func TestServer(t *testing.T) {
+	client, stop := dial(t)
+	defer stop()
+	ctx := context.Background()
+	for i := uint64(0); i < 5; i++ {
+		_, err := client.Insert(ctx, &InsertRequest{Entity: cube(i, float64(i)*2, 0, 0)})
+		utils.Equals(t, nil, err)
+	}
+	_, err := client.Insert(ctx, &InsertRequest{Entity: cube(0, 10, 10, 10)})
+	utils.Equals(t, codes.AlreadyExists, status.Code(err))
+
+	q, err := client.QueryBox(ctx, &QueryBoxRequest{Box: volume.NewBoxMinMax(1.5, 0, 0, 4.5, 1, 1)})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []uint64{1, 2}, ids(q.Entities))
+	utils.Equals(t, true, q.Entities[0].Bounds.Min.Equal(*vector3.NewVector3(2, 0, 0)))
+
+	q, err = client.QuerySphere(ctx, &QuerySphereRequest{Sphere: &volume.Sphere{Center: vector3.NewVector3(4.5, 2, 0.5), Radius: 1.5}})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []uint64{2}, ids(q.Entities))
+
+	// Entering 0 from inside, 1 and 2 in front, 3 past the max distance
+	h, err := client.Raycast(ctx, &RaycastRequest{
+		Origin:      vector3.NewVector3(0.5, 0.5, 0.5),
+		Direction:   vector3.NewVector3(3, 0, 0),
+		MaxDistance: 5,
+	})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []uint64{0, 1, 2}, hitIds(h.Hits))
+	utils.Equals(t, []float64{0, 1.5, 3.5}, []float64{h.Hits[0].Distance, h.Hits[1].Distance, h.Hits[2].Distance})
+	h, err = client.Raycast(ctx, &RaycastRequest{Origin: vector3.NewVector3(-1, 0.5, 0.5), Direction: vector3.NewVector3(1, 0, 0)})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []uint64{0, 1, 2, 3, 4}, hitIds(h.Hits))
+
+	h, err = client.Nearest(ctx, &NearestRequest{Point: vector3.NewVector3(6.5, 3, 0.5), K: 2})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []uint64{3, 2}, hitIds(h.Hits))
+	utils.Equals(t, 2., h.Hits[0].Distance)
+
+	_, err = client.Move(ctx, &MoveRequest{Entity: cube(3, 100, 0, 0)})
+	utils.Equals(t, nil, err)
+	_, err = client.Remove(ctx, &RemoveRequest{Id: 2})
+	utils.Equals(t, nil, err)
+	h, err = client.Nearest(ctx, &NearestRequest{Point: vector3.NewVector3(6.5, 3, 0.5), K: 2})
+	utils.Equals(t, nil, err)
+	utils.Equals(t, []uint64{4, 1}, hitIds(h.Hits))
+
+	_, err = client.Move(ctx, &MoveRequest{Entity: cube(2, 0, 0, 0)})
+	utils.Equals(t, codes.NotFound, status.Code(err))
+	_, err = client.Remove(ctx, &RemoveRequest{Id: 2})
+	utils.Equals(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_InvalidArgument(t *testing.T) {
+	client, stop := dial(t)
+	defer stop()
+	ctx := context.Background()
+	inf := math.Inf(1)
+	for _, call := range []func() error{
+		func() error { _, err := client.Insert(ctx, &InsertRequest{}); return err },
+		func() error { _, err := client.Insert(ctx, &InsertRequest{Entity: &Entity{Id: 1}}); return err },
+		func() error {
+			_, err := client.Move(ctx, &MoveRequest{Entity: &Entity{Id: 1, Bounds: volume.NewBoxMinMax(1, 0, 0, 0, 1, 1)}})
+			return err
+		},
+		func() error {
+			_, err := client.QueryBox(ctx, &QueryBoxRequest{Box: &volume.Box{Min: vector3.NewVector3Zero()}})
+			return err
+		},
+		func() error {
+			_, err := client.QuerySphere(ctx, &QuerySphereRequest{Sphere: &volume.Sphere{Center: vector3.NewVector3Zero(), Radius: -1}})
+			return err
+		},
+		func() error {
+			_, err := client.Raycast(ctx, &RaycastRequest{Origin: vector3.NewVector3Zero(), Direction: vector3.NewVector3Zero()})
+			return err
+		},
+		func() error { _, err := client.Nearest(ctx, &NearestRequest{K: 1}); return err },
+		// Infinite positions and directions
+		func() error {
+			_, err := client.Insert(ctx, &InsertRequest{Entity: &Entity{Id: 1, Bounds: volume.NewBoxMinMax(0, 0, 0, inf, 1, 1)}})
+			return err
+		},
+		func() error {
+			_, err := client.Move(ctx, &MoveRequest{Entity: &Entity{Id: 1, Bounds: volume.NewBoxMinMax(-inf, 0, 0, 1, 1, 1)}})
+			return err
+		},
+		func() error {
+			_, err := client.QueryBox(ctx, &QueryBoxRequest{Box: volume.NewBoxMinMax(-inf, -inf, -inf, inf, inf, inf)})
+			return err
+		},
+		func() error {
+			_, err := client.QuerySphere(ctx, &QuerySphereRequest{Sphere: &volume.Sphere{Center: vector3.NewVector3(0, inf, 0), Radius: 1}})
+			return err
+		},
+		func() error {
+			_, err := client.Raycast(ctx, &RaycastRequest{Origin: vector3.NewVector3(inf, 0, 0), Direction: vector3.NewVector3(1, 0, 0)})
+			return err
+		},
+		func() error {
+			_, err := client.Raycast(ctx, &RaycastRequest{Origin: vector3.NewVector3Zero(), Direction: vector3.NewVector3(inf, 0, 0)})
+			return err
+		},
+		func() error {
+			_, err := client.Raycast(ctx, &RaycastRequest{Origin: vector3.NewVector3Zero(), Direction: vector3.NewVector3(1e200, 0, 0)})
+			return err
+		},
+		func() error {
+			_, err := client.Nearest(ctx, &NearestRequest{Point: vector3.NewVector3(0, 0, -inf), K: 1})
+			return err
+		},
+	} {
+		utils.Equals(t, codes.InvalidArgument, status.Code(call()))
+	}
+}
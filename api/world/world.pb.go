@@ -0,0 +1,966 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: world.proto
+
+package world
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	vector3 "github.com/louis030195/protometry/api/vector3"
+	volume "github.com/louis030195/protometry/api/volume"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Entity is an object of the world with its bounds
+type Entity struct {
+	Id                   uint64      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bounds               *volume.Box `protobuf:"bytes,2,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Entity) Reset()         { *m = Entity{} }
+func (m *Entity) String() string { return proto.CompactTextString(m) }
+func (*Entity) ProtoMessage()    {}
+func (*Entity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{0}
+}
+
+func (m *Entity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Entity.Unmarshal(m, b)
+}
+func (m *Entity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Entity.Marshal(b, m, deterministic)
+}
+func (m *Entity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Entity.Merge(m, src)
+}
+func (m *Entity) XXX_Size() int {
+	return xxx_messageInfo_Entity.Size(m)
+}
+func (m *Entity) XXX_DiscardUnknown() {
+	xxx_messageInfo_Entity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Entity proto.InternalMessageInfo
+
+func (m *Entity) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Entity) GetBounds() *volume.Box {
+	if m != nil {
+		return m.Bounds
+	}
+	return nil
+}
+
+type InsertRequest struct {
+	Entity               *Entity  `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InsertRequest) Reset()         { *m = InsertRequest{} }
+func (m *InsertRequest) String() string { return proto.CompactTextString(m) }
+func (*InsertRequest) ProtoMessage()    {}
+func (*InsertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{1}
+}
+
+func (m *InsertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InsertRequest.Unmarshal(m, b)
+}
+func (m *InsertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InsertRequest.Marshal(b, m, deterministic)
+}
+func (m *InsertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InsertRequest.Merge(m, src)
+}
+func (m *InsertRequest) XXX_Size() int {
+	return xxx_messageInfo_InsertRequest.Size(m)
+}
+func (m *InsertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InsertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InsertRequest proto.InternalMessageInfo
+
+func (m *InsertRequest) GetEntity() *Entity {
+	if m != nil {
+		return m.Entity
+	}
+	return nil
+}
+
+type InsertResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InsertResponse) Reset()         { *m = InsertResponse{} }
+func (m *InsertResponse) String() string { return proto.CompactTextString(m) }
+func (*InsertResponse) ProtoMessage()    {}
+func (*InsertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{2}
+}
+
+func (m *InsertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InsertResponse.Unmarshal(m, b)
+}
+func (m *InsertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InsertResponse.Marshal(b, m, deterministic)
+}
+func (m *InsertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InsertResponse.Merge(m, src)
+}
+func (m *InsertResponse) XXX_Size() int {
+	return xxx_messageInfo_InsertResponse.Size(m)
+}
+func (m *InsertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InsertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InsertResponse proto.InternalMessageInfo
+
+type MoveRequest struct {
+	Entity               *Entity  `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveRequest) Reset()         { *m = MoveRequest{} }
+func (m *MoveRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveRequest) ProtoMessage()    {}
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{3}
+}
+
+func (m *MoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveRequest.Unmarshal(m, b)
+}
+func (m *MoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveRequest.Marshal(b, m, deterministic)
+}
+func (m *MoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveRequest.Merge(m, src)
+}
+func (m *MoveRequest) XXX_Size() int {
+	return xxx_messageInfo_MoveRequest.Size(m)
+}
+func (m *MoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveRequest proto.InternalMessageInfo
+
+func (m *MoveRequest) GetEntity() *Entity {
+	if m != nil {
+		return m.Entity
+	}
+	return nil
+}
+
+type MoveResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveResponse) Reset()         { *m = MoveResponse{} }
+func (m *MoveResponse) String() string { return proto.CompactTextString(m) }
+func (*MoveResponse) ProtoMessage()    {}
+func (*MoveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{4}
+}
+
+func (m *MoveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveResponse.Unmarshal(m, b)
+}
+func (m *MoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveResponse.Marshal(b, m, deterministic)
+}
+func (m *MoveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveResponse.Merge(m, src)
+}
+func (m *MoveResponse) XXX_Size() int {
+	return xxx_messageInfo_MoveResponse.Size(m)
+}
+func (m *MoveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveResponse proto.InternalMessageInfo
+
+type RemoveRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRequest) Reset()         { *m = RemoveRequest{} }
+func (m *RemoveRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveRequest) ProtoMessage()    {}
+func (*RemoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{5}
+}
+
+func (m *RemoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRequest.Unmarshal(m, b)
+}
+func (m *RemoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRequest.Merge(m, src)
+}
+func (m *RemoveRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveRequest.Size(m)
+}
+func (m *RemoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRequest proto.InternalMessageInfo
+
+func (m *RemoveRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type RemoveResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveResponse) Reset()         { *m = RemoveResponse{} }
+func (m *RemoveResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveResponse) ProtoMessage()    {}
+func (*RemoveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{6}
+}
+
+func (m *RemoveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveResponse.Unmarshal(m, b)
+}
+func (m *RemoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveResponse.Merge(m, src)
+}
+func (m *RemoveResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveResponse.Size(m)
+}
+func (m *RemoveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveResponse proto.InternalMessageInfo
+
+type QueryBoxRequest struct {
+	Box                  *volume.Box `protobuf:"bytes,1,opt,name=box,proto3" json:"box,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *QueryBoxRequest) Reset()         { *m = QueryBoxRequest{} }
+func (m *QueryBoxRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryBoxRequest) ProtoMessage()    {}
+func (*QueryBoxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{7}
+}
+
+func (m *QueryBoxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryBoxRequest.Unmarshal(m, b)
+}
+func (m *QueryBoxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryBoxRequest.Marshal(b, m, deterministic)
+}
+func (m *QueryBoxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryBoxRequest.Merge(m, src)
+}
+func (m *QueryBoxRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryBoxRequest.Size(m)
+}
+func (m *QueryBoxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryBoxRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryBoxRequest proto.InternalMessageInfo
+
+func (m *QueryBoxRequest) GetBox() *volume.Box {
+	if m != nil {
+		return m.Box
+	}
+	return nil
+}
+
+type QuerySphereRequest struct {
+	Sphere               *volume.Sphere `protobuf:"bytes,1,opt,name=sphere,proto3" json:"sphere,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *QuerySphereRequest) Reset()         { *m = QuerySphereRequest{} }
+func (m *QuerySphereRequest) String() string { return proto.CompactTextString(m) }
+func (*QuerySphereRequest) ProtoMessage()    {}
+func (*QuerySphereRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{8}
+}
+
+func (m *QuerySphereRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuerySphereRequest.Unmarshal(m, b)
+}
+func (m *QuerySphereRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuerySphereRequest.Marshal(b, m, deterministic)
+}
+func (m *QuerySphereRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuerySphereRequest.Merge(m, src)
+}
+func (m *QuerySphereRequest) XXX_Size() int {
+	return xxx_messageInfo_QuerySphereRequest.Size(m)
+}
+func (m *QuerySphereRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuerySphereRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuerySphereRequest proto.InternalMessageInfo
+
+func (m *QuerySphereRequest) GetSphere() *volume.Sphere {
+	if m != nil {
+		return m.Sphere
+	}
+	return nil
+}
+
+// QueryResponse holds the entities found, in increasing id
+type QueryResponse struct {
+	Entities             []*Entity `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
+func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryResponse) ProtoMessage()    {}
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{9}
+}
+
+func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
+}
+func (m *QueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryResponse.Marshal(b, m, deterministic)
+}
+func (m *QueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResponse.Merge(m, src)
+}
+func (m *QueryResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryResponse.Size(m)
+}
+func (m *QueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResponse proto.InternalMessageInfo
+
+func (m *QueryResponse) GetEntities() []*Entity {
+	if m != nil {
+		return m.Entities
+	}
+	return nil
+}
+
+type RaycastRequest struct {
+	Origin               *vector3.Vector3 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Direction            *vector3.Vector3 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	MaxDistance          float64          `protobuf:"fixed64,3,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RaycastRequest) Reset()         { *m = RaycastRequest{} }
+func (m *RaycastRequest) String() string { return proto.CompactTextString(m) }
+func (*RaycastRequest) ProtoMessage()    {}
+func (*RaycastRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{10}
+}
+
+func (m *RaycastRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaycastRequest.Unmarshal(m, b)
+}
+func (m *RaycastRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaycastRequest.Marshal(b, m, deterministic)
+}
+func (m *RaycastRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaycastRequest.Merge(m, src)
+}
+func (m *RaycastRequest) XXX_Size() int {
+	return xxx_messageInfo_RaycastRequest.Size(m)
+}
+func (m *RaycastRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaycastRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaycastRequest proto.InternalMessageInfo
+
+func (m *RaycastRequest) GetOrigin() *vector3.Vector3 {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+func (m *RaycastRequest) GetDirection() *vector3.Vector3 {
+	if m != nil {
+		return m.Direction
+	}
+	return nil
+}
+
+func (m *RaycastRequest) GetMaxDistance() float64 {
+	if m != nil {
+		return m.MaxDistance
+	}
+	return 0
+}
+
+type NearestRequest struct {
+	Point                *vector3.Vector3 `protobuf:"bytes,1,opt,name=point,proto3" json:"point,omitempty"`
+	K                    uint32           `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *NearestRequest) Reset()         { *m = NearestRequest{} }
+func (m *NearestRequest) String() string { return proto.CompactTextString(m) }
+func (*NearestRequest) ProtoMessage()    {}
+func (*NearestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{11}
+}
+
+func (m *NearestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NearestRequest.Unmarshal(m, b)
+}
+func (m *NearestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NearestRequest.Marshal(b, m, deterministic)
+}
+func (m *NearestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NearestRequest.Merge(m, src)
+}
+func (m *NearestRequest) XXX_Size() int {
+	return xxx_messageInfo_NearestRequest.Size(m)
+}
+func (m *NearestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NearestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NearestRequest proto.InternalMessageInfo
+
+func (m *NearestRequest) GetPoint() *vector3.Vector3 {
+	if m != nil {
+		return m.Point
+	}
+	return nil
+}
+
+func (m *NearestRequest) GetK() uint32 {
+	if m != nil {
+		return m.K
+	}
+	return 0
+}
+
+// Hit is an entity found at a distance, from the origin of a ray or from a point
+type Hit struct {
+	Entity               *Entity  `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Distance             float64  `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Hit) Reset()         { *m = Hit{} }
+func (m *Hit) String() string { return proto.CompactTextString(m) }
+func (*Hit) ProtoMessage()    {}
+func (*Hit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{12}
+}
+
+func (m *Hit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Hit.Unmarshal(m, b)
+}
+func (m *Hit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Hit.Marshal(b, m, deterministic)
+}
+func (m *Hit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Hit.Merge(m, src)
+}
+func (m *Hit) XXX_Size() int {
+	return xxx_messageInfo_Hit.Size(m)
+}
+func (m *Hit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Hit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Hit proto.InternalMessageInfo
+
+func (m *Hit) GetEntity() *Entity {
+	if m != nil {
+		return m.Entity
+	}
+	return nil
+}
+
+func (m *Hit) GetDistance() float64 {
+	if m != nil {
+		return m.Distance
+	}
+	return 0
+}
+
+// HitsResponse holds the entities found, the closest first
+type HitsResponse struct {
+	Hits                 []*Hit   `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HitsResponse) Reset()         { *m = HitsResponse{} }
+func (m *HitsResponse) String() string { return proto.CompactTextString(m) }
+func (*HitsResponse) ProtoMessage()    {}
+func (*HitsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b509c40833d40ff2, []int{13}
+}
+
+func (m *HitsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HitsResponse.Unmarshal(m, b)
+}
+func (m *HitsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HitsResponse.Marshal(b, m, deterministic)
+}
+func (m *HitsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HitsResponse.Merge(m, src)
+}
+func (m *HitsResponse) XXX_Size() int {
+	return xxx_messageInfo_HitsResponse.Size(m)
+}
+func (m *HitsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HitsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HitsResponse proto.InternalMessageInfo
+
+func (m *HitsResponse) GetHits() []*Hit {
+	if m != nil {
+		return m.Hits
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Entity)(nil), "protometry.world.Entity")
+	proto.RegisterType((*InsertRequest)(nil), "protometry.world.InsertRequest")
+	proto.RegisterType((*InsertResponse)(nil), "protometry.world.InsertResponse")
+	proto.RegisterType((*MoveRequest)(nil), "protometry.world.MoveRequest")
+	proto.RegisterType((*MoveResponse)(nil), "protometry.world.MoveResponse")
+	proto.RegisterType((*RemoveRequest)(nil), "protometry.world.RemoveRequest")
+	proto.RegisterType((*RemoveResponse)(nil), "protometry.world.RemoveResponse")
+	proto.RegisterType((*QueryBoxRequest)(nil), "protometry.world.QueryBoxRequest")
+	proto.RegisterType((*QuerySphereRequest)(nil), "protometry.world.QuerySphereRequest")
+	proto.RegisterType((*QueryResponse)(nil), "protometry.world.QueryResponse")
+	proto.RegisterType((*RaycastRequest)(nil), "protometry.world.RaycastRequest")
+	proto.RegisterType((*NearestRequest)(nil), "protometry.world.NearestRequest")
+	proto.RegisterType((*Hit)(nil), "protometry.world.Hit")
+	proto.RegisterType((*HitsResponse)(nil), "protometry.world.HitsResponse")
+}
+
+func init() {
+	proto.RegisterFile("world.proto", fileDescriptor_b509c40833d40ff2)
+}
+
+var fileDescriptor_b509c40833d40ff2 = []byte{
+	// 596 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x4f, 0x8f, 0xd2, 0x40,
+	0x18, 0xc6, 0xd3, 0x02, 0x5d, 0x7c, 0x0b, 0x48, 0x26, 0x6a, 0x6a, 0x8d, 0xd2, 0x6d, 0x3c, 0xe0,
+	0xa5, 0xfc, 0xd3, 0x03, 0xd9, 0x83, 0x91, 0x48, 0xc4, 0x10, 0x37, 0xbb, 0x5d, 0xa3, 0x89, 0x17,
+	0x53, 0xda, 0xc9, 0x32, 0x59, 0xda, 0xc1, 0x76, 0xba, 0xc2, 0xe7, 0xf1, 0x62, 0xfc, 0x94, 0x86,
+	0xe9, 0xb4, 0x50, 0xfe, 0x2c, 0x9b, 0x3d, 0x4d, 0x3a, 0x7d, 0xde, 0xdf, 0x3c, 0xef, 0xcc, 0xfb,
+	0x80, 0xfa, 0x9b, 0x86, 0x33, 0xcf, 0x9a, 0x87, 0x94, 0x51, 0x54, 0xe7, 0x8b, 0x8f, 0x59, 0xb8,
+	0xb4, 0xf8, 0xbe, 0x7e, 0x76, 0x4d, 0xd8, 0x34, 0x9e, 0x58, 0x2e, 0xf5, 0x5b, 0x33, 0x1a, 0x93,
+	0xa8, 0xdd, 0x6b, 0x77, 0xfa, 0xef, 0x5a, 0x6b, 0x61, 0xcb, 0x99, 0x93, 0xd6, 0x2d, 0x76, 0x19,
+	0x0d, 0x7b, 0xe9, 0x9a, 0xe0, 0xf4, 0xfe, 0x3d, 0x8b, 0xe9, 0x2c, 0xf6, 0xb1, 0x58, 0x92, 0x52,
+	0x73, 0x04, 0xca, 0x30, 0x60, 0x84, 0x2d, 0x51, 0x0d, 0x64, 0xe2, 0x69, 0x92, 0x21, 0x35, 0x8b,
+	0xb6, 0x4c, 0x3c, 0x64, 0x81, 0x32, 0xa1, 0x71, 0xe0, 0x45, 0x9a, 0x6c, 0x48, 0x4d, 0xb5, 0xfb,
+	0xcc, 0xda, 0x30, 0x2d, 0x18, 0x03, 0xba, 0xb0, 0x85, 0xca, 0xfc, 0x00, 0xd5, 0xcf, 0x41, 0x84,
+	0x43, 0x66, 0xe3, 0x5f, 0x31, 0x8e, 0x18, 0x6a, 0x83, 0x82, 0x39, 0x9a, 0x43, 0xd5, 0xae, 0x66,
+	0x6d, 0x77, 0x6d, 0x25, 0x47, 0xdb, 0x42, 0x67, 0xd6, 0xa1, 0x96, 0x22, 0xa2, 0x39, 0x0d, 0x22,
+	0x6c, 0xbe, 0x07, 0xf5, 0x0b, 0xbd, 0xc5, 0x0f, 0x47, 0xd6, 0xa0, 0x92, 0x00, 0x04, 0xb0, 0x01,
+	0x55, 0x1b, 0xfb, 0x1b, 0xc8, 0xad, 0xb6, 0x57, 0x1e, 0x52, 0x81, 0x28, 0x39, 0x83, 0xc7, 0x97,
+	0x31, 0x0e, 0x97, 0xab, 0x66, 0x45, 0x51, 0x13, 0x0a, 0x13, 0xba, 0xd0, 0xa4, 0x3b, 0x2f, 0x66,
+	0x25, 0x31, 0x3f, 0x01, 0xe2, 0xc5, 0x57, 0xf3, 0x29, 0x0e, 0xb3, 0x43, 0x3b, 0xa0, 0x44, 0x7c,
+	0x43, 0x20, 0x9e, 0xef, 0x41, 0x88, 0x0a, 0x21, 0x34, 0x87, 0x50, 0xe5, 0xa0, 0xd4, 0x16, 0x7a,
+	0x0b, 0x65, 0xde, 0x23, 0xc1, 0x91, 0x26, 0x19, 0x85, 0x3b, 0x6f, 0x23, 0x53, 0x9a, 0x7f, 0x24,
+	0xa8, 0xd9, 0xce, 0xd2, 0x75, 0xa2, 0xec, 0x9d, 0x7a, 0xa0, 0xd0, 0x90, 0x5c, 0x93, 0x40, 0x98,
+	0x79, 0x91, 0x33, 0x23, 0x06, 0xed, 0x5b, 0xb2, 0xda, 0x42, 0x8a, 0xfa, 0xf0, 0xc8, 0x23, 0x21,
+	0x76, 0x19, 0xa1, 0x81, 0x26, 0x1f, 0xaf, 0x5b, 0xab, 0xd1, 0x29, 0x54, 0x7c, 0x67, 0xf1, 0xd3,
+	0x23, 0x11, 0x73, 0x02, 0x17, 0x6b, 0x05, 0x43, 0x6a, 0x4a, 0xb6, 0xea, 0x3b, 0x8b, 0x8f, 0x62,
+	0xcb, 0xbc, 0x84, 0xda, 0x39, 0x76, 0x42, 0xbc, 0x36, 0xd9, 0x81, 0xd2, 0x9c, 0x92, 0x80, 0xdd,
+	0xc7, 0x63, 0xa2, 0x44, 0x15, 0x90, 0x6e, 0xb8, 0xb5, 0xaa, 0x2d, 0xdd, 0x98, 0x57, 0x50, 0x18,
+	0x91, 0x07, 0x4c, 0x10, 0xd2, 0xa1, 0x9c, 0x59, 0x95, 0xb9, 0xd5, 0xec, 0xdb, 0xec, 0x43, 0x65,
+	0x44, 0x58, 0x94, 0xbd, 0xc9, 0x1b, 0x28, 0x4e, 0x09, 0x4b, 0xdf, 0xe3, 0xe9, 0x2e, 0x7b, 0x44,
+	0x98, 0xcd, 0x25, 0xdd, 0xbf, 0x45, 0x28, 0x7d, 0x5f, 0xed, 0xa1, 0x31, 0x28, 0xc9, 0xd4, 0xa3,
+	0xc6, 0x6e, 0x41, 0x2e, 0x52, 0xba, 0x71, 0x58, 0x20, 0x1c, 0x0c, 0xa1, 0xb8, 0x9a, 0x77, 0xf4,
+	0x72, 0x57, 0xb9, 0x11, 0x24, 0xfd, 0xd5, 0xa1, 0xdf, 0x02, 0x33, 0x06, 0x25, 0x49, 0xc1, 0x3e,
+	0x4f, 0xb9, 0x00, 0xe9, 0xc6, 0x61, 0x81, 0x80, 0x9d, 0x43, 0x39, 0x0d, 0x10, 0x3a, 0xdd, 0x55,
+	0x6f, 0x85, 0x4b, 0x6f, 0x1c, 0x90, 0x64, 0xbc, 0xaf, 0xa0, 0x6e, 0x64, 0x0a, 0xbd, 0x3e, 0xa0,
+	0xcf, 0x45, 0xee, 0x38, 0x75, 0x0c, 0x27, 0x22, 0x18, 0x68, 0x5f, 0x4b, 0xb9, 0xcc, 0xec, 0xbb,
+	0xbf, 0xdc, 0x20, 0x8c, 0xe1, 0x44, 0x0c, 0xf0, 0x3e, 0x58, 0x7e, 0xb6, 0x8f, 0xc1, 0x06, 0x7d,
+	0x78, 0xe2, 0x52, 0x7f, 0x47, 0x34, 0x48, 0xe6, 0xe7, 0x42, 0xfa, 0x51, 0xe2, 0xdf, 0xff, 0xe4,
+	0xfa, 0xc5, 0x5a, 0xc2, 0xff, 0x4d, 0x14, 0x5e, 0xd4, 0xfb, 0x3f, 0x00, 0x84, 0x42, 0xac, 0x62,
+	0x7e, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// WorldClient is the client API for World service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WorldClient interface {
+	// Insert adds an entity, failing with ALREADY_EXISTS if its id is taken
+	Insert(ctx context.Context, in *InsertRequest, opts ...grpc.CallOption) (*InsertResponse, error)
+	// Move changes the bounds of an entity, failing with NOT_FOUND if it doesn't exist
+	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResponse, error)
+	// Remove removes an entity, failing with NOT_FOUND if it doesn't exist
+	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+	// QueryBox returns the entities whose bounds intersect the box
+	QueryBox(ctx context.Context, in *QueryBoxRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// QuerySphere returns the entities whose bounds intersect the sphere
+	QuerySphere(ctx context.Context, in *QuerySphereRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Raycast returns the entities the ray crosses with the distance where it enters them
+	Raycast(ctx context.Context, in *RaycastRequest, opts ...grpc.CallOption) (*HitsResponse, error)
+	// Nearest returns the k entities closest to the point
+	Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*HitsResponse, error)
+}
+
+type worldClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorldClient(cc grpc.ClientConnInterface) WorldClient {
+	return &worldClient{cc}
+}
+
+func (c *worldClient) Insert(ctx context.Context, in *InsertRequest, opts ...grpc.CallOption) (*InsertResponse, error) {
+	out := new(InsertResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/Insert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldClient) Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResponse, error) {
+	out := new(MoveResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/Move", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error) {
+	out := new(RemoveResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/Remove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldClient) QueryBox(ctx context.Context, in *QueryBoxRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/QueryBox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldClient) QuerySphere(ctx context.Context, in *QuerySphereRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/QuerySphere", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldClient) Raycast(ctx context.Context, in *RaycastRequest, opts ...grpc.CallOption) (*HitsResponse, error) {
+	out := new(HitsResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/Raycast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldClient) Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*HitsResponse, error) {
+	out := new(HitsResponse)
+	err := c.cc.Invoke(ctx, "/protometry.world.World/Nearest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorldServer is the server API for World service.
+type WorldServer interface {
+	// Insert adds an entity, failing with ALREADY_EXISTS if its id is taken
+	Insert(context.Context, *InsertRequest) (*InsertResponse, error)
+	// Move changes the bounds of an entity, failing with NOT_FOUND if it doesn't exist
+	Move(context.Context, *MoveRequest) (*MoveResponse, error)
+	// Remove removes an entity, failing with NOT_FOUND if it doesn't exist
+	Remove(context.Context, *RemoveRequest) (*RemoveResponse, error)
+	// QueryBox returns the entities whose bounds intersect the box
+	QueryBox(context.Context, *QueryBoxRequest) (*QueryResponse, error)
+	// QuerySphere returns the entities whose bounds intersect the sphere
+	QuerySphere(context.Context, *QuerySphereRequest) (*QueryResponse, error)
+	// Raycast returns the entities the ray crosses with the distance where it enters them
+	Raycast(context.Context, *RaycastRequest) (*HitsResponse, error)
+	// Nearest returns the k entities closest to the point
+	Nearest(context.Context, *NearestRequest) (*HitsResponse, error)
+}
+
+// UnimplementedWorldServer can be embedded to have forward compatible implementations.
+type UnimplementedWorldServer struct {
+}
+
+func (*UnimplementedWorldServer) Insert(ctx context.Context, req *InsertRequest) (*InsertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Insert not implemented")
+}
+func (*UnimplementedWorldServer) Move(ctx context.Context, req *MoveRequest) (*MoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Move not implemented")
+}
+func (*UnimplementedWorldServer) Remove(ctx context.Context, req *RemoveRequest) (*RemoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (*UnimplementedWorldServer) QueryBox(ctx context.Context, req *QueryBoxRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBox not implemented")
+}
+func (*UnimplementedWorldServer) QuerySphere(ctx context.Context, req *QuerySphereRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuerySphere not implemented")
+}
+func (*UnimplementedWorldServer) Raycast(ctx context.Context, req *RaycastRequest) (*HitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Raycast not implemented")
+}
+func (*UnimplementedWorldServer) Nearest(ctx context.Context, req *NearestRequest) (*HitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Nearest not implemented")
+}
+
+func RegisterWorldServer(s *grpc.Server, srv WorldServer) {
+	s.RegisterService(&_World_serviceDesc, srv)
+}
+
+func _World_Insert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).Insert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/Insert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).Insert(ctx, req.(*InsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _World_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/Move",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).Move(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _World_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/Remove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).Remove(ctx, req.(*RemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _World_QueryBox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBoxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).QueryBox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/QueryBox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).QueryBox(ctx, req.(*QueryBoxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _World_QuerySphere_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuerySphereRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).QuerySphere(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/QuerySphere",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).QuerySphere(ctx, req.(*QuerySphereRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _World_Raycast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RaycastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).Raycast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/Raycast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).Raycast(ctx, req.(*RaycastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _World_Nearest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NearestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServer).Nearest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protometry.world.World/Nearest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServer).Nearest(ctx, req.(*NearestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _World_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protometry.world.World",
+	HandlerType: (*WorldServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Insert",
+			Handler:    _World_Insert_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _World_Move_Handler,
+		},
+		{
+			MethodName: "Remove",
+			Handler:    _World_Remove_Handler,
+		},
+		{
+			MethodName: "QueryBox",
+			Handler:    _World_QueryBox_Handler,
+		},
+		{
+			MethodName: "QuerySphere",
+			Handler:    _World_QuerySphere_Handler,
+		},
+		{
+			MethodName: "Raycast",
+			Handler:    _World_Raycast_Handler,
+		},
+		{
+			MethodName: "Nearest",
+			Handler:    _World_Nearest_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "world.proto",
+}
@@ -2,4 +2,7 @@ module github.com/louis030195/protometry
 
 go 1.14
 
-require github.com/golang/protobuf v1.4.3
+require (
+	github.com/golang/protobuf v1.4.3
+	google.golang.org/grpc v1.43.0
+)
@@ -13,4 +13,28 @@ var (
 	ErrVectorInvalidDimension = errors.New("Vectors' dimensions are not of the expected size")
 	// ErrVectorInvalidIndex ...
 	ErrVectorInvalidIndex = errors.New("Invalid index")
+	// ErrMeshIncompleteTriangle ...
+	ErrMeshIncompleteTriangle = errors.New("Mesh triangle indices are not a multiple of 3")
+	// ErrMeshInvalidIndex ...
+	ErrMeshInvalidIndex = errors.New("Mesh triangle references a vertex out of range")
+	// ErrHullDegenerate ...
+	ErrHullDegenerate = errors.New("Points are coplanar or too close to it to build a convex hull with volume")
+	// ErrHullNonFinite ...
+	ErrHullNonFinite = errors.New("Convex hull points must be finite")
+	// ErrQuantizationInvalidBits ...
+	ErrQuantizationInvalidBits = errors.New("Quantization bits are out of range")
+	// ErrSnapshotUnknownBaseline ...
+	ErrSnapshotUnknownBaseline = errors.New("Snapshot delta refers to a baseline that was not received")
+	// ErrSnapshotMalformed ...
+	ErrSnapshotMalformed = errors.New("Snapshot delta is truncated or malformed")
+	// ErrVolumeUnknownType ...
+	ErrVolumeUnknownType = errors.New("Volume is not a sphere, capsule, box or mesh")
+	// ErrTextMalformed ...
+	ErrTextMalformed = errors.New("Text is not of the expected form")
+	// ErrWorldEntityExists ...
+	ErrWorldEntityExists = errors.New("Entity id is already taken")
+	// ErrWorldEntityNotFound ...
+	ErrWorldEntityNotFound = errors.New("Entity does not exist")
+	// ErrWorldInvalidRequest ...
+	ErrWorldInvalidRequest = errors.New("Request is missing a field or has an invalid value")
 )
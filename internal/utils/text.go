@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FormatFloat writes f in the shortest form that parses back to it
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// FormatTuple writes the values as "(a, b, c)"
+func FormatTuple(values ...float64) string {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for i, v := range values {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(FormatFloat(v))
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// SplitTuple returns the items of "(a, b, c)" without the spaces around them. Items may be tuples
+// themselves, only the commas outside of them separate the items
+func SplitTuple(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, ErrTextMalformed
+	}
+	s = s[1 : len(s)-1]
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var items []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, ErrTextMalformed
+			}
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, ErrTextMalformed
+	}
+	items = append(items, strings.TrimSpace(s[start:]))
+	for _, item := range items {
+		if item == "" {
+			return nil, ErrTextMalformed
+		}
+	}
+	return items, nil
+}
+
+// ParseFloat parses a number written by FormatFloat, or any form accepted by strconv.ParseFloat
+func ParseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, ErrTextMalformed
+	}
+	return f, nil
+}
+
+// ParseTuple parses "(a, b, c)" made of n numbers
+func ParseTuple(s string, n int) ([]float64, error) {
+	items, err := SplitTuple(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != n {
+		return nil, ErrTextMalformed
+	}
+	values := make([]float64, n)
+	for i, item := range items {
+		if values[i], err = ParseFloat(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// TrimName returns s without the name it starts with, and whether it did, for forms like "Name(a, b)"
+func TrimName(s, name string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, name) {
+		return s, false
+	}
+	return s[len(name):], true
+}
+
+// WriteFloat writes x to the state of a fmt.Formatter, formatted with the verb, its flags, width and
+// precision. %v and %s without precision give the form of FormatFloat
+func WriteFloat(f fmt.State, verb rune, x float64) {
+	precision, hasPrecision := f.Precision()
+	if verb == 'v' || verb == 's' {
+		if !hasPrecision {
+			io.WriteString(f, FormatFloat(x))
+			return
+		}
+		verb = 'g'
+	}
+	spec := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			spec += string(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		spec += strconv.Itoa(width)
+	}
+	if hasPrecision {
+		spec += "." + strconv.Itoa(precision)
+	}
+	fmt.Fprintf(f, spec+string(verb), x)
+}
+
+// WriteTuple writes the values as "(a, b, c)" to the state of a fmt.Formatter, each one with WriteFloat
+func WriteTuple(f fmt.State, verb rune, values ...float64) {
+	io.WriteString(f, "(")
+	for i, v := range values {
+		if i > 0 {
+			io.WriteString(f, ", ")
+		}
+		WriteFloat(f, verb, v)
+	}
+	io.WriteString(f, ")")
+}